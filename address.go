@@ -114,6 +114,17 @@ func (address Address) LatLon() string {
 	return formatCoordinatePair(address.Latitude, address.Longitude)
 }
 
+// GeoURI returns the coordinates of this Address as an RFC 5870 "geo" URI,
+// or an empty string if the Address has not been geocoded.
+func (address Address) GeoURI() string {
+
+	if !address.HasGeocode() {
+		return ""
+	}
+
+	return address.GeoPoint().GeoURI()
+}
+
 // SetPoint copies the longitude and latitude from the given Point into this Address.
 func (address *Address) SetPoint(point Point) {
 	address.Longitude = point.Longitude
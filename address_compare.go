@@ -0,0 +1,366 @@
+package geo
+
+import (
+	"math"
+	"strings"
+	"unicode"
+)
+
+// AddressDuplicateSimilarity is the Similarity at (or above) which Deduplicate
+// treats two addresses as the same place
+const AddressDuplicateSimilarity = 0.85
+
+// addressProximityHalfDistance is the distance (in meters) at which the proximity
+// of two addresses' coordinates falls to one half
+const addressProximityHalfDistance = 100.0
+
+// addressAbbreviations expands the abbreviations that are commonly used in streets
+// and units, so that "123 Main St." matches "123 Main Street". Both sides of a
+// comparison are expanded the same way, so ambiguous abbreviations (like "St" for
+// "Saint") still match each other.
+var addressAbbreviations = map[string]string{
+	"st": "street", "str": "strasse", "ave": "avenue", "av": "avenue", "rd": "road",
+	"blvd": "boulevard", "bd": "boulevard", "dr": "drive", "ln": "lane", "ct": "court",
+	"pl": "place", "tce": "terrace", "ter": "terrace", "pkwy": "parkway", "hwy": "highway",
+	"cir": "circle", "cres": "crescent", "sq": "square", "trl": "trail", "gdns": "gardens",
+	"pde": "parade", "esp": "esplanade", "mt": "mount", "ft": "fort", "hts": "heights",
+	"ctr": "center", "jct": "junction", "expy": "expressway", "fwy": "freeway", "rte": "route",
+	"n": "north", "s": "south", "e": "east", "w": "west",
+	"ne": "northeast", "nw": "northwest", "se": "southeast", "sw": "southwest",
+	"apt": "apartment", "ste": "suite", "fl": "floor", "rm": "room", "bldg": "building",
+	"lvl": "level", "whg": "wohnung",
+}
+
+// addressComparison holds the normalized values of an Address, for comparing it with other addresses
+type addressComparison struct {
+	street      string // Street, without its unit
+	houseNumber string // First word of the street that contains a digit
+	unit        string
+	locality    string
+	region      string
+	subdivision string // Full ISO 3166-2 code of the region, when it is known
+	postalCode  string
+	country     string
+	position    Position
+	hasPosition bool
+}
+
+// Equal returns TRUE if two addresses are the same after normalization: ignoring case,
+// accents, punctuation, and common abbreviations ("St." and "Street"), and converting
+// countries, regions, and postal codes into their standard forms. Units written on the
+// first street line ("123 Main St, Suite 4") match units written on the second. Addresses
+// with only a Formatted value are parsed first. Names, coordinates, and time zones are
+// not compared.
+func (address Address) Equal(other Address) bool {
+	return address.comparison().equal(other.comparison())
+}
+
+// Similarity returns a score from 0 (different places) to 1 (the same place) that
+// combines the normalized text of two addresses (like Equal) with the distance between
+// their coordinates. Fields that only one address has are ignored, except for the
+// street, so "123 Main St." is very similar to "123 Main Street, Suite 4". Addresses
+// with only coordinates are compared by distance alone. Different house numbers,
+// countries, or known ISO 3166-2 regions mean different places. Use
+// AddressDuplicateSimilarity as a threshold for duplicates.
+func (address Address) Similarity(other Address) float64 {
+	return address.comparison().similarity(other.comparison())
+}
+
+// Deduplicate groups addresses that describe the same place, such as "123 Main St." and
+// "123 Main Street, Suite 4". It returns clusters of indexes into addresses: every index
+// is in exactly one cluster, and clusters (and the indexes in them) are in the order of
+// the input. Two addresses are in the same cluster when their Similarity is at least
+// AddressDuplicateSimilarity, or when they are both similar to a third address.
+// Deduplicate compares every pair of addresses, so it suits lists of up to a few
+// thousand addresses.
+func Deduplicate(addresses []Address) [][]int {
+
+	comparisons := make([]addressComparison, len(addresses))
+
+	for index, address := range addresses {
+		comparisons[index] = address.comparison()
+	}
+
+	// Join similar addresses into clusters (a disjoint-set forest), using the first index as each cluster's root
+	parents := make([]int, len(addresses))
+
+	for index := range parents {
+		parents[index] = index
+	}
+
+	root := func(index int) int {
+		for parents[index] != index {
+			parents[index] = parents[parents[index]]
+			index = parents[index]
+		}
+		return index
+	}
+
+	for first := range comparisons {
+		for second := first + 1; second < len(comparisons); second++ {
+
+			firstRoot, secondRoot := root(first), root(second)
+
+			if firstRoot == secondRoot {
+				continue
+			}
+
+			if comparisons[first].similarity(comparisons[second]) >= AddressDuplicateSimilarity {
+				parents[max(firstRoot, secondRoot)] = min(firstRoot, secondRoot)
+			}
+		}
+	}
+
+	// Collect the clusters in order
+	var result [][]int
+	clusters := make(map[int]int)
+
+	for index := range addresses {
+
+		cluster, ok := clusters[root(index)]
+
+		if !ok {
+			cluster = len(result)
+			clusters[root(index)] = cluster
+			result = append(result, nil)
+		}
+
+		result[cluster] = append(result[cluster], index)
+	}
+
+	return result
+}
+
+// comparison returns the normalized values of this Address
+func (address Address) comparison() addressComparison {
+
+	// Parse addresses that only have a formatted value
+	if (address.Street1 == "") && (address.Locality == "") && (address.PostalCode == "") && (address.Formatted != "") {
+		if parsed, err := ParseAddress(address.Formatted, address.Country); err == nil {
+			parsed.Latitude, parsed.Longitude = address.Latitude, address.Longitude
+			address = parsed
+		}
+	}
+
+	address.Normalize()
+
+	// Units are sometimes written on the first line, as in "123 Main St, Suite 4" or "123 Main St Apt 4"
+	street, unit := address.Street1, address.Street2
+
+	if before, after, found := strings.Cut(street, ","); found {
+		street, unit = before, after+" "+unit
+	} else if match := addressInlineUnit.FindStringSubmatch(street); match != nil {
+		street, unit = match[1], match[2]+" "+unit
+	}
+
+	postalCode, err := NormalizePostalCode(address.Country, address.PostalCode)
+
+	if err != nil {
+		postalCode = address.PostalCode
+	}
+
+	result := addressComparison{
+		street:      addressCompareKey(street),
+		unit:        addressCompareKey(unit),
+		locality:    addressCompareKey(address.Locality),
+		region:      addressCompareKey(address.Region),
+		postalCode:  strings.ReplaceAll(addressCompareKey(postalCode), " ", ""),
+		country:     addressCompareKey(address.Country),
+		position:    address.GeoPoint().Position,
+		hasPosition: address.HasGeocode() && !math.IsNaN(address.Latitude) && !math.IsNaN(address.Longitude),
+	}
+
+	if subdivision, ok := LookupSubdivision(address.Country, address.Region); ok && ((address.Country == "") || (subdivision.Country == address.Country)) {
+		result.subdivision = subdivision.Code
+	}
+
+	for word := range strings.FieldsSeq(result.street) {
+		if strings.ContainsFunc(word, unicode.IsDigit) {
+			result.houseNumber = word
+			break
+		}
+	}
+
+	return result
+}
+
+// equal returns TRUE if the text of two comparisons is the same
+func (comparison addressComparison) equal(other addressComparison) bool {
+	return (comparison.street == other.street) &&
+		(comparison.unit == other.unit) &&
+		(comparison.locality == other.locality) &&
+		(comparison.region == other.region) &&
+		(comparison.postalCode == other.postalCode) &&
+		(comparison.country == other.country)
+}
+
+// similarity returns a score from 0 to 1 for how similar two comparisons are
+func (comparison addressComparison) similarity(other addressComparison) float64 {
+
+	// Addresses in different countries are never the same
+	if (comparison.country != "") && (other.country != "") && (comparison.country != other.country) {
+		return 0
+	}
+
+	// Neither are addresses in different states or provinces
+	if (comparison.subdivision != "") && (other.subdivision != "") && (comparison.subdivision != other.subdivision) {
+		return 0
+	}
+
+	text, hasText := comparison.textSimilarity(other)
+	hasPositions := comparison.hasPosition && other.hasPosition
+
+	var proximity float64
+
+	if hasPositions {
+		distance := comparison.position.SlantDistance(other.position)
+		proximity = math.Pow(0.5, distance/addressProximityHalfDistance)
+	}
+
+	switch {
+
+	case hasText && hasPositions:
+		return 0.75*text + 0.25*proximity
+
+	case hasText:
+		return text
+
+	case hasPositions:
+		return proximity
+	}
+
+	return 0
+}
+
+// textSimilarity returns a score from 0 to 1 for how similar the text of two comparisons
+// is. It returns FALSE if they have no fields in common to compare.
+func (comparison addressComparison) textSimilarity(other addressComparison) (float64, bool) {
+
+	// Addresses that only have coordinates have no text to compare
+	if !comparison.hasText() || !other.hasText() {
+		return 0, false
+	}
+
+	var score float64
+	var weight float64
+
+	// compare adds the similarity of one field, when both addresses have it
+	compare := func(value string, otherValue string, fieldWeight float64, similarity func(string, string) float64) {
+		if (value != "") && (otherValue != "") {
+			score += fieldWeight * similarity(value, otherValue)
+			weight += fieldWeight
+		}
+	}
+
+	// The street is the most important field, and is a mismatch when only one address has it
+	switch {
+
+	case (comparison.street == "") && (other.street == ""):
+
+	case (comparison.houseNumber != "") && (other.houseNumber != "") && (comparison.houseNumber != other.houseNumber):
+		weight += 0.45
+
+	default:
+		score += 0.45 * addressTextSimilarity(comparison.street, other.street)
+		weight += 0.45
+	}
+
+	compare(comparison.unit, other.unit, 0.05, addressTextSimilarity)
+	compare(comparison.locality, other.locality, 0.2, addressTextSimilarity)
+	compare(comparison.region, other.region, 0.1, addressExactSimilarity)
+	compare(comparison.postalCode, other.postalCode, 0.2, addressPostalCodeSimilarity)
+
+	if weight == 0 {
+		return 0, false
+	}
+
+	return score / weight, true
+}
+
+// hasText returns TRUE if this comparison has any text besides its country
+func (comparison addressComparison) hasText() bool {
+	return (comparison.street != "") ||
+		(comparison.unit != "") ||
+		(comparison.locality != "") ||
+		(comparison.region != "") ||
+		(comparison.postalCode != "")
+}
+
+// addressCompareKey normalizes a value for comparisons by removing case, accents, and
+// punctuation, and by expanding common abbreviations
+func addressCompareKey(value string) string {
+
+	words := strings.FieldsFunc(strings.ReplaceAll(iso3166Key(value), "ß", "ss"), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+
+	for index, word := range words {
+
+		if expanded, ok := addressAbbreviations[word]; ok {
+			words[index] = expanded
+			continue
+		}
+
+		// German streets are often abbreviated, as in "Hauptstr."
+		if (len(word) > 3) && strings.HasSuffix(word, "str") {
+			words[index] = word + "asse"
+		}
+	}
+
+	return strings.Join(words, " ")
+}
+
+// addressTextSimilarity returns the Sørensen-Dice coefficient of the pairs of
+// characters in two values, which tolerates typos and missing words
+func addressTextSimilarity(value string, other string) float64 {
+
+	if value == other {
+		return 1
+	}
+
+	runes, otherRunes := []rune(value), []rune(other)
+
+	if (len(runes) < 2) || (len(otherRunes) < 2) {
+		return 0
+	}
+
+	pairs := make(map[[2]rune]int, len(runes))
+
+	for index := range len(runes) - 1 {
+		pairs[[2]rune{runes[index], runes[index+1]}]++
+	}
+
+	var matches int
+
+	for index := range len(otherRunes) - 1 {
+		pair := [2]rune{otherRunes[index], otherRunes[index+1]}
+
+		if pairs[pair] > 0 {
+			pairs[pair]--
+			matches++
+		}
+	}
+
+	return 2 * float64(matches) / float64(len(runes)+len(otherRunes)-2)
+}
+
+// addressExactSimilarity returns 1 if two values are the same, and 0 otherwise
+func addressExactSimilarity(value string, other string) float64 {
+
+	if value == other {
+		return 1
+	}
+
+	return 0
+}
+
+// addressPostalCodeSimilarity returns 1 if two postal codes are the same, or if one
+// extends the other (as ZIP+4 codes extend ZIP codes), and 0 otherwise
+func addressPostalCodeSimilarity(value string, other string) float64 {
+
+	if strings.HasPrefix(value, other) || strings.HasPrefix(other, value) {
+		return 1
+	}
+
+	return 0
+}
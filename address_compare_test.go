@@ -0,0 +1,162 @@
+package geo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddress_Equal(t *testing.T) {
+
+	tests := []struct {
+		address  Address
+		other    Address
+		expected bool
+	}{
+		{
+			Address{Street1: "123 Main St.", Locality: "Springfield", Region: "IL", PostalCode: "62701", Country: "US"},
+			Address{Street1: "123 MAIN STREET", Locality: "springfield", Region: "Illinois", PostalCode: "62701", Country: "United States"},
+			true,
+		},
+		{
+			Address{Street1: "1600 Pennsylvania Ave NW", Locality: "Washington", Region: "DC", Country: "US"},
+			Address{Street1: "1600 Pennsylvania Avenue Northwest", Locality: "Washington", Region: "District of Columbia", Country: "US"},
+			true,
+		},
+		{
+			Address{Street1: "Hauptstr. 5", Locality: "München", PostalCode: "80331", Country: "DE"},
+			Address{Street1: "Hauptstraße 5", Locality: "Munchen", PostalCode: "80331", Country: "Germany"},
+			true,
+		},
+		{
+			Address{Street1: "10 Downing St", Locality: "London", PostalCode: "sw1a2aa", Country: "GB"},
+			Address{Street1: "10 Downing Street", Locality: "London", PostalCode: "SW1A 2AA", Country: "GB"},
+			true,
+		},
+		{
+			// Units on the first line match units on the second
+			Address{Street1: "123 Main St, Suite 4", Locality: "Springfield"},
+			Address{Street1: "123 Main Street", Street2: "Ste. 4", Locality: "Springfield"},
+			true,
+		},
+		{
+			Address{Street1: "123 Main St Apt 4B"},
+			Address{Street1: "123 Main Street", Street2: "Apartment 4B"},
+			true,
+		},
+		{
+			// Formatted addresses are parsed
+			Address{Formatted: "123 Main St., Springfield, IL 62701"},
+			Address{Street1: "123 Main Street", Locality: "Springfield", Region: "IL", PostalCode: "62701", Country: "US"},
+			true,
+		},
+		{
+			// Names and coordinates are not compared
+			Address{Name: "Joe's Diner", Street1: "123 Main St", Latitude: 39.78, Longitude: -89.65},
+			Address{Name: "Joe's", Street1: "123 Main St"},
+			true,
+		},
+		{
+			Address{Street1: "123 Main St."},
+			Address{Street1: "123 Main Street", Street2: "Suite 4"},
+			false,
+		},
+		{
+			Address{Street1: "123 Main St", Locality: "Springfield"},
+			Address{Street1: "125 Main St", Locality: "Springfield"},
+			false,
+		},
+		{
+			Address{Street1: "123 Main St", Country: "US"},
+			Address{Street1: "123 Main St", Country: "CA"},
+			false,
+		},
+	}
+
+	for _, test := range tests {
+		require.Equal(t, test.expected, test.address.Equal(test.other), test)
+		require.Equal(t, test.expected, test.other.Equal(test.address), test)
+	}
+}
+
+func TestAddress_Similarity(t *testing.T) {
+
+	springfield := Address{Street1: "123 Main St.", Locality: "Springfield", Region: "IL", PostalCode: "62701", Country: "US", Latitude: 39.7817, Longitude: -89.6501}
+
+	tests := []struct {
+		other   Address
+		minimum float64
+		maximum float64
+	}{
+		// Duplicates
+		{Address{Street1: "123 Main Street, Suite 4", Locality: "Springfield", Region: "Illinois", Country: "US"}, 0.99, 1},
+		{Address{Street1: "123 Main St", Locality: "Springfeld", PostalCode: "62701-1234", Country: "US"}, 0.9, 1},
+		{Address{Formatted: "123 Main St, Springfield, IL 62701, USA", Latitude: 39.7818, Longitude: -89.6502}, 0.95, 1},
+		{Address{Street1: "123 Main St", Latitude: 39.7820, Longitude: -89.6500}, 0.9, 1},
+		{Address{Latitude: 39.7817, Longitude: -89.6501}, 1, 1},
+
+		// Different places
+		{Address{Street1: "125 Main St", Locality: "Springfield", Region: "IL", Country: "US"}, 0, 0.6},
+		{Address{Street1: "123 Main St", Locality: "Springfield", Region: "MA", PostalCode: "01103", Country: "US", Latitude: 42.1015, Longitude: -72.5898}, 0, 0.6},
+		{Address{Street1: "123 Main St", Locality: "Springfield", Country: "CA"}, 0, 0},
+		{Address{Locality: "Springfield", Region: "IL", Country: "US"}, 0, 0.7},
+		{Address{Street1: "900 Elm Ave", Locality: "Springfield", Region: "IL", Country: "US"}, 0, 0.6},
+		{Address{Latitude: 39.8, Longitude: -89.7}, 0, 0.01},
+		{Address{}, 0, 0},
+	}
+
+	for _, test := range tests {
+
+		similarity := springfield.Similarity(test.other)
+		require.GreaterOrEqual(t, similarity, test.minimum, test.other)
+		require.LessOrEqual(t, similarity, test.maximum, test.other)
+
+		// Similarity is symmetric
+		require.InDelta(t, similarity, test.other.Similarity(springfield), 1e-9, test.other)
+	}
+}
+
+func TestDeduplicate(t *testing.T) {
+
+	addresses := []Address{
+		{Street1: "123 Main St.", Locality: "Springfield", Country: "US"},
+		{Street1: "900 Elm Ave", Locality: "Springfield", Country: "US"},
+		{Street1: "123 Main Street, Suite 4", Locality: "Springfield", Country: "US"},
+		{Formatted: "900 Elm Avenue, Springfield, IL 62701"},
+		{Street1: "125 Main St", Locality: "Springfield", Country: "US"},
+		{Name: "Joe's Diner", Street1: "123 MAIN ST", Locality: "springfield"},
+	}
+
+	require.Equal(t, [][]int{{0, 2, 5}, {1, 3}, {4}}, Deduplicate(addresses))
+}
+
+func TestAddress_Similarity_Regions(t *testing.T) {
+
+	illinois := Address{Street1: "123 Main St", Locality: "Springfield", Region: "IL", Country: "US"}
+	massachusetts := Address{Street1: "123 Main St", Locality: "Springfield", Region: "MA", Country: "US"}
+
+	// Addresses in different states are never the same, even when everything else matches
+	require.Equal(t, float64(0), illinois.Similarity(massachusetts))
+	require.Equal(t, float64(0), massachusetts.Similarity(illinois))
+	require.Equal(t, [][]int{{0}, {1}}, Deduplicate([]Address{illinois, massachusetts}))
+
+	// Regions may be written as names or full codes
+	require.Equal(t, float64(0), illinois.Similarity(Address{Street1: "123 Main St", Locality: "Springfield", Region: "Massachusetts", Country: "US"}))
+	require.Equal(t, float64(0), illinois.Similarity(Address{Street1: "123 Main St", Locality: "Springfield", Region: "US-MA"}))
+	require.Greater(t, illinois.Similarity(Address{Street1: "123 Main St", Locality: "Springfield", Region: "Illinois", Country: "US"}), 0.99)
+}
+
+func TestDeduplicate_Empty(t *testing.T) {
+	require.Nil(t, Deduplicate(nil))
+	require.Equal(t, [][]int{{0}}, Deduplicate([]Address{{}}))
+}
+
+func TestAddressCompareKey(t *testing.T) {
+	require.Equal(t, "123 main street", addressCompareKey("123  Main St."))
+	require.Equal(t, "1600 pennsylvania avenue northwest", addressCompareKey("1600 Pennsylvania Ave. N.W."))
+	require.Equal(t, "hauptstrasse 5", addressCompareKey("Hauptstr. 5"))
+	require.Equal(t, "hauptstrasse 5", addressCompareKey("HAUPTSTRASSE 5"))
+	require.Equal(t, "rue de l eglise", addressCompareKey("Rue de l'Église"))
+	require.Equal(t, "suite 4", addressCompareKey("Suite #4"))
+	require.Equal(t, "", addressCompareKey(" , "))
+}
@@ -0,0 +1,118 @@
+package geo
+
+// The dictionaries in this file are used by ParseAddress. Keys are
+// normalized with addressKey (lower case, without periods).
+
+// addressCountryNames maps the ways that a supported country may be written
+// at the end of an address to its ISO 3166-1 alpha-2 code. "CA" and "DE" are
+// left out because they are more likely to be California and Delaware.
+var addressCountryNames = map[string]string{
+	"us":                       "US",
+	"usa":                      "US",
+	"united states":            "US",
+	"united states of america": "US",
+	"america":                  "US",
+	"canada":                   "CA",
+	"uk":                       "GB",
+	"gb":                       "GB",
+	"united kingdom":           "GB",
+	"great britain":            "GB",
+	"england":                  "GB",
+	"scotland":                 "GB",
+	"wales":                    "GB",
+	"northern ireland":         "GB",
+	"germany":                  "DE",
+	"deutschland":              "DE",
+	"fr":                       "FR",
+	"france":                   "FR",
+	"au":                       "AU",
+	"australia":                "AU",
+	"jp":                       "JP",
+	"japan":                    "JP",
+	"nippon":                   "JP",
+	"日本":                       "JP",
+	"日本国":                      "JP",
+}
+
+// addressRegions lists the states, provinces, territories, and prefectures of
+// the countries whose addresses include a region before the postal code.
+var addressRegions = map[string]map[string]bool{
+	"US": addressDictionary(
+		"al", "alabama", "ak", "alaska", "az", "arizona", "ar", "arkansas", "ca", "california",
+		"co", "colorado", "ct", "connecticut", "de", "delaware", "dc", "district of columbia",
+		"fl", "florida", "ga", "georgia", "hi", "hawaii", "id", "idaho", "il", "illinois",
+		"in", "indiana", "ia", "iowa", "ks", "kansas", "ky", "kentucky", "la", "louisiana",
+		"me", "maine", "md", "maryland", "ma", "massachusetts", "mi", "michigan", "mn", "minnesota",
+		"ms", "mississippi", "mo", "missouri", "mt", "montana", "ne", "nebraska", "nv", "nevada",
+		"nh", "new hampshire", "nj", "new jersey", "nm", "new mexico", "ny", "new york",
+		"nc", "north carolina", "nd", "north dakota", "oh", "ohio", "ok", "oklahoma", "or", "oregon",
+		"pa", "pennsylvania", "ri", "rhode island", "sc", "south carolina", "sd", "south dakota",
+		"tn", "tennessee", "tx", "texas", "ut", "utah", "vt", "vermont", "va", "virginia",
+		"wa", "washington", "wv", "west virginia", "wi", "wisconsin", "wy", "wyoming",
+		"as", "american samoa", "gu", "guam", "mp", "northern mariana islands", "pr", "puerto rico",
+		"vi", "virgin islands", "aa", "ae", "ap",
+	),
+	"CA": addressDictionary(
+		"ab", "alberta", "bc", "british columbia", "colombie-britannique", "mb", "manitoba",
+		"nb", "new brunswick", "nouveau-brunswick", "nl", "newfoundland and labrador", "terre-neuve-et-labrador",
+		"ns", "nova scotia", "nouvelle-écosse", "nt", "northwest territories", "nu", "nunavut",
+		"on", "ontario", "pe", "prince edward island", "qc", "quebec", "québec", "sk", "saskatchewan",
+		"yt", "yukon",
+	),
+	"AU": addressDictionary(
+		"nsw", "new south wales", "vic", "victoria", "qld", "queensland", "sa", "south australia",
+		"wa", "western australia", "tas", "tasmania", "nt", "northern territory",
+		"act", "australian capital territory", "jbt", "jervis bay territory",
+	),
+	"JP": addressDictionary(
+		"hokkaido", "aomori", "iwate", "miyagi", "akita", "yamagata", "fukushima", "ibaraki", "tochigi",
+		"gunma", "saitama", "chiba", "tokyo", "tokyo-to", "kanagawa", "niigata", "toyama", "ishikawa",
+		"fukui", "yamanashi", "nagano", "gifu", "shizuoka", "aichi", "mie", "shiga", "kyoto", "kyoto-fu",
+		"osaka", "osaka-fu", "hyogo", "nara", "wakayama", "tottori", "shimane", "okayama", "hiroshima",
+		"yamaguchi", "tokushima", "kagawa", "ehime", "kochi", "fukuoka", "saga", "nagasaki", "kumamoto",
+		"oita", "miyazaki", "kagoshima", "okinawa",
+	),
+}
+
+// addressPrefectures lists Japan's 47 prefectures as they are written in Japanese,
+// where they come first (after the postal code) in an address.
+var addressPrefectures = []string{
+	"北海道", "青森県", "岩手県", "宮城県", "秋田県", "山形県", "福島県", "茨城県", "栃木県", "群馬県",
+	"埼玉県", "千葉県", "東京都", "神奈川県", "新潟県", "富山県", "石川県", "福井県", "山梨県", "長野県",
+	"岐阜県", "静岡県", "愛知県", "三重県", "滋賀県", "京都府", "大阪府", "兵庫県", "奈良県", "和歌山県",
+	"鳥取県", "島根県", "岡山県", "広島県", "山口県", "徳島県", "香川県", "愛媛県", "高知県", "福岡県",
+	"佐賀県", "長崎県", "熊本県", "大分県", "宮崎県", "鹿児島県", "沖縄県",
+}
+
+// addressStreetTypes are the (English) words that end a street name. They are used
+// to find where the street ends when an address has no commas.
+var addressStreetTypes = addressDictionary(
+	"street", "st", "avenue", "ave", "av", "road", "rd", "boulevard", "blvd", "drive", "dr",
+	"lane", "ln", "way", "court", "ct", "place", "pl", "terrace", "tce", "parkway", "pkwy",
+	"highway", "hwy", "circle", "cir", "crescent", "cres", "close", "square", "sq", "trail", "trl",
+	"row", "mews", "grove", "gardens", "gdns", "walk", "hill", "parade", "pde", "esplanade",
+)
+
+// addressDirections are the compass directions that may follow a street type, as in "Pennsylvania Ave NW"
+var addressDirections = addressDictionary(
+	"n", "s", "e", "w", "ne", "nw", "se", "sw", "north", "south", "east", "west",
+	"northeast", "northwest", "southeast", "southwest",
+)
+
+// addressGermanStreets are German street words, which help to tell German addresses from French ones
+var addressGermanStreets = []string{"straße", "strasse", "str.", "weg", "platz", "allee", "gasse", "ring", "damm", "ufer", "chaussee"}
+
+// addressFrenchStreets are French street words, which help to tell French addresses from German ones
+var addressFrenchStreets = []string{"rue", "avenue", "boulevard", "bd", "place", "chemin", "allée", "quai", "impasse", "route", "cours"}
+
+// addressDictionary returns a set of (normalized) words
+func addressDictionary(values ...string) map[string]bool {
+
+	result := make(map[string]bool, len(values))
+
+	for _, value := range values {
+		result[addressKey(value)] = true
+	}
+
+	return result
+}
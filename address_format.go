@@ -0,0 +1,216 @@
+package geo
+
+import (
+	_ "embed"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// Styles supported by Address.Format
+const (
+	// AddressFormatMultiLine writes each part of the address on its own line, in the order used by its country
+	AddressFormatMultiLine = "multiline"
+
+	// AddressFormatSingleLine writes the address on a single line, such as "10 Downing Street, London, SW1A 2AA, United Kingdom"
+	AddressFormatSingleLine = "singleline"
+
+	// AddressFormatPostal writes the address on multiple lines for an envelope, with the
+	// capital letters that the country's postal service asks for (such as "PARIS" or "SYDNEY NSW 2000")
+	AddressFormatPostal = "postal"
+)
+
+// addressTemplate describes how addresses are written in a country, in the style of
+// OpenCage's address-formatting templates. Each line contains {placeholders} named
+// after the AddressProperty* constants, plus {countryName} for the country's name.
+type addressTemplate struct {
+	lines     []string // Template for each line of the address
+	separator string   // Separator between lines in single-line output
+	uppercase []string // Properties written in capital letters by AddressFormatPostal ("*" means everything)
+}
+
+//go:embed data/address-formats.tsv
+var addressFormatsTSV string
+
+// addressTemplates parses the embedded address templates the first time they are used.
+// They are keyed by ISO 3166-1 alpha-2 code, plus "default" for addresses without a known
+// country, and "JP-Jpan" for Japanese addresses that are written in Japanese.
+var addressTemplates = sync.OnceValue(func() map[string]addressTemplate {
+
+	rows := iso3166Rows(addressFormatsTSV, 2)
+	result := make(map[string]addressTemplate, len(rows))
+
+	// Read the templates first, and then the rows that copy them
+	for _, fields := range rows {
+
+		if !strings.Contains(fields[1], "{") {
+			continue
+		}
+
+		template := addressTemplate{
+			lines:     strings.Split(fields[1], "|"),
+			separator: ", ",
+		}
+
+		if (len(fields) > 2) && (fields[2] != "") {
+			template.uppercase = strings.Split(fields[2], ",")
+		}
+
+		if (len(fields) > 3) && (fields[3] != "") {
+			separator, err := strconv.Unquote(fields[3])
+
+			if err != nil {
+				panic("geo: invalid address template separator for " + fields[0])
+			}
+
+			template.separator = separator
+		}
+
+		result[fields[0]] = template
+	}
+
+	for _, fields := range rows {
+
+		if strings.Contains(fields[1], "{") {
+			continue
+		}
+
+		template, ok := result[fields[1]]
+
+		if !ok {
+			panic("geo: unknown address template " + fields[1] + " for " + fields[0])
+		}
+
+		result[fields[0]] = template
+	}
+
+	return result
+})
+
+// addressPlaceholder matches a {placeholder} in an address template
+var addressPlaceholder = regexp.MustCompile(`\{([A-Za-z0-9]+)\}`)
+
+// Format returns this Address as human-readable text (one of the AddressFormat* constants),
+// using the template for its Country. The Name, time zone, and coordinates are not
+// included. Unknown styles fall back to AddressFormatMultiLine.
+func (address Address) Format(style string) string {
+
+	template := address.formatTemplate()
+	values := address.formatValues()
+
+	// Apply the postal service's capitalization rules
+	if style == AddressFormatPostal {
+		for _, name := range template.uppercase {
+			for key, value := range values {
+				if (name == "*") || (name == key) {
+					values[key] = strings.ToUpper(value)
+				}
+			}
+		}
+	}
+
+	// Fill in each line, and remove anything left over from empty values
+	lines := make([]string, 0, len(template.lines))
+
+	for _, line := range template.lines {
+
+		line = addressPlaceholder.ReplaceAllStringFunc(line, func(placeholder string) string {
+			return values[placeholder[1:len(placeholder)-1]]
+		})
+
+		// Skip lines without any values (such as "〒" without a postal code)
+		if line = cleanFormattedLine(line); strings.IndexFunc(line, isAddressContent) >= 0 {
+			lines = append(lines, line)
+		}
+	}
+
+	if style == AddressFormatSingleLine {
+		return strings.Join(lines, template.separator)
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// UpdateFormatted regenerates the Formatted value of this Address from its parsed
+// fields, as a single line. Unlike SetString("formatted", ...), this does not reset
+// any geocoded values. If the Address has no parsed fields then Formatted is unchanged.
+func (address *Address) UpdateFormatted() {
+
+	if !address.HasAddress() {
+		return
+	}
+
+	address.Formatted = address.Format(AddressFormatSingleLine)
+}
+
+// formatTemplate returns the template for this Address's country
+func (address Address) formatTemplate() addressTemplate {
+
+	country := addressCountryCode(address.Country)
+	templates := addressTemplates()
+
+	// Japanese addresses may be written in either Japanese or English
+	if (country == "JP") && (containsJapanese(address.Region) || containsJapanese(address.Locality)) {
+		country = "JP-Jpan"
+	}
+
+	if template, ok := templates[country]; ok {
+		return template
+	}
+
+	return templates["default"]
+}
+
+// formatValues returns the values that can be used in an address template
+func (address Address) formatValues() map[string]string {
+
+	countryName := address.Country
+
+	if country, ok := LookupCountry(addressCountryCode(address.Country)); ok {
+		countryName = country.DisplayName()
+	}
+
+	return map[string]string{
+		AddressPropertyStreet1:    address.Street1,
+		AddressPropertyStreet2:    address.Street2,
+		AddressPropertyLocality:   address.Locality,
+		AddressPropertyRegion:     address.Region,
+		AddressPropertyPostalCode: address.PostalCode,
+		AddressPropertyCountry:    address.Country,
+		"countryName":             countryName,
+	}
+}
+
+// cleanFormattedLine removes the separators and whitespace that are left behind
+// when some of the values in a template line are empty, such as "Springfield, " or "São Paulo - "
+func cleanFormattedLine(line string) string {
+
+	line = strings.Join(strings.Fields(line), " ")
+	line = strings.ReplaceAll(line, " ,", ",")
+
+	for strings.Contains(line, ",,") {
+		line = strings.ReplaceAll(line, ",,", ",")
+	}
+
+	return strings.Trim(line, ", -")
+}
+
+// isAddressContent returns TRUE for characters that are part of an address value,
+// rather than a template's punctuation (like "〒")
+func isAddressContent(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// containsJapanese returns TRUE if a value includes any Japanese characters
+func containsJapanese(value string) bool {
+
+	for _, r := range value {
+		if unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,257 @@
+package geo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddress_Format_US(t *testing.T) {
+
+	address := Address{
+		Name:       "Empire State Building",
+		Street1:    "350 Fifth Avenue",
+		Street2:    "Suite 3300",
+		Locality:   "New York",
+		Region:     "NY",
+		PostalCode: "10118",
+		Country:    "US",
+	}
+
+	require.Equal(t, "350 Fifth Avenue\nSuite 3300\nNew York, NY 10118\nUnited States", address.Format(AddressFormatMultiLine))
+	require.Equal(t, "350 Fifth Avenue, Suite 3300, New York, NY 10118, United States", address.Format(AddressFormatSingleLine))
+	require.Equal(t, "350 FIFTH AVENUE\nSUITE 3300\nNEW YORK, NY 10118\nUNITED STATES", address.Format(AddressFormatPostal))
+}
+
+func TestAddress_Format_GB(t *testing.T) {
+
+	address := Address{
+		Street1:    "10 Downing Street",
+		Street2:    "Flat 3",
+		Locality:   "London",
+		PostalCode: "SW1A 2AA",
+		Country:    "GB",
+	}
+
+	require.Equal(t, "Flat 3\n10 Downing Street\nLondon\nSW1A 2AA\nUnited Kingdom", address.Format(AddressFormatMultiLine))
+	require.Equal(t, "Flat 3\n10 Downing Street\nLONDON\nSW1A 2AA\nUNITED KINGDOM", address.Format(AddressFormatPostal))
+}
+
+func TestAddress_Format_DE(t *testing.T) {
+
+	address := Address{
+		Street1:    "Hauptstraße 5a",
+		Locality:   "München",
+		PostalCode: "80331",
+		Country:    "DE",
+	}
+
+	require.Equal(t, "Hauptstraße 5a\n80331 München\nGermany", address.Format(AddressFormatMultiLine))
+	require.Equal(t, "Hauptstraße 5a\n80331 München\nGERMANY", address.Format(AddressFormatPostal))
+}
+
+func TestAddress_Format_FR(t *testing.T) {
+
+	address := Address{
+		Street1:    "10 bis rue de Rivoli",
+		Locality:   "Paris",
+		PostalCode: "75001",
+		Country:    "FR",
+	}
+
+	require.Equal(t, "10 bis rue de Rivoli, 75001 Paris, France", address.Format(AddressFormatSingleLine))
+	require.Equal(t, "10 bis rue de Rivoli\n75001 PARIS\nFRANCE", address.Format(AddressFormatPostal))
+}
+
+func TestAddress_Format_AU(t *testing.T) {
+
+	address := Address{
+		Street1:    "1 Macquarie Street",
+		Street2:    "Level 5",
+		Locality:   "Sydney",
+		Region:     "NSW",
+		PostalCode: "2000",
+		Country:    "AU",
+	}
+
+	require.Equal(t, "Level 5\n1 Macquarie Street\nSYDNEY NSW 2000\nAUSTRALIA", address.Format(AddressFormatPostal))
+}
+
+func TestAddress_Format_CA(t *testing.T) {
+
+	address := Address{
+		Street1:    "24 Sussex Drive",
+		Locality:   "Ottawa",
+		Region:     "ON",
+		PostalCode: "K1M 1M4",
+		Country:    "CA",
+	}
+
+	require.Equal(t, "24 Sussex Drive, Ottawa ON K1M 1M4, Canada", address.Format(AddressFormatSingleLine))
+}
+
+func TestAddress_Format_JP(t *testing.T) {
+
+	// Written in Japanese, from largest to smallest
+	japanese := Address{
+		Street1:    "丸の内1-1-1",
+		Street2:    "パレスビル",
+		Locality:   "千代田区",
+		Region:     "東京都",
+		PostalCode: "100-0005",
+		Country:    "JP",
+	}
+
+	require.Equal(t, "〒100-0005\n東京都千代田区丸の内1-1-1\nパレスビル", japanese.Format(AddressFormatMultiLine))
+	require.Equal(t, "〒100-0005 東京都千代田区丸の内1-1-1 パレスビル", japanese.Format(AddressFormatSingleLine))
+
+	japanese.PostalCode = ""
+	require.Equal(t, "東京都千代田区丸の内1-1-1 パレスビル", japanese.Format(AddressFormatSingleLine))
+
+	// Written in English, from smallest to largest
+	english := Address{
+		Street1:    "1-1 Marunouchi",
+		Locality:   "Chiyoda-ku",
+		Region:     "Tokyo",
+		PostalCode: "100-0005",
+		Country:    "JP",
+	}
+
+	require.Equal(t, "1-1 Marunouchi, Chiyoda-ku, Tokyo 100-0005, Japan", english.Format(AddressFormatSingleLine))
+}
+
+func TestAddress_Format_Default(t *testing.T) {
+
+	address := Address{
+		Street1:    "Keizersgracht 123",
+		Locality:   "Amsterdam",
+		PostalCode: "1015 CJ",
+		Country:    "Netherlands",
+	}
+
+	require.Equal(t, "Keizersgracht 123\n1015 CJ Amsterdam\nNetherlands", address.Format(AddressFormatMultiLine))
+	require.Equal(t, "Keizersgracht 123\n1015 CJ Amsterdam\nNETHERLANDS", address.Format(AddressFormatPostal))
+
+	// Countries without their own template are named from the ISO 3166 table
+	address.Country = "NL"
+	require.Equal(t, "Keizersgracht 123, 1015 CJ Amsterdam, Netherlands", address.Format(AddressFormatSingleLine))
+}
+
+func TestAddress_Format_OtherCountries(t *testing.T) {
+
+	tests := []struct {
+		address  Address
+		expected string
+	}{
+		{
+			Address{Street1: "Avenida Paulista, 1578", Street2: "Bela Vista", Locality: "São Paulo", Region: "SP", PostalCode: "01310-200", Country: "BR"},
+			"Avenida Paulista, 1578\nBela Vista\nSão Paulo - SP\n01310-200\nBrazil",
+		},
+		{
+			Address{Street1: "Via del Corso 1", Locality: "Roma", Region: "RM", PostalCode: "00186", Country: "IT"},
+			"Via del Corso 1\n00186 Roma RM\nItaly",
+		},
+		{
+			Address{Street1: "Paseo de la Reforma 50", Locality: "Ciudad de México", Region: "CDMX", PostalCode: "11580", Country: "MX"},
+			"Paseo de la Reforma 50\n11580 Ciudad de México, CDMX\nMexico",
+		},
+		{
+			Address{Street1: "1 Marine Drive", Locality: "Mumbai", Region: "Maharashtra", PostalCode: "400020", Country: "IN"},
+			"1 Marine Drive\nMumbai 400020\nMaharashtra\nIndia",
+		},
+		{
+			Address{Street1: "Tverskaya ulitsa 7", Locality: "Moscow", PostalCode: "125009", Country: "RU"},
+			"Tverskaya ulitsa 7\nMoscow\n125009\nRussian Federation",
+		},
+		{
+			// Territories use the template of another country
+			Address{Street1: "1 Calle Fortaleza", Locality: "San Juan", Region: "PR", PostalCode: "00901", Country: "PR"},
+			"1 Calle Fortaleza\nSan Juan, PR 00901\nPuerto Rico",
+		},
+		{
+			// Separators are removed along with empty values
+			Address{Street1: "Avenida Paulista, 1578", Locality: "São Paulo", Country: "BR"},
+			"Avenida Paulista, 1578\nSão Paulo\nBrazil",
+		},
+	}
+
+	for _, test := range tests {
+		require.Equal(t, test.expected, test.address.Format(AddressFormatMultiLine))
+	}
+}
+
+func TestAddressTemplates(t *testing.T) {
+
+	templates := addressTemplates()
+
+	// Every country has a template
+	for _, country := range Countries() {
+		_, ok := templates[country.Alpha2]
+		require.True(t, ok, country.Alpha2)
+	}
+
+	// Templates only use known placeholders
+	values := Address{}.formatValues()
+
+	for key, template := range templates {
+
+		require.NotEmpty(t, template.separator, key)
+
+		for _, line := range template.lines {
+			for _, match := range addressPlaceholder.FindAllStringSubmatch(line, -1) {
+				_, ok := values[match[1]]
+				require.True(t, ok, key+" "+line)
+			}
+		}
+
+		for _, name := range template.uppercase {
+			_, ok := values[name]
+			require.True(t, ok || (name == "*"), key+" "+name)
+		}
+	}
+}
+
+func TestAddress_Format_MissingValues(t *testing.T) {
+
+	require.Equal(t, "Springfield, IL, United States", Address{Locality: "Springfield", Region: "IL", Country: "US"}.Format(AddressFormatSingleLine))
+	require.Equal(t, "IL 62701\nUnited States", Address{Region: "IL", PostalCode: "62701", Country: "USA"}.Format("unknown"))
+	require.Equal(t, "62701\nIL", Address{Region: "IL", PostalCode: "62701"}.Format(AddressFormatMultiLine))
+	require.Equal(t, "", Address{}.Format(AddressFormatMultiLine))
+}
+
+func TestAddress_UpdateFormatted(t *testing.T) {
+
+	address := Address{
+		Formatted:  "old value",
+		Street1:    "Unter den Linden 77",
+		Locality:   "Berlin",
+		PostalCode: "10117",
+		Country:    "DE",
+		Latitude:   52.5163,
+		Longitude:  13.3777,
+	}
+
+	address.UpdateFormatted()
+	require.Equal(t, "Unter den Linden 77, 10117 Berlin, Germany", address.Formatted)
+	require.Equal(t, 52.5163, address.Latitude)
+
+	// Without any parsed fields, Formatted is left alone
+	empty := Address{Formatted: "Somewhere"}
+	empty.UpdateFormatted()
+	require.Equal(t, "Somewhere", empty.Formatted)
+}
+
+func TestAddress_Format_RoundTrip(t *testing.T) {
+
+	for _, formatted := range []string{
+		"350 Fifth Avenue, Suite 3300, New York, NY 10118, United States",
+		"Unter den Linden 77, 10117 Berlin, Germany",
+		"10 bis rue de Rivoli, 75001 Paris, France",
+		"1-1 Marunouchi, Chiyoda-ku, Tokyo 100-0005, Japan",
+		"〒100-0005 東京都千代田区丸の内1-1-1 パレスビル",
+	} {
+		address, err := ParseAddress(formatted, "")
+		require.Nil(t, err)
+		require.Equal(t, formatted, address.Format(AddressFormatSingleLine))
+	}
+}
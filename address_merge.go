@@ -0,0 +1,130 @@
+package geo
+
+// AddressMergePolicy decides which values win when Merge combines two addresses
+type AddressMergePolicy int
+
+const (
+	// AddressMergeFillEmpty sets each property that is empty in the existing Address
+	// from the incoming one, and keeps every other value. This is the default policy.
+	AddressMergeFillEmpty AddressMergePolicy = iota
+
+	// AddressMergePreferExisting keeps the existing values, like AddressMergeFillEmpty,
+	// but treats the street address (Street1, Street2, Locality, Region, PostalCode, and
+	// Country) as one value: it is only taken from the incoming Address when the existing
+	// Address has none of it, so that two different addresses are never mixed together.
+	AddressMergePreferExisting
+
+	// AddressMergePreferIncoming replaces existing values with every incoming value that
+	// is not empty, and keeps the existing values where the incoming ones are empty.
+	AddressMergePreferIncoming
+)
+
+// addressStreetProperties are the properties that make up the street address
+var addressStreetProperties = map[string]bool{
+	AddressPropertyStreet1:    true,
+	AddressPropertyStreet2:    true,
+	AddressPropertyLocality:   true,
+	AddressPropertyRegion:     true,
+	AddressPropertyPostalCode: true,
+	AddressPropertyCountry:    true,
+}
+
+// addressStringField is a text property of an Address, which Diff and Merge can read and write
+type addressStringField struct {
+	name  string
+	value *string
+}
+
+// Diff returns the names (AddressProperty* constants) of the properties that are different
+// in another Address, in the order of the Address's fields. Values are compared exactly;
+// use Equal to compare addresses after normalizing them. Diff returns nil if every property
+// is the same.
+func (address Address) Diff(other Address) []string {
+
+	var result []string
+
+	otherFields := other.stringFields()
+
+	for index, field := range address.stringFields() {
+		if *field.value != *otherFields[index].value {
+			result = append(result, field.name)
+		}
+	}
+
+	if address.Latitude != other.Latitude {
+		result = append(result, AddressPropertyLatitude)
+	}
+
+	if address.Longitude != other.Longitude {
+		result = append(result, AddressPropertyLongitude)
+	}
+
+	return result
+}
+
+// Merge copies values from another Address (such as a partial result from a Geocoder)
+// into this Address according to a policy, and returns the names of the properties that
+// changed (as in Diff). Empty incoming values never replace existing ones. Latitude and
+// Longitude are merged together, as one value. The Name is usually entered by a person,
+// so it is only set when it is empty, whatever the policy.
+func (address *Address) Merge(other Address, policy AddressMergePolicy) []string {
+
+	original := *address
+	otherFields := other.stringFields()
+
+	switch policy {
+
+	case AddressMergePreferIncoming:
+
+		for index, field := range address.stringFields() {
+
+			value := *otherFields[index].value
+
+			if (value == "") || ((field.name == AddressPropertyName) && (*field.value != "")) {
+				continue
+			}
+
+			*field.value = value
+		}
+
+		if other.HasGeocode() {
+			address.Latitude, address.Longitude = other.Latitude, other.Longitude
+		}
+
+	default:
+
+		// Keep the whole street address when the existing Address has any of it
+		keepStreet := (policy == AddressMergePreferExisting) && original.HasAddress()
+
+		for index, field := range address.stringFields() {
+
+			if (*field.value != "") || (keepStreet && addressStreetProperties[field.name]) {
+				continue
+			}
+
+			*field.value = *otherFields[index].value
+		}
+
+		if !original.HasGeocode() {
+			address.Latitude, address.Longitude = other.Latitude, other.Longitude
+		}
+	}
+
+	return original.Diff(*address)
+}
+
+// stringFields returns the text properties of this Address, in the order of its fields
+func (address *Address) stringFields() []addressStringField {
+	return []addressStringField{
+		{name: AddressPropertyName, value: &address.Name},
+		{name: AddressPropertyFormatted, value: &address.Formatted},
+		{name: AddressPropertyStreet1, value: &address.Street1},
+		{name: AddressPropertyStreet2, value: &address.Street2},
+		{name: AddressPropertyLocality, value: &address.Locality},
+		{name: AddressPropertyRegion, value: &address.Region},
+		{name: AddressPropertyPostalCode, value: &address.PostalCode},
+		{name: AddressPropertyCountry, value: &address.Country},
+		{name: AddressPropertyPlusCode, value: &address.PlusCode},
+		{name: AddressPropertyTimezone, value: &address.Timezone},
+	}
+}
@@ -0,0 +1,185 @@
+package geo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddress_Diff(t *testing.T) {
+
+	address := Address{Name: "Joe's Diner", Street1: "123 Main St", Locality: "Springfield", Latitude: 39.78, Longitude: -89.65}
+
+	require.Nil(t, address.Diff(address))
+
+	other := address
+	other.Street1 = "123 Main Street"
+	other.PostalCode = "62701"
+	other.Timezone = "America/Chicago"
+	other.Longitude = -89.66
+
+	require.Equal(t, []string{
+		AddressPropertyStreet1,
+		AddressPropertyPostalCode,
+		AddressPropertyTimezone,
+		AddressPropertyLongitude,
+	}, address.Diff(other))
+
+	// Every property is compared
+	require.Equal(t, []string{
+		AddressPropertyName,
+		AddressPropertyFormatted,
+		AddressPropertyStreet1,
+		AddressPropertyStreet2,
+		AddressPropertyLocality,
+		AddressPropertyRegion,
+		AddressPropertyPostalCode,
+		AddressPropertyCountry,
+		AddressPropertyPlusCode,
+		AddressPropertyTimezone,
+		AddressPropertyLatitude,
+		AddressPropertyLongitude,
+	}, Address{}.Diff(Address{
+		Name: "a", Formatted: "b", Street1: "c", Street2: "d", Locality: "e", Region: "f", PostalCode: "g",
+		Country: "h", PlusCode: "i", Timezone: "j", Latitude: 1, Longitude: 2,
+	}))
+}
+
+// testMergeAddresses returns an Address that a person entered, and a partial result from a geocoder
+func testMergeAddresses() (Address, Address) {
+
+	existing := Address{
+		Name:      "Joe's Diner",
+		Formatted: "123 Main St, Springfield",
+		Street1:   "123 Main St",
+		Locality:  "Springfield",
+	}
+
+	incoming := Address{
+		Name:       "Joe's Diner & Grill",
+		Street1:    "123 Main Street",
+		Locality:   "Springfield",
+		Region:     "IL",
+		PostalCode: "62701",
+		Country:    "US",
+		Latitude:   39.7817,
+		Longitude:  -89.6501,
+	}
+
+	return existing, incoming
+}
+
+func TestAddress_Merge_FillEmpty(t *testing.T) {
+
+	address, incoming := testMergeAddresses()
+	changed := address.Merge(incoming, AddressMergeFillEmpty)
+
+	require.Equal(t, []string{
+		AddressPropertyRegion,
+		AddressPropertyPostalCode,
+		AddressPropertyCountry,
+		AddressPropertyLatitude,
+		AddressPropertyLongitude,
+	}, changed)
+
+	require.Equal(t, Address{
+		Name:       "Joe's Diner",
+		Formatted:  "123 Main St, Springfield",
+		Street1:    "123 Main St",
+		Locality:   "Springfield",
+		Region:     "IL",
+		PostalCode: "62701",
+		Country:    "US",
+		Latitude:   39.7817,
+		Longitude:  -89.6501,
+	}, address)
+}
+
+func TestAddress_Merge_PreferExisting(t *testing.T) {
+
+	address, incoming := testMergeAddresses()
+	changed := address.Merge(incoming, AddressMergePreferExisting)
+
+	// The street address is kept together, so only the coordinates change
+	require.Equal(t, []string{AddressPropertyLatitude, AddressPropertyLongitude}, changed)
+	require.Equal(t, "", address.PostalCode)
+	require.Equal(t, 39.7817, address.Latitude)
+
+	// Addresses without a street address take the whole incoming one
+	address = Address{Name: "Joe's Diner", Timezone: "America/Chicago"}
+	changed = address.Merge(incoming, AddressMergePreferExisting)
+
+	require.Equal(t, []string{
+		AddressPropertyStreet1,
+		AddressPropertyLocality,
+		AddressPropertyRegion,
+		AddressPropertyPostalCode,
+		AddressPropertyCountry,
+		AddressPropertyLatitude,
+		AddressPropertyLongitude,
+	}, changed)
+	require.Equal(t, "Joe's Diner", address.Name)
+	require.Equal(t, "123 Main Street", address.Street1)
+	require.Equal(t, "America/Chicago", address.Timezone)
+}
+
+func TestAddress_Merge_PreferIncoming(t *testing.T) {
+
+	address, incoming := testMergeAddresses()
+	address.Latitude, address.Longitude = 39.78, -89.65
+	address.Timezone = "America/Chicago"
+
+	changed := address.Merge(incoming, AddressMergePreferIncoming)
+
+	require.Equal(t, []string{
+		AddressPropertyStreet1,
+		AddressPropertyRegion,
+		AddressPropertyPostalCode,
+		AddressPropertyCountry,
+		AddressPropertyLatitude,
+		AddressPropertyLongitude,
+	}, changed)
+
+	require.Equal(t, Address{
+		Name:       "Joe's Diner", // Names are never replaced
+		Formatted:  "123 Main St, Springfield",
+		Street1:    "123 Main Street",
+		Locality:   "Springfield",
+		Region:     "IL",
+		PostalCode: "62701",
+		Country:    "US",
+		Timezone:   "America/Chicago", // Empty incoming values are ignored
+		Latitude:   39.7817,
+		Longitude:  -89.6501,
+	}, address)
+}
+
+func TestAddress_Merge_Name(t *testing.T) {
+
+	for _, policy := range []AddressMergePolicy{AddressMergeFillEmpty, AddressMergePreferExisting, AddressMergePreferIncoming} {
+
+		// Empty names are filled in
+		address := Address{}
+		address.Merge(Address{Name: "Joe's Diner"}, policy)
+		require.Equal(t, "Joe's Diner", address.Name, policy)
+
+		// Existing names are not
+		address.Merge(Address{Name: "Something Else"}, policy)
+		require.Equal(t, "Joe's Diner", address.Name, policy)
+	}
+}
+
+func TestAddress_Merge_Coordinates(t *testing.T) {
+
+	// Latitude and longitude are merged together, even when one of them is zero
+	address := Address{Latitude: 51.4779, Longitude: 0}
+	require.Nil(t, address.Merge(Address{Latitude: 40, Longitude: -74}, AddressMergeFillEmpty))
+	require.Equal(t, Address{Latitude: 51.4779}, address)
+
+	require.Equal(t, []string{AddressPropertyLatitude, AddressPropertyLongitude}, address.Merge(Address{Latitude: 40, Longitude: -74}, AddressMergePreferIncoming))
+	require.Equal(t, Address{Latitude: 40, Longitude: -74}, address)
+
+	// Incoming addresses without coordinates do not clear them
+	require.Nil(t, address.Merge(Address{}, AddressMergePreferIncoming))
+	require.Equal(t, Address{Latitude: 40, Longitude: -74}, address)
+}
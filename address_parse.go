@@ -0,0 +1,704 @@
+package geo
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/benpate/derp"
+)
+
+// Labels for the components of a parsed address. These match the labels used by libpostal.
+const (
+	// AddressLabelHouse is the name of a building or venue, such as "Buckingham Palace"
+	AddressLabelHouse = "house"
+
+	// AddressLabelHouseNumber is the number of a building on its street, such as "1600" or "221B"
+	AddressLabelHouseNumber = "house_number"
+
+	// AddressLabelRoad is the name of a street, such as "Pennsylvania Ave NW"
+	AddressLabelRoad = "road"
+
+	// AddressLabelUnit is an apartment, suite, flat, or floor, such as "Apt 4B"
+	AddressLabelUnit = "unit"
+
+	// AddressLabelSuburb is any other line between the street and the city
+	AddressLabelSuburb = "suburb"
+
+	// AddressLabelCity is the city, town, or (in Japan) the city or ward
+	AddressLabelCity = "city"
+
+	// AddressLabelState is the state, province, territory, or prefecture
+	AddressLabelState = "state"
+
+	// AddressLabelPostcode is the postal code
+	AddressLabelPostcode = "postcode"
+
+	// AddressLabelCountry is the country, as it was written in the address
+	AddressLabelCountry = "country"
+)
+
+// AddressComponent is a single labeled part of a parsed address
+type AddressComponent struct {
+	Label string // One of the AddressLabel* constants
+	Value string // The text of this component, as it was written
+}
+
+// Postal codes that end an address segment, for countries that write the region before the postal code
+var addressPostcodeAtEnd = map[string]*regexp.Regexp{
+	"US": regexp.MustCompile(`(?:^|\s)(\d{5}(?:-\d{4})?)$`),
+	"CA": regexp.MustCompile(`(?i)(?:^|\s)([a-z]\d[a-z] ?\d[a-z]\d)$`),
+	"GB": regexp.MustCompile(`(?i)(?:^|\s)([a-z]{1,2}\d[a-z\d]? ?\d[a-z]{2})$`),
+	"AU": regexp.MustCompile(`(?:^|\s)(\d{4})$`),
+	"JP": regexp.MustCompile(`(?:^|\s)〒? ?(\d{3}-\d{4})$`),
+}
+
+// Postal codes that begin an address segment (followed by the city), as in Germany and France
+var addressPostcodeAtStart = regexp.MustCompile(`(?i)^(?:[a-z]-)?(\d{5}) (.+)$`)
+
+// Postal codes inside an address segment (between the street and the city), for addresses without commas,
+// as in "10 rue de la Paix 75002 Paris". The city may not begin with a digit.
+var addressPostcodeInside = regexp.MustCompile(`(?i)^(.*\S) (?:[a-z]-)?(\d{5}) (\D.*)$`)
+
+// Japanese postal codes, anywhere in an address written in Japanese
+var addressJapanesePostcode = regexp.MustCompile(`〒? ?([0-9０-９]{3}[-－‐−][0-9０-９]{4})`)
+
+// Unit designators (apartments, suites, floors) in several languages
+const addressUnitWords = `apt|apartment|suite|ste|unit|flat|floor|fl|room|rm|level|lvl|bldg|building|` +
+	`whg|wohnung|etage|stock|og|app|appartement|appt|bâtiment|batiment|bât|bat|escalier|esc|étage|porte|bureau`
+
+// addressUnit matches a whole segment that is a unit, such as "Apt 4B", "#12", or "3. OG"
+var addressUnit = regexp.MustCompile(`(?i)^(?:(?:` + addressUnitWords + `)\.? ?#? ?[\p{L}\d-]+|# ?[\p{L}\d-]+|\d+\.? ?(?:og|etage|stock|étage|floor))$`)
+
+// addressInlineUnit matches a street segment that ends with a unit, such as "123 Main St Apt 4B"
+var addressInlineUnit = regexp.MustCompile(`(?i)^(.*?\S) ((?:(?:` + addressUnitWords + `)\.? ?#? ?|# ?)[\p{L}\d-]+)$`)
+
+// addressLeadingNumber matches a street that begins with a house number, such as "221B Baker Street" or "10 bis rue Cler"
+var addressLeadingNumber = regexp.MustCompile(`(?i)^(\d+[a-z]?(?:[-/]\d+[a-z]?)*(?: (?:bis|ter|quater)\b)?),? (.+)$`)
+
+// addressTrailingNumber matches a street that ends with a house number, such as "Unter den Linden 77" or "Hauptstraße 5a"
+var addressTrailingNumber = regexp.MustCompile(`(?i)^(.+?) (\d+ ?[a-z]?(?: ?[-/] ?\d+ ?[a-z]?)?)$`)
+
+// addressJapaneseNumber matches the block and building numbers at the end of a street in Japanese, such as "丸の内1-1-1"
+var addressJapaneseNumber = regexp.MustCompile(`^(.*?)([0-9０-９]+(?:[-－‐−丁目番地号の]+[0-9０-９]*)*)$`)
+
+// parsedAddress collects the components of an address while it is being parsed
+type parsedAddress struct {
+	country     string // ISO 3166-1 alpha-2 code (if known)
+	countryText string
+	house       string
+	street      string
+	houseNumber string
+	road        string
+	units       []string
+	suburbs     []string
+	city        string
+	state       string
+	postcode    string
+}
+
+// ParseAddress parses a free-text address into its structured fields, without calling
+// any external service. It handles the conventions of the United States, Canada,
+// the United Kingdom, Germany, France, Australia, and Japan (written in either Japanese
+// or English). The country is read from the address when it is written there, otherwise
+// hintCountry (an ISO 3166-1 alpha-2 code or English name) is used, and if that is empty
+// then the country is guessed from the shape of the postal code. The returned Address
+// uses the ISO 3166-1 alpha-2 code for its Country. Parsing is heuristic: it recognizes
+// common layouts, but cannot match the accuracy of a full geocoder.
+func ParseAddress(formatted string, hintCountry string) (Address, error) {
+
+	const location = "geo.ParseAddress"
+
+	if strings.TrimSpace(formatted) == "" {
+		return Address{}, derp.Internal(location, "Address must not be empty")
+	}
+
+	parsed := parseAddress(formatted, hintCountry)
+
+	result := Address{
+		Name:       parsed.house,
+		Formatted:  formatted,
+		Street1:    parsed.street,
+		Street2:    strings.Join(append(parsed.units, parsed.suburbs...), ", "),
+		Locality:   parsed.city,
+		Region:     parsed.state,
+		PostalCode: parsed.postcode,
+		Country:    parsed.country,
+	}
+
+	if result.Country == "" {
+		result.Country = parsed.countryText
+	}
+
+	return result, nil
+}
+
+// ParseAddressComponents parses a free-text address (like ParseAddress) and returns each
+// of its labeled components, in the order: house, house_number, road, unit, suburb,
+// city, state, postcode, country. Components that are not found are omitted.
+func ParseAddressComponents(formatted string, hintCountry string) []AddressComponent {
+
+	parsed := parseAddress(formatted, hintCountry)
+	result := make([]AddressComponent, 0, 8)
+
+	add := func(label string, value string) {
+		if value != "" {
+			result = append(result, AddressComponent{Label: label, Value: value})
+		}
+	}
+
+	add(AddressLabelHouse, parsed.house)
+	add(AddressLabelHouseNumber, parsed.houseNumber)
+	add(AddressLabelRoad, parsed.road)
+
+	for _, unit := range parsed.units {
+		add(AddressLabelUnit, unit)
+	}
+
+	for _, suburb := range parsed.suburbs {
+		add(AddressLabelSuburb, suburb)
+	}
+
+	add(AddressLabelCity, parsed.city)
+	add(AddressLabelState, parsed.state)
+	add(AddressLabelPostcode, parsed.postcode)
+	add(AddressLabelCountry, parsed.countryText)
+
+	return result
+}
+
+// ParseFormatted fills in this Address's street, locality, region, postal code, and
+// country by parsing its Formatted value with ParseAddress. Its name and any geocoded
+// values are left unchanged.
+func (address *Address) ParseFormatted(hintCountry string) error {
+
+	const location = "geo.Address.ParseFormatted"
+
+	parsed, err := ParseAddress(address.Formatted, hintCountry)
+
+	if err != nil {
+		return derp.Wrap(err, location, "Unable to parse formatted address", address.Formatted)
+	}
+
+	address.Street1 = parsed.Street1
+	address.Street2 = parsed.Street2
+	address.Locality = parsed.Locality
+	address.Region = parsed.Region
+	address.PostalCode = parsed.PostalCode
+	address.Country = parsed.Country
+
+	return nil
+}
+
+/******************************************
+ * Parser
+ ******************************************/
+
+// parseAddress splits an address into segments, and then labels them from the
+// end (country, postal code, region, city) to the beginning (street and unit)
+func parseAddress(formatted string, hintCountry string) parsedAddress {
+
+	result := parsedAddress{
+		country: addressCountryCode(hintCountry),
+	}
+
+	segments := addressSegments(formatted)
+
+	// The country, when it is written, is always last
+	if len(segments) > 0 {
+		last := segments[len(segments)-1]
+
+		if code, ok := addressCountryNames[addressKey(last)]; ok {
+			result.country = code
+			result.countryText = last
+			segments = segments[:len(segments)-1]
+		}
+	}
+
+	// Addresses written in Japanese run from largest to smallest
+	if addressHasPrefecture(segments) {
+		result.country = "JP"
+		result.parseJapanese(segments)
+		return result
+	}
+
+	if result.country == "" {
+		result.country = addressGuessCountry(segments)
+	}
+
+	// Label the postal code, region, and city, then the street
+	var head []string
+
+	switch result.country {
+
+	case "DE", "FR":
+		head = result.parsePostcodeFirst(segments)
+
+	case "US", "CA", "AU", "GB", "JP":
+		head = result.parsePostcodeLast(segments)
+
+	default:
+		head = result.parsePostcodeFirst(segments)
+	}
+
+	result.parseStreet(head)
+
+	// When there were no commas, the city may still be attached to the street
+	if (result.city == "") && (result.street != "") && (len(segments) == 1) {
+		result.splitStreetAndCity()
+	}
+
+	return result
+}
+
+// parsePostcodeLast labels addresses that end with "city region postcode", as in the
+// US, Canada, Australia, the UK (without a region), and Japan (in English).
+// It returns the segments that remain before the city.
+func (parsed *parsedAddress) parsePostcodeLast(segments []string) []string {
+
+	pattern := addressPostcodeAtEnd[parsed.country]
+	regions := addressRegions[parsed.country]
+	end := len(segments)
+
+	// Find the postal code in one of the last two segments
+	for index := len(segments) - 1; (index >= 0) && (index >= len(segments)-2); index-- {
+
+		match := pattern.FindStringSubmatchIndex(segments[index])
+
+		if match == nil {
+			continue
+		}
+
+		parsed.postcode = segments[index][match[2]:match[3]]
+		before := strings.TrimSpace(segments[index][:match[0]])
+
+		// Anything after the postal code may only be the region
+		for _, segment := range segments[index+1:] {
+			if (parsed.state == "") && regions[addressKey(segment)] {
+				parsed.state = segment
+			}
+		}
+
+		parsed.state, before = addressSplitRegion(before, regions, parsed.state)
+		end = index
+
+		// Without commas, the street and city share a segment. splitStreetAndCity separates them later.
+		if (index == 0) && addressLeadingNumber.MatchString(before) {
+			return []string{before}
+		}
+
+		parsed.city = before
+		break
+	}
+
+	// Without a postal code, the last segment may be "city region" or just "region"
+	if (parsed.postcode == "") && (end > 0) {
+
+		if region, before := addressSplitRegion(segments[end-1], regions, ""); region != "" {
+			parsed.state = region
+			parsed.city = before
+			end--
+		} else if (parsed.country == "GB") && (end > 1) {
+			parsed.city = segments[end-1]
+			end--
+		}
+	}
+
+	// The region may have its own segment, as in "Toronto, Ontario, M5V 3L9"
+	if (parsed.state == "") && (parsed.city == "") && (end > 0) && regions[addressKey(segments[end-1])] {
+		parsed.state = segments[end-1]
+		end--
+	}
+
+	// The city may have its own segment, as in "Pittsburgh, PA 15212"
+	if (parsed.city == "") && (end > 0) && ((end > 1) || !addressLeadingNumber.MatchString(segments[0])) {
+		parsed.city = segments[end-1]
+		end--
+	}
+
+	return segments[:end]
+}
+
+// parsePostcodeFirst labels addresses that end with "postcode city", as in Germany and France,
+// including addresses without commas, where the postal code follows the street in the same
+// segment. It returns the segments that remain before the city.
+func (parsed *parsedAddress) parsePostcodeFirst(segments []string) []string {
+
+	for index := len(segments) - 1; (index >= 0) && (index >= len(segments)-2); index-- {
+
+		head := segments[:index]
+		match := addressPostcodeAtStart.FindStringSubmatch(segments[index])
+
+		// Without commas, the street comes before the postal code in the same segment
+		if match == nil {
+
+			inside := addressPostcodeInside.FindStringSubmatch(segments[index])
+
+			if inside == nil {
+				continue
+			}
+
+			head = append(segments[:index:index], inside[1])
+			match = inside[1:]
+		}
+
+		parsed.postcode = match[1]
+		parsed.city = match[2]
+
+		// A segment after the city is the region, as in "75001 Paris, Île-de-France"
+		if index+1 < len(segments) {
+			parsed.state = segments[index+1]
+		}
+
+		return head
+	}
+
+	// Without a postal code, the last segment is the city
+	if len(segments) > 1 {
+		parsed.city = segments[len(segments)-1]
+		return segments[:len(segments)-1]
+	}
+
+	return segments
+}
+
+// parseStreet labels the segments that come before the city: a building name, the
+// street (with its house number), units, and any other lines
+func (parsed *parsedAddress) parseStreet(segments []string) {
+
+	// The street is the first segment with a house number, or the first that is not a unit
+	street := -1
+
+	for index, segment := range segments {
+		if !addressUnit.MatchString(segment) && parsed.hasHouseNumber(segment) {
+			street = index
+			break
+		}
+	}
+
+	if street == -1 {
+		for index, segment := range segments {
+			if !addressUnit.MatchString(segment) {
+				street = index
+				break
+			}
+		}
+	}
+
+	for index, segment := range segments {
+
+		switch {
+
+		case addressUnit.MatchString(segment):
+			parsed.units = append(parsed.units, segment)
+
+		case index == street:
+			if match := addressInlineUnit.FindStringSubmatch(segment); match != nil {
+				segment = match[1]
+				parsed.units = append(parsed.units, match[2])
+			}
+
+			parsed.street = segment
+			parsed.houseNumber, parsed.road = parsed.splitHouseNumber(segment)
+
+		case (index < street) && (parsed.house == ""):
+			parsed.house = segment
+
+		default:
+			parsed.suburbs = append(parsed.suburbs, segment)
+		}
+	}
+}
+
+// splitStreetAndCity separates the city from the end of the street, for addresses that
+// have no commas, such as "1600 Pennsylvania Ave NW Washington DC 20500". The street
+// is assumed to end with a street type (such as "Ave"), and an optional direction.
+func (parsed *parsedAddress) splitStreetAndCity() {
+
+	words := strings.Fields(parsed.street)
+
+	for index := len(words) - 2; index >= 1; index-- {
+
+		if !addressStreetTypes[addressKey(words[index])] {
+			continue
+		}
+
+		end := index + 1
+
+		if addressDirections[addressKey(words[end])] && (end+1 < len(words)) {
+			end++
+		}
+
+		parsed.city = strings.Join(words[end:], " ")
+		parsed.street = strings.Join(words[:end], " ")
+		parsed.houseNumber, parsed.road = parsed.splitHouseNumber(parsed.street)
+		return
+	}
+}
+
+// parseJapanese labels an address written in Japanese, which begins with the postal code
+// and prefecture, then the city (or ward), and then the district and block numbers
+func (parsed *parsedAddress) parseJapanese(segments []string) {
+
+	value := strings.Join(segments, " ")
+
+	if match := addressJapanesePostcode.FindStringSubmatchIndex(value); match != nil {
+		parsed.postcode = value[match[2]:match[3]]
+		value = value[:match[0]] + value[match[1]:]
+	}
+
+	value = strings.TrimSpace(value)
+
+	for _, prefecture := range addressPrefectures {
+		if index := strings.Index(value, prefecture); index >= 0 {
+			parsed.state = prefecture
+			value = strings.TrimSpace(value[index+len(prefecture):])
+			break
+		}
+	}
+
+	// The city ends with 市 (city), 区 (ward), 町 (town), or 村 (village)
+	for _, suffix := range []string{"市", "区", "町", "村"} {
+		if index := strings.Index(value, suffix); index > 0 {
+			parsed.city = value[:index+len(suffix)]
+			value = strings.TrimSpace(value[index+len(suffix):])
+			break
+		}
+	}
+
+	// Large cities are divided into wards, as in 大阪市北区
+	if strings.HasSuffix(parsed.city, "市") {
+		if ward, _, found := strings.Cut(value, "区"); found && (utf8.RuneCountInString(ward) <= 3) {
+			parsed.city += ward + "区"
+			value = strings.TrimSpace(value[len(ward)+len("区"):])
+		}
+	}
+
+	// Anything after a space is the building name
+	if street, building, found := strings.Cut(value, " "); found {
+		value = street
+		parsed.suburbs = append(parsed.suburbs, strings.TrimSpace(building))
+	}
+
+	parsed.street = value
+
+	if match := addressJapaneseNumber.FindStringSubmatch(value); (match != nil) && (match[1] != "") {
+		parsed.road = match[1]
+		parsed.houseNumber = match[2]
+	} else {
+		parsed.road = value
+	}
+}
+
+// hasHouseNumber returns TRUE if a street segment includes a house number
+func (parsed *parsedAddress) hasHouseNumber(segment string) bool {
+	houseNumber, _ := parsed.splitHouseNumber(segment)
+	return houseNumber != ""
+}
+
+// splitHouseNumber separates the house number from the street name. German
+// addresses put the number after the street; most others put it before.
+func (parsed *parsedAddress) splitHouseNumber(street string) (houseNumber string, road string) {
+
+	leading := func() bool {
+		if match := addressLeadingNumber.FindStringSubmatch(street); match != nil {
+			houseNumber, road = match[1], match[2]
+			return true
+		}
+		return false
+	}
+
+	trailing := func() bool {
+		if match := addressTrailingNumber.FindStringSubmatch(street); match != nil {
+			houseNumber, road = match[2], match[1]
+			return true
+		}
+		return false
+	}
+
+	switch parsed.country {
+
+	case "DE":
+		_ = trailing() || leading()
+
+	case "US", "CA", "GB", "FR", "AU", "JP":
+		_ = leading()
+
+	default:
+		_ = leading() || trailing()
+	}
+
+	if houseNumber == "" {
+		return "", street
+	}
+
+	return houseNumber, road
+}
+
+/******************************************
+ * Helpers
+ ******************************************/
+
+// addressSegments splits an address into its comma- or line-separated parts,
+// with extra whitespace removed
+func addressSegments(formatted string) []string {
+
+	fields := strings.FieldsFunc(formatted, func(r rune) bool {
+		return (r == ',') || (r == '\n') || (r == ';') || (r == '、')
+	})
+
+	result := make([]string, 0, len(fields))
+
+	for _, field := range fields {
+		if field = strings.Join(strings.Fields(field), " "); field != "" {
+			result = append(result, field)
+		}
+	}
+
+	return result
+}
+
+// addressSplitRegion removes a region from the end of a segment, and returns the
+// region along with the remaining text. Longer region names are tried first, so that
+// "West Virginia" is found before "Virginia". If a region has already been found,
+// then the segment is returned unchanged.
+func addressSplitRegion(segment string, regions map[string]bool, found string) (string, string) {
+
+	if (found != "") || (len(regions) == 0) {
+		return found, segment
+	}
+
+	words := strings.Fields(segment)
+
+	for count := min(4, len(words)); count >= 1; count-- {
+
+		candidate := strings.Join(words[len(words)-count:], " ")
+
+		if regions[addressKey(candidate)] {
+			return candidate, strings.Join(words[:len(words)-count], " ")
+		}
+	}
+
+	return "", segment
+}
+
+// addressGuessCountry guesses the country of an address from the shape of its postal
+// code (and region, or street words) when the country is not otherwise known
+func addressGuessCountry(segments []string) string {
+
+	for index := len(segments) - 1; (index >= 0) && (index >= len(segments)-2); index-- {
+
+		segment := segments[index]
+
+		for _, country := range []string{"CA", "GB", "JP"} {
+			if addressPostcodeAtEnd[country].MatchString(segment) {
+				return country
+			}
+		}
+
+		// US and Australian postal codes follow a state
+		for _, country := range []string{"US", "AU"} {
+			if match := addressPostcodeAtEnd[country].FindStringSubmatchIndex(segment); match != nil {
+				if region, _ := addressSplitRegion(segment[:match[0]], addressRegions[country], ""); region != "" {
+					return country
+				}
+			}
+		}
+
+		// German and French postal codes come before the city
+		if addressPostcodeAtStart.MatchString(segment) || addressPostcodeInside.MatchString(segment) {
+			return addressGuessEuropeanCountry(segments)
+		}
+	}
+
+	// Without a postal code, use a region that belongs to only one country
+	if len(segments) > 1 {
+
+		var result string
+
+		for _, country := range []string{"US", "CA", "AU"} {
+			if region, _ := addressSplitRegion(segments[len(segments)-1], addressRegions[country], ""); region != "" {
+
+				if result != "" {
+					return ""
+				}
+
+				result = country
+			}
+		}
+
+		return result
+	}
+
+	return ""
+}
+
+// addressGuessEuropeanCountry chooses between Germany and France using the words in the street
+func addressGuessEuropeanCountry(segments []string) string {
+
+	for _, segment := range segments {
+
+		for _, word := range strings.FieldsFunc(strings.ToLower(segment), addressWordSeparator) {
+
+			for _, german := range addressGermanStreets {
+				if (word == german) || strings.HasSuffix(word, german) {
+					return "DE"
+				}
+			}
+
+			for _, french := range addressFrenchStreets {
+				if word == french {
+					return "FR"
+				}
+			}
+		}
+	}
+
+	return ""
+}
+
+// addressWordSeparator splits words on whitespace and hyphens (but not on periods, as in "str.")
+func addressWordSeparator(r rune) bool {
+	return unicode.IsSpace(r) || (r == '-')
+}
+
+// addressHasPrefecture returns TRUE if any segment includes a Japanese prefecture (written in Japanese)
+func addressHasPrefecture(segments []string) bool {
+
+	for _, segment := range segments {
+		for _, prefecture := range addressPrefectures {
+			if strings.Contains(segment, prefecture) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// addressCountryCode converts a country hint (an ISO code or a name) into an ISO 3166-1
+// alpha-2 code. Unrecognized two-letter values are assumed to be codes already.
+func addressCountryCode(value string) string {
+
+	key := addressKey(value)
+
+	if code, ok := addressCountryNames[key]; ok {
+		return code
+	}
+
+	if country, ok := LookupCountry(value); ok {
+		return country.Alpha2
+	}
+
+	if len(key) == 2 {
+		return strings.ToUpper(key)
+	}
+
+	return ""
+}
+
+// addressKey normalizes a word or phrase for dictionary lookups
+func addressKey(value string) string {
+	return strings.Join(strings.Fields(strings.ToLower(strings.ReplaceAll(value, ".", ""))), " ")
+}
@@ -0,0 +1,136 @@
+package geo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseAddress(t *testing.T) {
+
+	tests := []struct {
+		formatted string
+		hint      string
+		expected  Address
+	}{
+		// United States
+		{"1600 Pennsylvania Ave NW, Washington, DC 20500", "", Address{Street1: "1600 Pennsylvania Ave NW", Locality: "Washington", Region: "DC", PostalCode: "20500", Country: "US"}},
+		{"1600 Pennsylvania Ave NW Washington DC 20500", "", Address{Street1: "1600 Pennsylvania Ave NW", Locality: "Washington", Region: "DC", PostalCode: "20500", Country: "US"}},
+		{"350 Fifth Avenue, Suite 3300, New York, NY 10118, USA", "", Address{Street1: "350 Fifth Avenue", Street2: "Suite 3300", Locality: "New York", Region: "NY", PostalCode: "10118", Country: "US"}},
+		{"123 Main St Apt 4B\nSpringfield, IL 62701-1234", "", Address{Street1: "123 Main St", Street2: "Apt 4B", Locality: "Springfield", Region: "IL", PostalCode: "62701-1234", Country: "US"}},
+		{"500 W Virginia St, Charleston, West Virginia 25302", "", Address{Street1: "500 W Virginia St", Locality: "Charleston", Region: "West Virginia", PostalCode: "25302", Country: "US"}},
+		{"Springfield, IL", "", Address{Locality: "Springfield", Region: "IL", Country: "US"}},
+
+		// Canada
+		{"24 Sussex Drive, Ottawa, ON K1M 1M4, Canada", "", Address{Street1: "24 Sussex Drive", Locality: "Ottawa", Region: "ON", PostalCode: "K1M 1M4", Country: "CA"}},
+		{"301 Front St W, Toronto, Ontario, M5V 2T6", "", Address{Street1: "301 Front St W", Locality: "Toronto", Region: "Ontario", PostalCode: "M5V 2T6", Country: "CA"}},
+
+		// United Kingdom
+		{"Flat 3, 10 Downing Street, London SW1A 2AA, United Kingdom", "", Address{Street1: "10 Downing Street", Street2: "Flat 3", Locality: "London", PostalCode: "SW1A 2AA", Country: "GB"}},
+		{"221B Baker Street\nLondon\nNW1 6XE", "", Address{Street1: "221B Baker Street", Locality: "London", PostalCode: "NW1 6XE", Country: "GB"}},
+		{"Kensington Palace, 1 Palace Green, London W8 4PX", "", Address{Name: "Kensington Palace", Street1: "1 Palace Green", Locality: "London", PostalCode: "W8 4PX", Country: "GB"}},
+		{"10 Downing Street, London", "UK", Address{Street1: "10 Downing Street", Locality: "London", Country: "GB"}},
+
+		// Germany
+		{"Unter den Linden 77, 10117 Berlin, Deutschland", "", Address{Street1: "Unter den Linden 77", Locality: "Berlin", PostalCode: "10117", Country: "DE"}},
+		{"Hauptstraße 5a, 3. OG, 80331 München", "", Address{Street1: "Hauptstraße 5a", Street2: "3. OG", Locality: "München", PostalCode: "80331", Country: "DE"}},
+		{"Platz der Republik 1, 11011 Berlin", "DE", Address{Street1: "Platz der Republik 1", Locality: "Berlin", PostalCode: "11011", Country: "DE"}},
+		{"Unter den Linden 77 10117 Berlin", "DE", Address{Street1: "Unter den Linden 77", Locality: "Berlin", PostalCode: "10117", Country: "DE"}},
+		{"Hauptstraße 5a 80331 München", "", Address{Street1: "Hauptstraße 5a", Locality: "München", PostalCode: "80331", Country: "DE"}},
+		{"Hauptstraße 5a, 3. OG, D-80331 München", "", Address{Street1: "Hauptstraße 5a", Street2: "3. OG", Locality: "München", PostalCode: "80331", Country: "DE"}},
+
+		// France
+		{"10 bis rue de Rivoli, 75001 Paris, France", "", Address{Street1: "10 bis rue de Rivoli", Locality: "Paris", PostalCode: "75001", Country: "FR"}},
+		{"55 Rue du Faubourg Saint-Honoré, 75008 Paris", "", Address{Street1: "55 Rue du Faubourg Saint-Honoré", Locality: "Paris", PostalCode: "75008", Country: "FR"}},
+		{"10 rue de la Paix 75002 Paris", "FR", Address{Street1: "10 rue de la Paix", Locality: "Paris", PostalCode: "75002", Country: "FR"}},
+		{"10 rue de la Paix 75002 Paris", "", Address{Street1: "10 rue de la Paix", Locality: "Paris", PostalCode: "75002", Country: "FR"}},
+		{"10 rue de la Paix 75002 Paris, France", "", Address{Street1: "10 rue de la Paix", Locality: "Paris", PostalCode: "75002", Country: "FR"}},
+
+		// Australia
+		{"Level 5, 1 Macquarie Street, Sydney NSW 2000, Australia", "", Address{Street1: "1 Macquarie Street", Street2: "Level 5", Locality: "Sydney", Region: "NSW", PostalCode: "2000", Country: "AU"}},
+		{"100 George St, Parramatta, New South Wales 2150", "", Address{Street1: "100 George St", Locality: "Parramatta", Region: "New South Wales", PostalCode: "2150", Country: "AU"}},
+
+		// Japan
+		{"〒100-0005 東京都千代田区丸の内1-1-1 パレスビル", "", Address{Street1: "丸の内1-1-1", Street2: "パレスビル", Locality: "千代田区", Region: "東京都", PostalCode: "100-0005", Country: "JP"}},
+		{"〒530-0001 大阪府大阪市北区梅田3丁目1番3号", "", Address{Street1: "梅田3丁目1番3号", Locality: "大阪市北区", Region: "大阪府", PostalCode: "530-0001", Country: "JP"}},
+		{"1-1 Marunouchi, Chiyoda-ku, Tokyo 100-0005, Japan", "", Address{Street1: "1-1 Marunouchi", Locality: "Chiyoda-ku", Region: "Tokyo", PostalCode: "100-0005", Country: "JP"}},
+
+		// Unknown
+		{"Somewhere", "", Address{Street1: "Somewhere"}},
+	}
+
+	for _, test := range tests {
+		result, err := ParseAddress(test.formatted, test.hint)
+		require.Nil(t, err, test.formatted)
+
+		test.expected.Formatted = test.formatted
+		require.Equal(t, test.expected, result, test.formatted)
+	}
+}
+
+func TestParseAddress_Empty(t *testing.T) {
+	_, err := ParseAddress(" \n ", "US")
+	require.NotNil(t, err)
+}
+
+func TestParseAddress_CountryOverridesHint(t *testing.T) {
+	result, err := ParseAddress("24 Sussex Drive, Ottawa, ON K1M 1M4, Canada", "US")
+	require.Nil(t, err)
+	require.Equal(t, "CA", result.Country)
+}
+
+func TestParseAddressComponents(t *testing.T) {
+
+	require.Equal(t, []AddressComponent{
+		{Label: AddressLabelHouseNumber, Value: "350"},
+		{Label: AddressLabelRoad, Value: "Fifth Avenue"},
+		{Label: AddressLabelUnit, Value: "Suite 3300"},
+		{Label: AddressLabelCity, Value: "New York"},
+		{Label: AddressLabelState, Value: "NY"},
+		{Label: AddressLabelPostcode, Value: "10118"},
+		{Label: AddressLabelCountry, Value: "USA"},
+	}, ParseAddressComponents("350 Fifth Avenue, Suite 3300, New York, NY 10118, USA", ""))
+
+	require.Equal(t, []AddressComponent{
+		{Label: AddressLabelHouseNumber, Value: "77"},
+		{Label: AddressLabelRoad, Value: "Unter den Linden"},
+		{Label: AddressLabelCity, Value: "Berlin"},
+		{Label: AddressLabelPostcode, Value: "10117"},
+	}, ParseAddressComponents("Unter den Linden 77, 10117 Berlin", "DE"))
+
+	require.Equal(t, []AddressComponent{
+		{Label: AddressLabelHouseNumber, Value: "1-1-1"},
+		{Label: AddressLabelRoad, Value: "丸の内"},
+		{Label: AddressLabelSuburb, Value: "パレスビル"},
+		{Label: AddressLabelCity, Value: "千代田区"},
+		{Label: AddressLabelState, Value: "東京都"},
+		{Label: AddressLabelPostcode, Value: "100-0005"},
+	}, ParseAddressComponents("〒100-0005 東京都千代田区丸の内1-1-1 パレスビル", ""))
+
+	require.Empty(t, ParseAddressComponents("", ""))
+}
+
+func TestAddress_ParseFormatted(t *testing.T) {
+
+	address := Address{
+		Name:      "White House",
+		Formatted: "1600 Pennsylvania Ave NW, Washington, DC 20500",
+		Latitude:  38.8977,
+		Longitude: -77.0365,
+	}
+
+	require.Nil(t, address.ParseFormatted(""))
+	require.Equal(t, Address{
+		Name:       "White House",
+		Formatted:  "1600 Pennsylvania Ave NW, Washington, DC 20500",
+		Street1:    "1600 Pennsylvania Ave NW",
+		Locality:   "Washington",
+		Region:     "DC",
+		PostalCode: "20500",
+		Country:    "US",
+		Latitude:   38.8977,
+		Longitude:  -77.0365,
+	}, address)
+
+	require.NotNil(t, (&Address{}).ParseFormatted("US"))
+}
@@ -0,0 +1,171 @@
+package geo
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+)
+
+// Benchmarks for the JSON and BSON decoders. Run with:
+//
+//	go test -run=^$ -bench=Unmarshal -benchmem
+
+func BenchmarkPosition_UnmarshalJSON(b *testing.B) {
+
+	data := []byte(`[-79.9822,40.4461,250]`)
+	b.ReportAllocs()
+
+	for b.Loop() {
+		position := Position{}
+		if err := position.UnmarshalJSON(data); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkPoint_UnmarshalJSON(b *testing.B) {
+
+	data := []byte(`{"type":"Point","coordinates":[-79.9822,40.4461]}`)
+	b.ReportAllocs()
+
+	for b.Loop() {
+		point := Point{}
+		if err := point.UnmarshalJSON(data); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkPolygon_UnmarshalJSON(b *testing.B) {
+
+	data := []byte(`{"type":"Polygon","coordinates":[[[-80,40],[-79,40],[-79,41],[-80,41],[-80,40]]]}`)
+	b.ReportAllocs()
+
+	for b.Loop() {
+		polygon := Polygon{}
+		if err := polygon.UnmarshalJSON(data); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkPosition_UnmarshalBSONValue(b *testing.B) {
+
+	dataType, data, err := NewPositionWithAltitude(-79.9822, 40.4461, 250).MarshalBSONValue()
+
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ReportAllocs()
+
+	for b.Loop() {
+		position := Position{}
+		if err := position.UnmarshalBSONValue(bsontype.Type(dataType), data); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkPoint_UnmarshalBSON(b *testing.B) {
+
+	data, err := bson.Marshal(NewPoint(-79.9822, 40.4461))
+
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ReportAllocs()
+
+	for b.Loop() {
+		point := Point{}
+		if err := point.UnmarshalBSON(data); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkPolygon_UnmarshalBSON(b *testing.B) {
+
+	data, err := bson.Marshal(NewPolygon(NewPosition(-80, 40), NewPosition(-79, 40), NewPosition(-79, 41), NewPosition(-80, 41), NewPosition(-80, 40)))
+
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ReportAllocs()
+
+	for b.Loop() {
+		polygon := Polygon{}
+		if err := polygon.UnmarshalBSON(data); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// Offline reverse geocoding, against 250 country-sized polygons of 2,000 vertices each. Run with:
+//
+//	go test -run=^$ -bench=ReverseCountry -benchmem
+
+func BenchmarkBoundaryIndex_ReverseCountry(b *testing.B) {
+
+	var table strings.Builder
+
+	for index, country := range Countries() {
+
+		// A circle of 2,000 vertices, on a grid of ten-degree cells
+		center := NewPosition(-175+float64(index%35)*10, -80+float64(index/35)*20)
+		ring := make([]Position, 2000)
+
+		for vertex := range ring {
+			angle := 2 * math.Pi * float64(vertex) / float64(len(ring))
+			ring[vertex] = NewPosition(center.Longitude+4.9*math.Cos(angle), center.Latitude+4.9*math.Sin(angle))
+		}
+
+		table.WriteString(country.Alpha2 + "\t" + mustEncodePolyline(ring, boundaryPrecision) + "\n")
+	}
+
+	index, err := NewBoundaryIndex(strings.NewReader(table.String()))
+
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	position := NewPosition(-175+2, -80+1)
+	b.ReportAllocs()
+
+	for b.Loop() {
+		if _, err := index.ReverseCountry(position); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// Offline reverse geocoding against the embedded Natural Earth boundaries, which
+// should take well under a millisecond per lookup
+
+func BenchmarkReverseCountry(b *testing.B) {
+
+	positions := []Position{
+		NewPosition(-104.99, 39.74), // Denver
+		NewPosition(2.35, 48.86),    // Paris
+		NewPosition(139.69, 35.69),  // Tokyo
+		NewPosition(-43.2, -22.91),  // Rio de Janeiro
+		NewPosition(-30, 0),         // The middle of the Atlantic
+	}
+
+	// Parse the embedded table before timing any lookups
+	defaultBoundaryIndex()
+	b.ReportAllocs()
+
+	for b.Loop() {
+		for _, position := range positions {
+			_, _ = ReverseCountry(position)
+		}
+	}
+
+	b.ReportMetric(float64(b.Elapsed().Nanoseconds())/float64(b.N*len(positions)), "ns/lookup")
+}
@@ -0,0 +1,207 @@
+package geo
+
+import (
+	"bytes"
+	"cmp"
+	"compress/gzip"
+	"context"
+	_ "embed"
+	"io"
+	"math"
+	"strings"
+	"sync"
+
+	"github.com/benpate/derp"
+)
+
+//go:generate go run boundary_generate.go -countries ne_10m_admin_0_countries.geojson -regions ne_10m_admin_1_states_provinces.geojson -out data/boundaries.tsv.gz
+
+// The embedded boundary table is generated from Natural Earth's admin-0 (country) and
+// admin-1 (state and province) boundaries by boundary_generate.go. Each line holds an
+// ISO 3166 code ("US" or "US-CA") and one polygon: its outer ring, then any holes, each
+// written as an encoded polyline. Regions made of several polygons use several lines.
+// The table is gzipped.
+
+//go:embed data/boundaries.tsv.gz
+var boundariesTSVGZ []byte
+
+// CountryRegion is the result of an offline reverse geocode: the country, and the
+// state or province (if known), that contain a position
+type CountryRegion struct {
+	Country Country     // ISO 3166-1 codes and names of the country
+	Region  Subdivision // ISO 3166-2 code and name of the state or province, or a zero value if unknown
+}
+
+// BoundaryIndex finds the country and region that contain a position, using
+// a table of simplified boundaries and a grid of one-degree cells. It is safe
+// for concurrent use once it has been created.
+type BoundaryIndex struct {
+	table polygonTable
+}
+
+// defaultBoundaryIndex parses the embedded boundary table the first time it is used
+var defaultBoundaryIndex = sync.OnceValue(func() *BoundaryIndex {
+
+	reader, err := gzip.NewReader(bytes.NewReader(boundariesTSVGZ))
+
+	if err != nil {
+		panic("geo: invalid embedded boundary table: " + err.Error())
+	}
+
+	result, err := NewBoundaryIndex(reader)
+
+	if err != nil {
+		panic("geo: invalid embedded boundary table: " + err.Error())
+	}
+
+	return result
+})
+
+// NewBoundaryIndex reads a (decompressed) boundary table in the same format as the embedded one
+// (see boundary_generate.go), such as a more detailed table that you generate yourself.
+func NewBoundaryIndex(reader io.Reader) (*BoundaryIndex, error) {
+
+	const location = "geo.NewBoundaryIndex"
+
+	table, err := readPolygonTable(reader)
+
+	if err != nil {
+		return nil, derp.Wrap(err, location, "Unable to read boundary table")
+	}
+
+	return &BoundaryIndex{table: table}, nil
+}
+
+// Len returns the number of polygons in this BoundaryIndex
+func (index *BoundaryIndex) Len() int {
+	return len(index.table.polygons)
+}
+
+// ReverseCountry returns the country (and, when known, the state or province) that
+// contains a position. It returns a "not found" error for positions that are not inside
+// any country, such as positions in the ocean.
+func (index *BoundaryIndex) ReverseCountry(position Position) (CountryRegion, error) {
+
+	const location = "geo.BoundaryIndex.ReverseCountry"
+
+	if math.IsNaN(position.Latitude) || (position.Latitude < -90) || (position.Latitude > 90) {
+		return CountryRegion{}, derp.Internal(location, "Latitude must be between -90 and 90", position)
+	}
+
+	if math.IsNaN(position.Longitude) || (position.Longitude < -180) || (position.Longitude > 180) {
+		return CountryRegion{}, derp.Internal(location, "Longitude must be between -180 and 180", position)
+	}
+
+	var countryCode string
+	var regionCode string
+
+	for code := range index.table.containing(position) {
+
+		if strings.Contains(code, "-") {
+			regionCode = cmp.Or(regionCode, code)
+		} else {
+			countryCode = cmp.Or(countryCode, code)
+		}
+
+		if (countryCode != "") && (regionCode != "") {
+			break
+		}
+	}
+
+	// Use the region's country when the (more simplified) country boundaries miss the position
+	if countryCode == "" {
+		countryCode, _, _ = strings.Cut(regionCode, "-")
+	}
+
+	country, ok := LookupCountry(countryCode)
+
+	if !ok {
+		return CountryRegion{}, derp.NotFound(location, "No country at position", position)
+	}
+
+	result := CountryRegion{Country: country}
+
+	// Only use regions inside the same country, in case two simplified boundaries overlap
+	if region, ok := LookupSubdivision(country.Alpha2, regionCode); ok && (region.Country == country.Alpha2) {
+		result.Region = region
+	}
+
+	return result, nil
+}
+
+// ReverseCountry returns the country (and, when known, the state or province) that
+// contains a position, using the embedded boundary table. No network access is needed.
+// It returns a "not found" error for positions that are not inside any country.
+func ReverseCountry(position Position) (CountryRegion, error) {
+
+	const location = "geo.ReverseCountry"
+
+	index := defaultBoundaryIndex()
+
+	// Without boundaries, every position would look like it is in the ocean
+	if index.Len() == 0 {
+		return CountryRegion{}, derp.NotImplemented(location, "The embedded boundary table is empty. Run `go generate` to build it.")
+	}
+
+	return index.ReverseCountry(position)
+}
+
+// UpdateCountry sets the Country (as an ISO 3166-1 alpha-2 code) and Region (as the local
+// part of an ISO 3166-2 code, like Normalize) of this Address from its coordinates, using
+// the embedded boundary table. The Region is only changed when the boundary table includes
+// it. It returns an error, and leaves the Address unchanged, if the Address has no
+// coordinates or they are not inside any country.
+func (address *Address) UpdateCountry() error {
+
+	const location = "geo.Address.UpdateCountry"
+
+	if !address.HasGeocode() {
+		return derp.Validation("Address must have coordinates to find its country")
+	}
+
+	result, err := ReverseCountry(address.GeoPoint().Position)
+
+	if err != nil {
+		return derp.Wrap(err, location, "Unable to find country", address.LatLon())
+	}
+
+	address.Country = result.Country.Alpha2
+
+	if result.Region.Code != "" {
+		address.Region = result.Region.LocalCode()
+	}
+
+	return nil
+}
+
+// OfflineReverseGeocoder is a ReverseGeocoder that only finds the country and region
+// of a Point, using a BoundaryIndex instead of a network service
+type OfflineReverseGeocoder struct {
+	Index *BoundaryIndex // Boundaries to search (the embedded boundary table when nil)
+}
+
+// ReverseGeocode implements the ReverseGeocoder interface
+func (geocoder OfflineReverseGeocoder) ReverseGeocode(_ context.Context, point Point) (Address, error) {
+
+	const location = "geo.OfflineReverseGeocoder.ReverseGeocode"
+
+	var result CountryRegion
+	var err error
+
+	if geocoder.Index == nil {
+		result, err = ReverseCountry(point.Position)
+	} else {
+		result, err = geocoder.Index.ReverseCountry(point.Position)
+	}
+
+	if err != nil {
+		return Address{}, derp.Wrap(err, location, "Unable to find country", point.LatLon())
+	}
+
+	return Address{
+		Region:    result.Region.LocalCode(),
+		Country:   result.Country.Alpha2,
+		Latitude:  point.Latitude,
+		Longitude: point.Longitude,
+	}, nil
+}
@@ -0,0 +1,295 @@
+//go:build ignore
+
+// boundary_generate.go builds the embedded, gzipped boundary table (data/boundaries.tsv.gz) from
+// Natural Earth's admin-0 and admin-1 boundaries, which are in the public domain:
+// https://www.naturalearthdata.com/downloads/
+//
+// It also builds the embedded, gzipped time zone table (data/timezones.tsv.gz) from
+// timezone-boundary-builder's "timezones-with-oceans.geojson" release (ODbL):
+// https://github.com/evansiroky/timezone-boundary-builder/releases
+//
+// Download the GeoJSON versions of "Admin 0 - Countries" and "Admin 1 - States,
+// Provinces" (for example, from https://github.com/nvkelso/natural-earth-vector/tree/master/geojson)
+// and the time zone boundaries into this directory, then run:
+//
+//	go generate
+//
+// Boundaries are simplified with the Douglas-Peucker algorithm. Use -tolerance to trade
+// accuracy near borders for a smaller table.
+package main
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"math"
+	"os"
+	"strings"
+
+	"github.com/benpate/geo"
+)
+
+// featureCollection is the part of a Natural Earth GeoJSON file that we use
+type featureCollection struct {
+	Features []struct {
+		Properties map[string]any `json:"properties"`
+		Geometry   struct {
+			Type        string          `json:"type"`
+			Coordinates json.RawMessage `json:"coordinates"`
+		} `json:"geometry"`
+	} `json:"features"`
+}
+
+func main() {
+
+	countries := flag.String("countries", "", "Natural Earth admin-0 countries (GeoJSON)")
+	regions := flag.String("regions", "", "Natural Earth admin-1 states and provinces (GeoJSON)")
+	timezones := flag.String("timezones", "", "timezone-boundary-builder time zones (GeoJSON)")
+	tolerance := flag.Float64("tolerance", 0.02, "Simplification tolerance, in degrees")
+	output := flag.String("out", "data/boundaries.tsv.gz", "Output file for countries and regions (gzipped)")
+	timezonesOutput := flag.String("timezones-out", "data/timezones.tsv.gz", "Output file for time zones (gzipped)")
+	flag.Parse()
+
+	if (*countries != "") || (*regions != "") {
+		create(*output, func(writer *bufio.Writer) {
+
+			fmt.Fprintln(writer, "# Simplified country (admin-0) and region (admin-1) boundaries, from Natural Earth (public domain).")
+			fmt.Fprintln(writer, "# Generated by boundary_generate.go. Each line is an ISO 3166 code, then a polygon's outer ring and")
+			fmt.Fprintln(writer, "# holes as encoded polylines (precision 4).")
+
+			if *countries != "" {
+				write(writer, *countries, *tolerance, isoCode("ISO_A2_EH", "ISO_A2"))
+			}
+
+			if *regions != "" {
+				write(writer, *regions, *tolerance, isoCode("iso_3166_2"))
+			}
+		})
+	}
+
+	if *timezones != "" {
+		create(*timezonesOutput, func(writer *bufio.Writer) {
+
+			fmt.Fprintln(writer, "# Simplified time zone boundaries, from timezone-boundary-builder (ODbL).")
+			fmt.Fprintln(writer, "# Generated by boundary_generate.go. Each line is a tz database name, then a polygon's outer ring")
+			fmt.Fprintln(writer, "# and holes as encoded polylines (precision 4).")
+
+			write(writer, *timezones, *tolerance, func(properties map[string]any) string {
+				name, _ := properties["tzid"].(string)
+				return name
+			})
+		})
+	}
+}
+
+// create writes a gzipped file
+func create(filename string, contents func(*bufio.Writer)) {
+
+	file, err := os.Create(filename)
+
+	if err != nil {
+		fail(err)
+	}
+
+	zipper, _ := gzip.NewWriterLevel(file, gzip.BestCompression)
+	writer := bufio.NewWriter(zipper)
+	contents(writer)
+
+	if err := writer.Flush(); err != nil {
+		fail(err)
+	}
+
+	if err := zipper.Close(); err != nil {
+		fail(err)
+	}
+
+	if err := file.Close(); err != nil {
+		fail(err)
+	}
+}
+
+// isoCode returns a function that finds the first of the named properties that holds a valid ISO 3166 code
+func isoCode(properties ...string) func(map[string]any) string {
+
+	return func(values map[string]any) string {
+
+		for _, property := range properties {
+			if value, ok := values[property].(string); ok && isCode(value) {
+				return value
+			}
+		}
+
+		return ""
+	}
+}
+
+// write adds every feature in a GeoJSON file to a polygon table, named by the
+// name function. Features without a name are skipped.
+func write(writer *bufio.Writer, filename string, tolerance float64, name func(map[string]any) string) {
+
+	data, err := os.ReadFile(filename)
+
+	if err != nil {
+		fail(err)
+	}
+
+	var collection featureCollection
+
+	if err := json.Unmarshal(data, &collection); err != nil {
+		fail(err)
+	}
+
+	for _, feature := range collection.Features {
+
+		code := name(feature.Properties)
+
+		if code == "" {
+			continue
+		}
+
+		var polygons [][][][]float64
+
+		switch feature.Geometry.Type {
+
+		case "Polygon":
+			var polygon [][][]float64
+			if err := json.Unmarshal(feature.Geometry.Coordinates, &polygon); err != nil {
+				fail(err)
+			}
+			polygons = append(polygons, polygon)
+
+		case "MultiPolygon":
+			if err := json.Unmarshal(feature.Geometry.Coordinates, &polygons); err != nil {
+				fail(err)
+			}
+		}
+
+		for _, polygon := range polygons {
+
+			rings := make([]string, 0, len(polygon))
+
+			for _, ring := range polygon {
+
+				// Skip rings that are too small to keep. When the outer ring is skipped, so is the whole polygon.
+				positions := simplify(ringPositions(ring), tolerance)
+
+				if len(positions) < 4 {
+					if len(rings) == 0 {
+						break
+					}
+					continue
+				}
+
+				ring, err := geo.EncodePolyline(positions, 4)
+
+				if err != nil {
+					fail(err)
+				}
+
+				rings = append(rings, ring)
+			}
+
+			if len(rings) > 0 {
+				fmt.Fprintln(writer, code+"\t"+strings.Join(rings, "\t"))
+			}
+		}
+	}
+}
+
+// ringPositions converts GeoJSON coordinates into Positions
+func ringPositions(ring [][]float64) []geo.Position {
+
+	result := make([]geo.Position, 0, len(ring))
+
+	for _, coordinates := range ring {
+		result = append(result, geo.NewPosition(coordinates[0], coordinates[1]))
+	}
+
+	return result
+}
+
+// isCode returns TRUE for ISO 3166-1 alpha-2 codes ("US") and ISO 3166-2 codes ("US-CA").
+// Natural Earth uses "-99" (and sometimes codes like "FR-") for boundaries without a code.
+func isCode(value string) bool {
+
+	country, subdivision, hasSubdivision := strings.Cut(value, "-")
+
+	if len(country) != 2 {
+		return false
+	}
+
+	if hasSubdivision {
+		_, ok := geo.LookupSubdivision(country, value)
+		return ok && (subdivision != "")
+	}
+
+	_, ok := geo.LookupCountry(country)
+	return ok
+}
+
+// simplify removes vertices that are within tolerance of the line between their
+// neighbors, using the Douglas-Peucker algorithm
+func simplify(positions []geo.Position, tolerance float64) []geo.Position {
+
+	if len(positions) < 3 {
+		return positions
+	}
+
+	keep := make([]bool, len(positions))
+	keep[0] = true
+	keep[len(positions)-1] = true
+
+	var mark func(first int, last int)
+
+	mark = func(first int, last int) {
+
+		farthest, distance := -1, tolerance
+
+		for index := first + 1; index < last; index++ {
+			if d := segmentDistance(positions[index], positions[first], positions[last]); d > distance {
+				farthest, distance = index, d
+			}
+		}
+
+		if farthest >= 0 {
+			keep[farthest] = true
+			mark(first, farthest)
+			mark(farthest, last)
+		}
+	}
+
+	mark(0, len(positions)-1)
+
+	result := make([]geo.Position, 0, len(positions))
+
+	for index, position := range positions {
+		if keep[index] {
+			result = append(result, position)
+		}
+	}
+
+	return result
+}
+
+// segmentDistance returns the distance (in degrees) from a position to the segment between a and b
+func segmentDistance(position geo.Position, a geo.Position, b geo.Position) float64 {
+
+	dx, dy := b.Longitude-a.Longitude, b.Latitude-a.Latitude
+
+	if (dx == 0) && (dy == 0) {
+		return math.Hypot(position.Longitude-a.Longitude, position.Latitude-a.Latitude)
+	}
+
+	t := ((position.Longitude-a.Longitude)*dx + (position.Latitude-a.Latitude)*dy) / (dx*dx + dy*dy)
+	t = max(0, min(t, 1))
+
+	return math.Hypot(position.Longitude-(a.Longitude+t*dx), position.Latitude-(a.Latitude+t*dy))
+}
+
+// fail reports an error and exits
+func fail(err error) {
+	fmt.Fprintln(os.Stderr, "boundary_generate:", err)
+	os.Exit(1)
+}
@@ -0,0 +1,223 @@
+package geo
+
+import (
+	"context"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/benpate/derp"
+	"github.com/stretchr/testify/require"
+)
+
+// testBoundaryTable returns a boundary table of rough rectangles, which are only test fixtures
+// (not real boundaries). South Africa has a hole for Lesotho, and Colorado is a region of the US.
+func testBoundaryTable() string {
+
+	box := func(west float64, south float64, east float64, north float64) string {
+		return mustEncodePolyline(NewBoundingBox(west, south, east, north).Polygon().Coordinates, boundaryPrecision)
+	}
+
+	return strings.Join([]string{
+		"# Test fixtures",
+		"US\t" + box(-125, 24, -66, 49),
+		"US\t" + box(-170, 52, -130, 71),
+		"US-CO\t" + box(-109.05, 37, -102.04, 41),
+		"CA\t" + box(-141, 49, -52, 83),
+		"ZA\t" + box(16, -35, 33, -22) + "\t" + box(27, -30.7, 29.5, -28.5),
+		"LS\t" + box(27, -30.7, 29.5, -28.5),
+		"",
+	}, "\n")
+}
+
+func testBoundaryIndex(t *testing.T) *BoundaryIndex {
+	index, err := NewBoundaryIndex(strings.NewReader(testBoundaryTable()))
+	require.Nil(t, err)
+	return index
+}
+
+func TestBoundaryIndex_ReverseCountry(t *testing.T) {
+
+	index := testBoundaryIndex(t)
+	require.Equal(t, 6, index.Len())
+
+	// Denver, Colorado
+	result, err := index.ReverseCountry(NewPosition(-104.99, 39.74))
+	require.Nil(t, err)
+	require.Equal(t, "US", result.Country.Alpha2)
+	require.Equal(t, "USA", result.Country.Alpha3)
+	require.Equal(t, "840", result.Country.Numeric)
+	require.Equal(t, "US-CO", result.Region.Code)
+	require.Equal(t, "Colorado", result.Region.Name)
+
+	// Anchorage, in the second polygon of the US, with no region
+	result, err = index.ReverseCountry(NewPosition(-149.9, 61.2))
+	require.Nil(t, err)
+	require.Equal(t, "US", result.Country.Alpha2)
+	require.Equal(t, Subdivision{}, result.Region)
+
+	result, err = index.ReverseCountry(NewPosition(-75.7, 51))
+	require.Nil(t, err)
+	require.Equal(t, "CA", result.Country.Alpha2)
+	require.Equal(t, "Canada", result.Country.Name)
+
+	// Maseru is in Lesotho, inside the hole in South Africa
+	result, err = index.ReverseCountry(NewPosition(27.48, -29.31))
+	require.Nil(t, err)
+	require.Equal(t, "LS", result.Country.Alpha2)
+
+	result, err = index.ReverseCountry(NewPosition(18.42, -33.92))
+	require.Nil(t, err)
+	require.Equal(t, "ZA", result.Country.Alpha2)
+
+	// The middle of the Atlantic
+	_, err = index.ReverseCountry(NewPosition(-30, 0))
+	require.True(t, derp.IsNotFound(err))
+}
+
+func TestBoundaryIndex_ReverseCountry_Invalid(t *testing.T) {
+
+	index := testBoundaryIndex(t)
+
+	for _, position := range []Position{
+		NewPosition(-500, 500),
+		NewPosition(0, 91),
+		NewPosition(0, -91),
+		NewPosition(181, 0),
+		NewPosition(-181, 0),
+		NewPosition(math.NaN(), 0),
+		NewPosition(0, math.NaN()),
+	} {
+		_, err := index.ReverseCountry(position)
+		require.NotNil(t, err, position)
+		require.False(t, derp.IsNotFound(err), position)
+	}
+}
+
+func TestBoundaryIndex_Errors(t *testing.T) {
+
+	_, err := NewBoundaryIndex(strings.NewReader("US\n"))
+	require.NotNil(t, err)
+
+	_, err = NewBoundaryIndex(strings.NewReader("US\t!!!\n"))
+	require.NotNil(t, err)
+
+	_, err = NewBoundaryIndex(strings.NewReader("US\t" + mustEncodePolyline([]Position{{}, {Longitude: 1}}, boundaryPrecision) + "\n"))
+	require.NotNil(t, err)
+
+	index, err := NewBoundaryIndex(strings.NewReader("# Empty\n\n"))
+	require.Nil(t, err)
+	require.Equal(t, 0, index.Len())
+}
+
+func TestReverseCountry_EmptyTable(t *testing.T) {
+
+	original := defaultBoundaryIndex
+	defaultBoundaryIndex = func() *BoundaryIndex { return &BoundaryIndex{} }
+	defer func() { defaultBoundaryIndex = original }()
+
+	// Without boundaries, land positions must not be reported as "not found"
+	_, err := ReverseCountry(NewPosition(-104.99, 39.74))
+	require.True(t, derp.IsNotImplemented(err))
+
+	address := Address{Country: "Atlantis", Latitude: 39.74, Longitude: -104.99}
+	require.True(t, derp.IsNotImplemented(address.UpdateCountry()))
+	require.Equal(t, "Atlantis", address.Country)
+
+	_, err = OfflineReverseGeocoder{}.ReverseGeocode(context.Background(), NewPoint(-104.99, 39.74))
+	require.True(t, derp.IsNotImplemented(err))
+}
+
+func TestDefaultBoundaryIndex(t *testing.T) {
+
+	// The embedded table must always decompress and parse, and must not be empty
+	index := defaultBoundaryIndex()
+	require.NotNil(t, index)
+	require.Greater(t, index.Len(), 0)
+}
+
+func TestReverseCountry(t *testing.T) {
+
+	// These use the embedded Natural Earth boundaries
+	tests := []struct {
+		longitude float64
+		latitude  float64
+		country   string
+		region    string
+	}{
+		{-104.99, 39.74, "US", "US-CO"},  // Denver
+		{-149.9, 61.22, "US", "US-AK"},   // Anchorage
+		{-73.57, 45.5, "CA", "CA-QC"},    // Montreal
+		{-103.35, 20.67, "MX", "MX-JAL"}, // Guadalajara
+		{2.35, 48.86, "FR", ""},          // Paris
+		{-0.13, 51.51, "GB", ""},         // London
+		{13.4, 52.52, "DE", "DE-BE"},     // Berlin
+		{27.48, -29.31, "LS", ""},        // Maseru, inside South Africa
+		{18.42, -33.92, "ZA", "ZA-WC"},   // Cape Town
+		{139.69, 35.69, "JP", "JP-13"},   // Tokyo
+		{151.21, -33.87, "AU", "AU-NSW"}, // Sydney
+		{-43.2, -22.91, "BR", "BR-RJ"},   // Rio de Janeiro
+	}
+
+	for _, test := range tests {
+
+		result, err := ReverseCountry(NewPosition(test.longitude, test.latitude))
+		require.Nil(t, err, test)
+		require.Equal(t, test.country, result.Country.Alpha2, test)
+
+		if test.region != "" {
+			require.Equal(t, test.region, result.Region.Code, test)
+		}
+	}
+
+	// Positions in the ocean are not inside any country
+	for _, position := range []Position{
+		NewPosition(-30, 0),    // The middle of the Atlantic
+		NewPosition(-140, -20), // The South Pacific
+		NewPosition(80, -30),   // The Indian Ocean
+		NewPosition(-45, 40),   // The North Atlantic
+	} {
+		_, err := ReverseCountry(position)
+		require.True(t, derp.IsNotFound(err), position)
+	}
+}
+
+func TestAddress_UpdateCountry(t *testing.T) {
+
+	original := defaultBoundaryIndex
+	index := testBoundaryIndex(t)
+	defaultBoundaryIndex = func() *BoundaryIndex { return index }
+	defer func() { defaultBoundaryIndex = original }()
+
+	address := Address{Region: "Somewhere", Latitude: 39.74, Longitude: -104.99}
+	require.Nil(t, address.UpdateCountry())
+	require.Equal(t, "US", address.Country)
+	require.Equal(t, "CO", address.Region)
+
+	// Regions are kept when the table does not have one
+	address = Address{Region: "Alaska", Latitude: 61.2, Longitude: -149.9}
+	require.Nil(t, address.UpdateCountry())
+	require.Equal(t, "US", address.Country)
+	require.Equal(t, "Alaska", address.Region)
+
+	// Addresses without coordinates, or in the ocean, are not changed
+	address = Address{Country: "Atlantis"}
+	require.True(t, derp.IsValidationError(address.UpdateCountry()))
+	require.Equal(t, "Atlantis", address.Country)
+
+	address = Address{Country: "Atlantis", Latitude: 0, Longitude: -30}
+	require.True(t, derp.IsNotFound(address.UpdateCountry()))
+	require.Equal(t, "Atlantis", address.Country)
+}
+
+func TestOfflineReverseGeocoder(t *testing.T) {
+
+	geocoder := OfflineReverseGeocoder{Index: testBoundaryIndex(t)}
+
+	address, err := geocoder.ReverseGeocode(context.Background(), NewPoint(-104.99, 39.74))
+	require.Nil(t, err)
+	require.Equal(t, Address{Region: "CO", Country: "US", Latitude: 39.74, Longitude: -104.99}, address)
+
+	_, err = geocoder.ReverseGeocode(context.Background(), NewPoint(-30, 0))
+	require.True(t, derp.IsNotFound(err))
+}
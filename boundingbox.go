@@ -0,0 +1,84 @@
+package geo
+
+// BoundingBox represents a rectangular area bounded by two longitudes and two latitudes.
+// BoundingBoxes do not cross the antimeridian, so West is always less than or equal to East.
+// https://datatracker.ietf.org/doc/html/rfc7946#section-5
+type BoundingBox struct {
+	West  float64 // Minimum longitude
+	South float64 // Minimum latitude
+	East  float64 // Maximum longitude
+	North float64 // Maximum latitude
+}
+
+// NewBoundingBox returns a BoundingBox with the given edges
+func NewBoundingBox(west float64, south float64, east float64, north float64) BoundingBox {
+	return BoundingBox{
+		West:  west,
+		South: south,
+		East:  east,
+		North: north,
+	}
+}
+
+// NewBoundingBoxFromPositions returns the smallest BoundingBox that contains
+// all of the given positions, or a zero BoundingBox if there are none.
+func NewBoundingBoxFromPositions(positions ...Position) BoundingBox {
+
+	if len(positions) == 0 {
+		return BoundingBox{}
+	}
+
+	result := NewBoundingBox(positions[0].Longitude, positions[0].Latitude, positions[0].Longitude, positions[0].Latitude)
+
+	for _, position := range positions[1:] {
+		result.West = min(result.West, position.Longitude)
+		result.South = min(result.South, position.Latitude)
+		result.East = max(result.East, position.Longitude)
+		result.North = max(result.North, position.Latitude)
+	}
+
+	return result
+}
+
+// IsZero returns TRUE if this is a zero BoundingBox
+func (box BoundingBox) IsZero() bool {
+	return (box.West == 0) && (box.South == 0) && (box.East == 0) && (box.North == 0)
+}
+
+// NotZero returns TRUE if this BoundingBox is not zero
+func (box BoundingBox) NotZero() bool {
+	return !box.IsZero()
+}
+
+// Contains returns TRUE if the Position is inside of (or on the edge of) this BoundingBox
+func (box BoundingBox) Contains(position Position) bool {
+	return (position.Longitude >= box.West) && (position.Longitude <= box.East) &&
+		(position.Latitude >= box.South) && (position.Latitude <= box.North)
+}
+
+// Intersects returns TRUE if this BoundingBox shares any area (or edge) with another
+func (box BoundingBox) Intersects(other BoundingBox) bool {
+	return (box.West <= other.East) && (other.West <= box.East) &&
+		(box.South <= other.North) && (other.South <= box.North)
+}
+
+// Center returns the Position in the middle of this BoundingBox
+func (box BoundingBox) Center() Position {
+	return NewPosition((box.West+box.East)/2, (box.South+box.North)/2)
+}
+
+// Polygon returns this BoundingBox as a closed, counter-clockwise Polygon
+func (box BoundingBox) Polygon() Polygon {
+	return NewPolygon(
+		NewPosition(box.West, box.South),
+		NewPosition(box.East, box.South),
+		NewPosition(box.East, box.North),
+		NewPosition(box.West, box.North),
+		NewPosition(box.West, box.South),
+	)
+}
+
+// MarshalSlice returns this BoundingBox as a GeoJSON "bbox" array: [west, south, east, north]
+func (box BoundingBox) MarshalSlice() []float64 {
+	return []float64{box.West, box.South, box.East, box.North}
+}
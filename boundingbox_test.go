@@ -0,0 +1,58 @@
+package geo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewBoundingBoxFromPositions(t *testing.T) {
+
+	require.True(t, NewBoundingBoxFromPositions().IsZero())
+
+	result := NewBoundingBoxFromPositions(
+		NewPosition(1, 5),
+		NewPosition(-3, 2),
+		NewPosition(4, -1),
+	)
+
+	require.Equal(t, NewBoundingBox(-3, -1, 4, 5), result)
+	require.True(t, result.NotZero())
+}
+
+func TestBoundingBox_Contains(t *testing.T) {
+
+	box := NewBoundingBox(-10, -5, 10, 5)
+
+	require.True(t, box.Contains(NewPosition(0, 0)))
+	require.True(t, box.Contains(NewPosition(10, 5)))
+	require.False(t, box.Contains(NewPosition(11, 0)))
+	require.False(t, box.Contains(NewPosition(0, -6)))
+}
+
+func TestBoundingBox_Intersects(t *testing.T) {
+
+	box := NewBoundingBox(-10, -5, 10, 5)
+
+	require.True(t, box.Intersects(NewBoundingBox(5, 0, 15, 10)))
+	require.True(t, box.Intersects(NewBoundingBox(10, 5, 15, 10)))
+	require.True(t, box.Intersects(NewBoundingBox(-1, -1, 1, 1)))
+	require.False(t, box.Intersects(NewBoundingBox(11, 0, 15, 10)))
+}
+
+func TestBoundingBox_Conversions(t *testing.T) {
+
+	box := NewBoundingBox(-10, -5, 10, 5)
+
+	require.Equal(t, NewPosition(0, 0), box.Center())
+	require.Equal(t, []float64{-10, -5, 10, 5}, box.MarshalSlice())
+	require.Equal(t, box, box.Polygon().BoundingBox())
+	require.Equal(t, 5, len(box.Polygon().Coordinates))
+}
+
+func TestGeometry_BoundingBox(t *testing.T) {
+
+	require.Equal(t, NewBoundingBox(1, 2, 1, 2), NewPoint(1, 2).BoundingBox())
+	require.Equal(t, NewBoundingBox(1, 2, 3, 4), NewLineString(NewPosition(1, 4), NewPosition(3, 2)).BoundingBox())
+	require.Equal(t, NewBoundingBox(1, 2, 3, 4), NewPolygon(NewPosition(1, 4), NewPosition(3, 2)).BoundingBox())
+}
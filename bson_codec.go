@@ -0,0 +1,536 @@
+package geo
+
+import (
+	"reflect"
+
+	"github.com/benpate/derp"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsoncodec"
+	"go.mongodb.org/mongo-driver/bson/bsonrw"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+)
+
+// Reflected types that receive custom BSON codecs
+var (
+	typePosition   = reflect.TypeOf(Position{})
+	typePoint      = reflect.TypeOf(Point{})
+	typeLineString = reflect.TypeOf(LineString{})
+	typePolygon    = reflect.TypeOf(Polygon{})
+)
+
+// NewBSONRegistry returns a copy of the default BSON registry, with codecs for
+// every geometry type in this package. Pass it to the MongoDB driver with
+// options.Client().SetRegistry()
+func NewBSONRegistry() *bsoncodec.Registry {
+	registry := bson.NewRegistry()
+	RegisterBSONCodecs(registry)
+	return registry
+}
+
+// RegisterBSONCodecs adds ValueEncoders and ValueDecoders for Position, Point,
+// LineString, and Polygon to an existing registry. These codecs read and write
+// BSON directly, without the intermediate maps and structs used by
+// MarshalBSON / UnmarshalBSON. Like MarshalJSON, zero-valued Points, LineStrings,
+// and Polygons are written as BSON null (or omitted entirely by `omitempty`),
+// and null values decode into zero values.
+func RegisterBSONCodecs(registry *bsoncodec.Registry) {
+
+	registry.RegisterTypeEncoder(typePosition, bsoncodec.ValueEncoderFunc(encodePositionValue))
+	registry.RegisterTypeEncoder(typePoint, bsoncodec.ValueEncoderFunc(encodePointValue))
+	registry.RegisterTypeEncoder(typeLineString, bsoncodec.ValueEncoderFunc(encodeLineStringValue))
+	registry.RegisterTypeEncoder(typePolygon, bsoncodec.ValueEncoderFunc(encodePolygonValue))
+
+	registry.RegisterTypeDecoder(typePosition, bsoncodec.ValueDecoderFunc(decodePositionValue))
+	registry.RegisterTypeDecoder(typePoint, bsoncodec.ValueDecoderFunc(decodePointValue))
+	registry.RegisterTypeDecoder(typeLineString, bsoncodec.ValueDecoderFunc(decodeLineStringValue))
+	registry.RegisterTypeDecoder(typePolygon, bsoncodec.ValueDecoderFunc(decodePolygonValue))
+}
+
+/******************************************
+ * Encoders
+ ******************************************/
+
+// encodePositionValue writes a Position as a GeoJSON coordinate array
+func encodePositionValue(_ bsoncodec.EncodeContext, writer bsonrw.ValueWriter, value reflect.Value) error {
+
+	const location = "geo.encodePositionValue"
+
+	if value.Type() != typePosition {
+		return derp.Internal(location, "Value must be a Position", value.Type().String())
+	}
+
+	return writeBSONPosition(writer, value.Interface().(Position))
+}
+
+// encodePointValue writes a Point as a GeoJSON object, or null if it is zero
+func encodePointValue(_ bsoncodec.EncodeContext, writer bsonrw.ValueWriter, value reflect.Value) error {
+
+	const location = "geo.encodePointValue"
+
+	if value.Type() != typePoint {
+		return derp.Internal(location, "Value must be a Point", value.Type().String())
+	}
+
+	point := value.Interface().(Point)
+
+	if point.IsZero() {
+		return writer.WriteNull()
+	}
+
+	return writeBSONGeometry(writer, PropertyTypePoint, func(coordinates bsonrw.ValueWriter) error {
+		return writeBSONPosition(coordinates, point.Position)
+	})
+}
+
+// encodeLineStringValue writes a LineString as a GeoJSON object, or null if it is zero
+func encodeLineStringValue(_ bsoncodec.EncodeContext, writer bsonrw.ValueWriter, value reflect.Value) error {
+
+	const location = "geo.encodeLineStringValue"
+
+	if value.Type() != typeLineString {
+		return derp.Internal(location, "Value must be a LineString", value.Type().String())
+	}
+
+	lineString := value.Interface().(LineString)
+
+	if lineString.IsZero() {
+		return writer.WriteNull()
+	}
+
+	return writeBSONGeometry(writer, PropertyTypeLineString, func(coordinates bsonrw.ValueWriter) error {
+		return writeBSONPositions(coordinates, lineString.Coordinates)
+	})
+}
+
+// encodePolygonValue writes a Polygon as a GeoJSON object, or null if it is zero
+func encodePolygonValue(_ bsoncodec.EncodeContext, writer bsonrw.ValueWriter, value reflect.Value) error {
+
+	const location = "geo.encodePolygonValue"
+
+	if value.Type() != typePolygon {
+		return derp.Internal(location, "Value must be a Polygon", value.Type().String())
+	}
+
+	polygon := value.Interface().(Polygon)
+
+	if polygon.IsZero() {
+		return writer.WriteNull()
+	}
+
+	return writeBSONGeometry(writer, PropertyTypePolygon, func(coordinates bsonrw.ValueWriter) error {
+
+		rings, err := coordinates.WriteArray()
+
+		if err != nil {
+			return err
+		}
+
+		ring, err := rings.WriteArrayElement()
+
+		if err != nil {
+			return err
+		}
+
+		if err := writeBSONPositions(ring, polygon.Coordinates); err != nil {
+			return err
+		}
+
+		return rings.WriteArrayEnd()
+	})
+}
+
+// writeBSONGeometry writes a GeoJSON object with a "type" and "coordinates",
+// using `writeCoordinates` to fill in the coordinates
+func writeBSONGeometry(writer bsonrw.ValueWriter, geometryType string, writeCoordinates func(bsonrw.ValueWriter) error) error {
+
+	const location = "geo.writeBSONGeometry"
+
+	document, err := writer.WriteDocument()
+
+	if err != nil {
+		return derp.Wrap(err, location, "Unable to write document")
+	}
+
+	element, err := document.WriteDocumentElement(PropertyType)
+
+	if err != nil {
+		return derp.Wrap(err, location, "Unable to write type")
+	}
+
+	if err := element.WriteString(geometryType); err != nil {
+		return derp.Wrap(err, location, "Unable to write type")
+	}
+
+	element, err = document.WriteDocumentElement(PropertyCoordinates)
+
+	if err != nil {
+		return derp.Wrap(err, location, "Unable to write coordinates")
+	}
+
+	if err := writeCoordinates(element); err != nil {
+		return derp.Wrap(err, location, "Unable to write coordinates")
+	}
+
+	return document.WriteDocumentEnd()
+}
+
+// writeBSONPositions writes a slice of Positions as an array of coordinate arrays
+func writeBSONPositions(writer bsonrw.ValueWriter, positions []Position) error {
+
+	array, err := writer.WriteArray()
+
+	if err != nil {
+		return err
+	}
+
+	for _, position := range positions {
+
+		element, err := array.WriteArrayElement()
+
+		if err != nil {
+			return err
+		}
+
+		if err := writeBSONPosition(element, position); err != nil {
+			return err
+		}
+	}
+
+	return array.WriteArrayEnd()
+}
+
+// writeBSONPosition writes a single Position as a coordinate array, matching MarshalSlice
+func writeBSONPosition(writer bsonrw.ValueWriter, position Position) error {
+
+	array, err := writer.WriteArray()
+
+	if err != nil {
+		return err
+	}
+
+	values := [3]float64{position.Longitude, position.Latitude, position.Altitude}
+	length := 2
+
+	if position.Altitude != 0 {
+		length = 3
+	}
+
+	for _, value := range values[:length] {
+
+		element, err := array.WriteArrayElement()
+
+		if err != nil {
+			return err
+		}
+
+		if err := element.WriteDouble(value); err != nil {
+			return err
+		}
+	}
+
+	return array.WriteArrayEnd()
+}
+
+/******************************************
+ * Decoders
+ ******************************************/
+
+// decodePositionValue reads a GeoJSON coordinate array into a Position
+func decodePositionValue(_ bsoncodec.DecodeContext, reader bsonrw.ValueReader, value reflect.Value) error {
+
+	const location = "geo.decodePositionValue"
+
+	if !value.CanSet() || (value.Type() != typePosition) {
+		return derp.Internal(location, "Value must be a settable Position", value.Type().String())
+	}
+
+	if reader.Type() == bsontype.Null {
+		value.Set(reflect.ValueOf(Position{}))
+		return reader.ReadNull()
+	}
+
+	position, err := readBSONPosition(reader)
+
+	if err != nil {
+		return derp.Wrap(err, location, "Unable to read position")
+	}
+
+	value.Set(reflect.ValueOf(position))
+	return nil
+}
+
+// decodePointValue reads a GeoJSON object (or null) into a Point
+func decodePointValue(_ bsoncodec.DecodeContext, reader bsonrw.ValueReader, value reflect.Value) error {
+
+	const location = "geo.decodePointValue"
+
+	if !value.CanSet() || (value.Type() != typePoint) {
+		return derp.Internal(location, "Value must be a settable Point", value.Type().String())
+	}
+
+	point := Point{}
+
+	err := readBSONGeometry(reader, PropertyTypePoint, func(coordinates bsonrw.ValueReader) error {
+		var err error
+		point.Position, err = readBSONPosition(coordinates)
+		return err
+	})
+
+	if err != nil {
+		return derp.Wrap(err, location, "Unable to read Point")
+	}
+
+	value.Set(reflect.ValueOf(point))
+	return nil
+}
+
+// decodeLineStringValue reads a GeoJSON object (or null) into a LineString
+func decodeLineStringValue(_ bsoncodec.DecodeContext, reader bsonrw.ValueReader, value reflect.Value) error {
+
+	const location = "geo.decodeLineStringValue"
+
+	if !value.CanSet() || (value.Type() != typeLineString) {
+		return derp.Internal(location, "Value must be a settable LineString", value.Type().String())
+	}
+
+	lineString := LineString{}
+
+	err := readBSONGeometry(reader, PropertyTypeLineString, func(coordinates bsonrw.ValueReader) error {
+		var err error
+		lineString.Coordinates, err = readBSONPositions(coordinates)
+		return err
+	})
+
+	if err != nil {
+		return derp.Wrap(err, location, "Unable to read LineString")
+	}
+
+	value.Set(reflect.ValueOf(lineString))
+	return nil
+}
+
+// decodePolygonValue reads a GeoJSON object (or null) into a Polygon, which must
+// contain exactly one ring of coordinates
+func decodePolygonValue(_ bsoncodec.DecodeContext, reader bsonrw.ValueReader, value reflect.Value) error {
+
+	const location = "geo.decodePolygonValue"
+
+	if !value.CanSet() || (value.Type() != typePolygon) {
+		return derp.Internal(location, "Value must be a settable Polygon", value.Type().String())
+	}
+
+	polygon := Polygon{}
+
+	err := readBSONGeometry(reader, PropertyTypePolygon, func(coordinates bsonrw.ValueReader) error {
+
+		rings, err := coordinates.ReadArray()
+
+		if err != nil {
+			return err
+		}
+
+		count := 0
+
+		for {
+			ring, err := rings.ReadValue()
+
+			if err == bsonrw.ErrEOA {
+				break
+			}
+
+			if err != nil {
+				return err
+			}
+
+			if polygon.Coordinates, err = readBSONPositions(ring); err != nil {
+				return err
+			}
+
+			count++
+		}
+
+		if count != 1 {
+			return derp.Internal(location, "Coordinates length must be 1", count)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return derp.Wrap(err, location, "Unable to read Polygon")
+	}
+
+	value.Set(reflect.ValueOf(polygon))
+	return nil
+}
+
+// readBSONGeometry reads a GeoJSON object, confirming its "type" and using
+// `readCoordinates` to read its "coordinates". BSON null is read as an empty
+// geometry, and unknown properties are skipped. Both "type" and "coordinates"
+// are required.
+func readBSONGeometry(reader bsonrw.ValueReader, geometryType string, readCoordinates func(bsonrw.ValueReader) error) error {
+
+	const location = "geo.readBSONGeometry"
+
+	if reader.Type() == bsontype.Null {
+		return reader.ReadNull()
+	}
+
+	document, err := reader.ReadDocument()
+
+	if err != nil {
+		return derp.Wrap(err, location, "Geometry must be a document")
+	}
+
+	hasType := false
+	hasCoordinates := false
+
+	for {
+		name, element, err := document.ReadElement()
+
+		if err == bsonrw.ErrEOD {
+			break
+		}
+
+		if err != nil {
+			return derp.Wrap(err, location, "Unable to read property")
+		}
+
+		switch name {
+
+		case PropertyType:
+			value, err := element.ReadString()
+
+			if err != nil {
+				return derp.Wrap(err, location, "Unable to read type")
+			}
+
+			if value != geometryType {
+				return derp.Internal(location, "Invalid GeoJSON. Type must be '"+geometryType+"'", value)
+			}
+
+			hasType = true
+
+		case PropertyCoordinates:
+			if err := readCoordinates(element); err != nil {
+				return derp.Wrap(err, location, "Unable to read coordinates")
+			}
+
+			hasCoordinates = true
+
+		default:
+			if err := element.Skip(); err != nil {
+				return derp.Wrap(err, location, "Unable to skip property", name)
+			}
+		}
+	}
+
+	if !hasType {
+		return derp.Internal(location, "Invalid GeoJSON. Type must be '"+geometryType+"'")
+	}
+
+	if !hasCoordinates {
+		return derp.Internal(location, "Invalid GeoJSON. Coordinates are required")
+	}
+
+	return nil
+}
+
+// readBSONPositions reads an array of coordinate arrays
+func readBSONPositions(reader bsonrw.ValueReader) ([]Position, error) {
+
+	const location = "geo.readBSONPositions"
+
+	array, err := reader.ReadArray()
+
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]Position, 0, 8)
+
+	for {
+		element, err := array.ReadValue()
+
+		if err == bsonrw.ErrEOA {
+			return result, nil
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		position, err := readBSONPosition(element)
+
+		if err != nil {
+			return nil, derp.Wrap(err, location, "Invalid coordinate at index", len(result))
+		}
+
+		result = append(result, position)
+	}
+}
+
+// readBSONPosition reads a single coordinate array of length 2 or 3
+func readBSONPosition(reader bsonrw.ValueReader) (Position, error) {
+
+	const location = "geo.readBSONPosition"
+
+	array, err := reader.ReadArray()
+
+	if err != nil {
+		return Position{}, derp.Wrap(err, location, "Coordinates must be an array")
+	}
+
+	var values [3]float64
+	length := 0
+
+	for {
+		element, err := array.ReadValue()
+
+		if err == bsonrw.ErrEOA {
+			break
+		}
+
+		if err != nil {
+			return Position{}, derp.Wrap(err, location, "Unable to read coordinate")
+		}
+
+		if length == len(values) {
+			return Position{}, derp.Internal(location, "Invalid coordinate length. Coordinates must be length 2 or 3")
+		}
+
+		if values[length], err = readBSONNumber(element); err != nil {
+			return Position{}, derp.Wrap(err, location, "Unable to read coordinate")
+		}
+
+		length++
+	}
+
+	if length < 2 {
+		return Position{}, derp.Internal(location, "Invalid coordinate length. Coordinates must be length 2 or 3", length)
+	}
+
+	return NewPositionWithAltitude(values[0], values[1], values[2]), nil
+}
+
+// readBSONNumber reads any BSON numeric value as a float64
+func readBSONNumber(reader bsonrw.ValueReader) (float64, error) {
+
+	const location = "geo.readBSONNumber"
+
+	switch reader.Type() {
+
+	case bsontype.Double:
+		return reader.ReadDouble()
+
+	case bsontype.Int32:
+		value, err := reader.ReadInt32()
+		return float64(value), err
+
+	case bsontype.Int64:
+		value, err := reader.ReadInt64()
+		return float64(value), err
+	}
+
+	return 0, derp.Internal(location, "Coordinate must be a number", reader.Type().String())
+}
@@ -0,0 +1,160 @@
+package geo
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsonrw"
+)
+
+// codecDocument exercises every codec, with and without omitempty
+type codecDocument struct {
+	Position   Position   `bson:"position"`
+	Point      Point      `bson:"point"`
+	LineString LineString `bson:"lineString"`
+	Polygon    Polygon    `bson:"polygon"`
+	Omitted    Point      `bson:"omitted,omitempty"`
+	Pointer    *Polygon   `bson:"pointer,omitempty"`
+	Slice      []Point    `bson:"slice,omitempty"`
+}
+
+// marshalWithCodecs encodes a value using NewBSONRegistry
+func marshalWithCodecs(t *testing.T, value any) []byte {
+
+	buffer := bytes.Buffer{}
+	writer, err := bsonrw.NewBSONValueWriter(&buffer)
+	require.Nil(t, err)
+
+	encoder, err := bson.NewEncoder(writer)
+	require.Nil(t, err)
+
+	encoder.SetRegistry(NewBSONRegistry())
+	require.Nil(t, encoder.Encode(value))
+
+	return buffer.Bytes()
+}
+
+// unmarshalWithCodecs decodes a value using NewBSONRegistry
+func unmarshalWithCodecs(data []byte, value any) error {
+
+	decoder, err := bson.NewDecoder(bsonrw.NewBSONDocumentReader(data))
+
+	if err != nil {
+		return err
+	}
+
+	decoder.SetRegistry(NewBSONRegistry())
+	return decoder.Decode(value)
+}
+
+func TestBSONCodec_MatchesMarshalBSON(t *testing.T) {
+
+	// Codecs write exactly the same bytes as the MarshalBSON methods
+	point := NewPointWithAltitude(-79.9822, 40.4461, 250)
+	expected, err := bson.Marshal(point)
+	require.Nil(t, err)
+	require.Equal(t, expected, marshalWithCodecs(t, point))
+
+	lineString := NewLineString(NewPosition(1, 2), NewPosition(3, 4))
+	expected, err = bson.Marshal(lineString)
+	require.Nil(t, err)
+	require.Equal(t, expected, marshalWithCodecs(t, lineString))
+
+	polygon := NewPolygon(NewPosition(0, 0), NewPosition(1, 0), NewPosition(1, 1), NewPosition(0, 0))
+	expected, err = bson.Marshal(polygon)
+	require.Nil(t, err)
+	require.Equal(t, expected, marshalWithCodecs(t, polygon))
+}
+
+func TestBSONCodec_RoundTrip(t *testing.T) {
+
+	polygon := NewPolygon(NewPosition(0, 0), NewPosition(1, 0), NewPosition(1, 1), NewPosition(0, 0))
+
+	original := codecDocument{
+		Position:   NewPositionWithAltitude(1, 2, 3),
+		Point:      NewPoint(-79.9822, 40.4461),
+		LineString: NewLineString(NewPosition(1, 2), NewPosition(3, 4), NewPosition(5, 6)),
+		Polygon:    polygon,
+		Pointer:    &polygon,
+		Slice:      []Point{NewPoint(1, 1), NewPoint(2, 2)},
+	}
+
+	data := marshalWithCodecs(t, original)
+
+	result := codecDocument{}
+	require.Nil(t, unmarshalWithCodecs(data, &result))
+	require.Equal(t, original, result)
+}
+
+func TestBSONCodec_Zero(t *testing.T) {
+
+	data := marshalWithCodecs(t, codecDocument{})
+
+	// Zero geometries are written as null, or omitted with omitempty
+	raw := bson.Raw(data)
+	require.Equal(t, bson.TypeArray, raw.Lookup("position").Type)
+	require.Equal(t, bson.TypeNull, raw.Lookup("point").Type)
+	require.Equal(t, bson.TypeNull, raw.Lookup("lineString").Type)
+	require.Equal(t, bson.TypeNull, raw.Lookup("polygon").Type)
+
+	_, err := raw.LookupErr("omitted")
+	require.NotNil(t, err)
+
+	// Null values decode into zero values
+	result := codecDocument{
+		Point:   NewPoint(1, 2),
+		Polygon: NewPolygon(NewPosition(1, 2)),
+	}
+
+	require.Nil(t, unmarshalWithCodecs(data, &result))
+	require.True(t, result.Point.IsZero())
+	require.True(t, result.LineString.IsZero())
+	require.True(t, result.Polygon.IsZero())
+}
+
+func TestBSONCodec_IntegerCoordinates(t *testing.T) {
+
+	data, err := bson.Marshal(bson.D{
+		{Key: "point", Value: bson.D{
+			{Key: "coordinates", Value: bson.A{int32(10), int64(20)}},
+			{Key: "bbox", Value: bson.A{1, 2, 3, 4}},
+			{Key: "type", Value: "Point"},
+		}},
+	})
+	require.Nil(t, err)
+
+	result := codecDocument{}
+	require.Nil(t, unmarshalWithCodecs(data, &result))
+	require.Equal(t, NewPoint(10, 20), result.Point)
+}
+
+func TestBSONCodec_Errors(t *testing.T) {
+
+	invalid := []bson.D{
+		{{Key: "point", Value: bson.D{{Key: "type", Value: "Polygon"}, {Key: "coordinates", Value: bson.A{1.0, 2.0}}}}},
+		{{Key: "point", Value: bson.D{{Key: "coordinates", Value: bson.A{1.0, 2.0}}}}},
+		{{Key: "point", Value: bson.D{{Key: "type", Value: "Point"}, {Key: "coordinates", Value: bson.A{1.0}}}}},
+		{{Key: "point", Value: bson.D{{Key: "type", Value: "Point"}, {Key: "coordinates", Value: bson.A{1.0, 2.0, 3.0, 4.0}}}}},
+		{{Key: "point", Value: bson.D{{Key: "type", Value: "Point"}, {Key: "coordinates", Value: bson.A{"1", "2"}}}}},
+		{{Key: "point", Value: "geo:1,2"}},
+		{{Key: "lineString", Value: bson.D{{Key: "type", Value: "LineString"}, {Key: "coordinates", Value: bson.A{bson.A{1.0}}}}}},
+		{{Key: "polygon", Value: bson.D{{Key: "type", Value: "Polygon"}, {Key: "coordinates", Value: bson.A{}}}}},
+		{{Key: "polygon", Value: bson.D{{Key: "type", Value: "Polygon"}, {Key: "coordinates", Value: bson.A{bson.A{}, bson.A{}}}}}},
+		{{Key: "position", Value: bson.A{true, false}}},
+		{{Key: "lineString", Value: bson.D{{Key: "type", Value: "LineString"}, {Key: "coordinates", Value: bson.A{bson.A{true, false}}}}}},
+		{{Key: "polygon", Value: bson.D{{Key: "type", Value: "Polygon"}, {Key: "coordinates", Value: bson.A{bson.A{bson.A{"1", "2"}}}}}}},
+		{{Key: "point", Value: bson.D{{Key: "type", Value: "Point"}}}},
+		{{Key: "lineString", Value: bson.D{{Key: "type", Value: "LineString"}}}},
+		{{Key: "polygon", Value: bson.D{{Key: "type", Value: "Polygon"}, {Key: "bbox", Value: bson.A{1, 2, 3, 4}}}}},
+	}
+
+	for _, document := range invalid {
+		data, err := bson.Marshal(document)
+		require.Nil(t, err)
+
+		result := codecDocument{}
+		require.NotNil(t, unmarshalWithCodecs(data, &result), document)
+	}
+}
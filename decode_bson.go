@@ -0,0 +1,205 @@
+package geo
+
+import (
+	"encoding/binary"
+
+	"github.com/benpate/rosetta/sliceof"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.mongodb.org/mongo-driver/x/bsonx/bsoncore"
+)
+
+// The decoders in this file are "fast paths" for the BSON unmarshallers of Position,
+// Point, and Polygon. They read the documents written by MarshalBSON directly into
+// Positions, without any intermediate values. Like the JSON fast paths, they report
+// `false` for anything unusual (including extra properties and every error) and the
+// caller then falls back to the general decoder.
+
+// decodePositionBSON reads a BSON array of two or three numbers
+func decodePositionBSON(dataType bsontype.Type, data []byte) (Position, bool) {
+
+	if dataType != bsontype.Array {
+		return Position{}, false
+	}
+
+	return readBSONCorePosition(data)
+}
+
+// decodePointBSON reads a GeoJSON Point document that contains only "type" and "coordinates"
+func decodePointBSON(data []byte) (Position, bool) {
+
+	var position Position
+	var hasType, hasCoordinates bool
+
+	ok := readBSONCoreDocument(data, func(key []byte, value bsoncore.Value) bool {
+
+		switch string(key) {
+
+		case PropertyType:
+			if hasType {
+				return false
+			}
+
+			hasType = true
+			return string(readBSONCoreString(value)) == PropertyTypePoint
+
+		case PropertyCoordinates:
+			var ok bool
+
+			if hasCoordinates || (value.Type != bsontype.Array) {
+				return false
+			}
+
+			hasCoordinates = true
+			position, ok = readBSONCorePosition(value.Data)
+			return ok
+		}
+
+		return false
+	})
+
+	return position, ok && hasType && hasCoordinates
+}
+
+// decodePolygonBSON reads a GeoJSON Polygon document that contains only "type" and
+// "coordinates", with exactly one ring
+func decodePolygonBSON(data []byte) (sliceof.Object[Position], bool) {
+
+	var coordinates sliceof.Object[Position]
+	var hasType, hasCoordinates bool
+
+	ok := readBSONCoreDocument(data, func(key []byte, value bsoncore.Value) bool {
+
+		switch string(key) {
+
+		case PropertyType:
+			if hasType || (value.Type != bsontype.String) {
+				return false
+			}
+
+			hasType = true
+			return readBSONCoreString(value) != nil
+
+		case PropertyCoordinates:
+			if hasCoordinates || (value.Type != bsontype.Array) {
+				return false
+			}
+
+			hasCoordinates = true
+			rings := 0
+
+			ok := readBSONCoreDocument(value.Data, func(_ []byte, ring bsoncore.Value) bool {
+
+				if (ring.Type != bsontype.Array) || (rings > 0) {
+					return false
+				}
+
+				rings++
+				coordinates = make(sliceof.Object[Position], 0, 8)
+
+				return readBSONCoreDocument(ring.Data, func(_ []byte, element bsoncore.Value) bool {
+
+					if element.Type != bsontype.Array {
+						return false
+					}
+
+					position, ok := readBSONCorePosition(element.Data)
+					coordinates = append(coordinates, position)
+					return ok
+				})
+			})
+
+			return ok && (rings == 1)
+		}
+
+		return false
+	})
+
+	return coordinates, ok && hasType && hasCoordinates
+}
+
+// readBSONCoreDocument calls `readValue` for each element of a BSON document (or array).
+// It returns FALSE if the document is malformed, or if `readValue` returns FALSE.
+func readBSONCoreDocument(data []byte, readValue func(key []byte, value bsoncore.Value) bool) bool {
+
+	// The declared length must match the data exactly, and include the trailing null byte
+	if (len(data) < 5) || (int(int32(binary.LittleEndian.Uint32(data))) != len(data)) || (data[len(data)-1] != 0) {
+		return false
+	}
+
+	elements := data[4 : len(data)-1]
+
+	for len(elements) > 0 {
+
+		element, remaining, ok := bsoncore.ReadElement(elements)
+
+		if !ok {
+			return false
+		}
+
+		value, err := element.ValueErr()
+
+		if err != nil {
+			return false
+		}
+
+		if !readValue(element.KeyBytes(), value) {
+			return false
+		}
+
+		elements = remaining
+	}
+
+	return true
+}
+
+// readBSONCorePosition reads a BSON array of two or three numbers
+func readBSONCorePosition(data []byte) (Position, bool) {
+
+	var values [3]float64
+	length := 0
+
+	ok := readBSONCoreDocument(data, func(_ []byte, value bsoncore.Value) bool {
+
+		if length == len(values) {
+			return false
+		}
+
+		switch value.Type {
+
+		case bsontype.Double:
+			values[length], _ = value.DoubleOK()
+
+		case bsontype.Int32:
+			number, _ := value.Int32OK()
+			values[length] = float64(number)
+
+		default:
+			return false
+		}
+
+		length++
+		return true
+	})
+
+	if !ok || (length < 2) {
+		return Position{}, false
+	}
+
+	return NewPositionWithAltitude(values[0], values[1], values[2]), true
+}
+
+// readBSONCoreString returns the bytes of a well-formed BSON string value, or nil
+func readBSONCoreString(value bsoncore.Value) []byte {
+
+	if (value.Type != bsontype.String) || (len(value.Data) < 5) {
+		return nil
+	}
+
+	length := int(int32(binary.LittleEndian.Uint32(value.Data)))
+
+	if (length < 1) || (length != len(value.Data)-4) || (value.Data[len(value.Data)-1] != 0) {
+		return nil
+	}
+
+	return value.Data[4 : len(value.Data)-1]
+}
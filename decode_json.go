@@ -0,0 +1,454 @@
+package geo
+
+import (
+	"bytes"
+	"strconv"
+	"unsafe"
+
+	"github.com/benpate/rosetta/sliceof"
+)
+
+// The decoders in this file are "fast paths" for the UnmarshalJSON methods of
+// Position, Point, and Polygon. They parse well-formed GeoJSON directly into
+// Positions, without any intermediate values. They report `false` for anything
+// unusual (including every error) and the caller then falls back to the general
+// decoder, so the results and error messages of both paths are always identical.
+
+// maxJSONDepth limits how deeply nested a skipped JSON value may be before the
+// fast path gives up and defers to the general decoder
+const maxJSONDepth = 64
+
+// decodePositionJSON parses a GeoJSON coordinate array, such as [1,2] or [1,2,3]
+func decodePositionJSON(data []byte) (Position, bool) {
+
+	scanner := jsonScanner{data: data}
+	position, ok := scanner.readPosition()
+
+	return position, ok && scanner.atEnd()
+}
+
+// decodePointJSON parses a GeoJSON Point object. Like the general decoder (which
+// reads into a map) property names are case-sensitive and unknown properties are ignored.
+func decodePointJSON(data []byte) (Position, bool) {
+
+	scanner := jsonScanner{data: data}
+
+	var position Position
+	var hasType, hasCoordinates bool
+
+	ok := scanner.readObject(func(key []byte) bool {
+
+		switch string(key) {
+
+		case PropertyType:
+			value, ok := scanner.readString()
+
+			if hasType || !ok || (string(value) != PropertyTypePoint) {
+				return false
+			}
+
+			hasType = true
+			return true
+
+		case PropertyCoordinates:
+			var ok bool
+
+			if hasCoordinates {
+				return false
+			}
+
+			position, ok = scanner.readPosition()
+			hasCoordinates = true
+			return ok
+		}
+
+		return scanner.skipValue(0)
+	})
+
+	return position, ok && hasType && hasCoordinates && scanner.atEnd()
+}
+
+// decodePolygonJSON parses a GeoJSON Polygon object with exactly one ring. The general
+// decoder (encoding/json into GeoJSONPolygon) matches property names case-insensitively,
+// so any property that might match that way is left for it to handle.
+func decodePolygonJSON(data []byte) (sliceof.Object[Position], bool) {
+
+	scanner := jsonScanner{data: data}
+
+	var coordinates sliceof.Object[Position]
+	var hasType, hasCoordinates bool
+
+	ok := scanner.readObject(func(key []byte) bool {
+
+		switch string(key) {
+
+		case PropertyType:
+			if hasType {
+				return false
+			}
+
+			hasType = true
+			_, ok := scanner.readString()
+			return ok
+
+		case PropertyCoordinates:
+			var ok bool
+
+			if hasCoordinates {
+				return false
+			}
+
+			hasCoordinates = true
+			coordinates, ok = scanner.readRings()
+			return ok
+		}
+
+		if jsonKeyMayFold(key, PropertyType) || jsonKeyMayFold(key, PropertyCoordinates) {
+			return false
+		}
+
+		return scanner.skipValue(0)
+	})
+
+	return coordinates, ok && hasCoordinates && scanner.atEnd()
+}
+
+// jsonKeyMayFold returns TRUE if encoding/json might match `key` to the struct field `name`.
+// This is true for case-insensitive ASCII matches, and for any key with non-ASCII
+// characters (which might be Unicode case-folded into an ASCII character)
+func jsonKeyMayFold(key []byte, name string) bool {
+
+	if len(key) != len(name) {
+
+		for _, character := range key {
+			if character >= 0x80 {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	for index, character := range key {
+
+		if character >= 0x80 {
+			return true
+		}
+
+		if (character | 0x20) != (name[index] | 0x20) {
+			return false
+		}
+	}
+
+	return true
+}
+
+/******************************************
+ * JSON Scanner
+ ******************************************/
+
+// jsonScanner reads JSON tokens from a byte slice, without allocating
+type jsonScanner struct {
+	data   []byte
+	offset int
+}
+
+// skipWhitespace advances past any JSON whitespace
+func (scanner *jsonScanner) skipWhitespace() {
+	for scanner.offset < len(scanner.data) {
+		switch scanner.data[scanner.offset] {
+		case ' ', '\t', '\n', '\r':
+			scanner.offset++
+		default:
+			return
+		}
+	}
+}
+
+// peek returns the next non-whitespace character (without consuming it), or 0 at the end of the data
+func (scanner *jsonScanner) peek() byte {
+
+	scanner.skipWhitespace()
+
+	if scanner.offset < len(scanner.data) {
+		return scanner.data[scanner.offset]
+	}
+
+	return 0
+}
+
+// consume advances past the next non-whitespace character, if it is `expected`
+func (scanner *jsonScanner) consume(expected byte) bool {
+
+	if scanner.peek() == expected {
+		scanner.offset++
+		return true
+	}
+
+	return false
+}
+
+// atEnd returns TRUE if only whitespace remains
+func (scanner *jsonScanner) atEnd() bool {
+	scanner.skipWhitespace()
+	return scanner.offset == len(scanner.data)
+}
+
+// readObject reads a JSON object, calling `readValue` after each property name.
+// `readValue` must read (or skip) the property's value and return TRUE on success.
+func (scanner *jsonScanner) readObject(readValue func(key []byte) bool) bool {
+
+	if !scanner.consume('{') {
+		return false
+	}
+
+	if scanner.consume('}') {
+		return true
+	}
+
+	for {
+		key, ok := scanner.readString()
+
+		if !ok || !scanner.consume(':') || !readValue(key) {
+			return false
+		}
+
+		if scanner.consume(',') {
+			continue
+		}
+
+		return scanner.consume('}')
+	}
+}
+
+// readString reads a JSON string that contains no escape sequences, returning its contents
+func (scanner *jsonScanner) readString() ([]byte, bool) {
+
+	if !scanner.consume('"') {
+		return nil, false
+	}
+
+	start := scanner.offset
+
+	for scanner.offset < len(scanner.data) {
+
+		character := scanner.data[scanner.offset]
+
+		switch {
+
+		case character == '"':
+			scanner.offset++
+			return scanner.data[start : scanner.offset-1], true
+
+		// Escapes are left for the general decoder, and control characters are invalid
+		case (character == '\\') || (character < 0x20):
+			return nil, false
+		}
+
+		scanner.offset++
+	}
+
+	return nil, false
+}
+
+// readNumber reads a JSON number
+func (scanner *jsonScanner) readNumber() (float64, bool) {
+
+	scanner.skipWhitespace()
+	start := scanner.offset
+
+	// Optional minus sign
+	if scanner.offset < len(scanner.data) && scanner.data[scanner.offset] == '-' {
+		scanner.offset++
+	}
+
+	// Integer part: a single zero, or digits that do not start with zero
+	if scanner.offset < len(scanner.data) && scanner.data[scanner.offset] == '0' {
+		scanner.offset++
+	} else if !scanner.readDigits() {
+		return 0, false
+	}
+
+	// Optional fraction
+	if scanner.offset < len(scanner.data) && scanner.data[scanner.offset] == '.' {
+		scanner.offset++
+
+		if !scanner.readDigits() {
+			return 0, false
+		}
+	}
+
+	// Optional exponent
+	if scanner.offset < len(scanner.data) && (scanner.data[scanner.offset]|0x20) == 'e' {
+		scanner.offset++
+
+		if scanner.offset < len(scanner.data) && (scanner.data[scanner.offset] == '+' || scanner.data[scanner.offset] == '-') {
+			scanner.offset++
+		}
+
+		if !scanner.readDigits() {
+			return 0, false
+		}
+	}
+
+	// The number's bytes are only borrowed as a string for the duration of ParseFloat,
+	// which copies them if it needs to keep them for an error.
+	number := scanner.data[start:scanner.offset]
+	value, err := strconv.ParseFloat(unsafe.String(unsafe.SliceData(number), len(number)), 64)
+
+	return value, err == nil
+}
+
+// readDigits advances past one or more decimal digits
+func (scanner *jsonScanner) readDigits() bool {
+
+	start := scanner.offset
+
+	for scanner.offset < len(scanner.data) && scanner.data[scanner.offset] >= '0' && scanner.data[scanner.offset] <= '9' {
+		scanner.offset++
+	}
+
+	return scanner.offset > start
+}
+
+// readPosition reads a coordinate array of two or three numbers
+func (scanner *jsonScanner) readPosition() (Position, bool) {
+
+	var values [3]float64
+
+	if !scanner.consume('[') {
+		return Position{}, false
+	}
+
+	for index := range values {
+
+		value, ok := scanner.readNumber()
+
+		if !ok {
+			return Position{}, false
+		}
+
+		values[index] = value
+
+		if scanner.consume(']') {
+
+			if index == 0 {
+				return Position{}, false
+			}
+
+			return NewPositionWithAltitude(values[0], values[1], values[2]), true
+		}
+
+		if !scanner.consume(',') {
+			return Position{}, false
+		}
+	}
+
+	return Position{}, false
+}
+
+// readRings reads the coordinates of a Polygon, which must contain exactly one ring
+func (scanner *jsonScanner) readRings() (sliceof.Object[Position], bool) {
+
+	if !scanner.consume('[') || !scanner.consume('[') {
+		return nil, false
+	}
+
+	// Every position begins with a bracket, so this is enough room for all of them
+	result := make(sliceof.Object[Position], 0, bytes.Count(scanner.data[scanner.offset:], []byte{'['}))
+
+	if !scanner.consume(']') {
+
+		for {
+			position, ok := scanner.readPosition()
+
+			if !ok {
+				return nil, false
+			}
+
+			result = append(result, position)
+
+			if scanner.consume(',') {
+				continue
+			}
+
+			if !scanner.consume(']') {
+				return nil, false
+			}
+
+			break
+		}
+	}
+
+	if !scanner.consume(']') {
+		return nil, false
+	}
+
+	return result, true
+}
+
+// skipValue advances past any valid JSON value
+func (scanner *jsonScanner) skipValue(depth int) bool {
+
+	if depth > maxJSONDepth {
+		return false
+	}
+
+	switch scanner.peek() {
+
+	case '"':
+		_, ok := scanner.readString()
+		return ok
+
+	case '{':
+		return scanner.readObject(func([]byte) bool {
+			return scanner.skipValue(depth + 1)
+		})
+
+	case '[':
+		scanner.offset++
+
+		if scanner.consume(']') {
+			return true
+		}
+
+		for {
+			if !scanner.skipValue(depth + 1) {
+				return false
+			}
+
+			if scanner.consume(',') {
+				continue
+			}
+
+			return scanner.consume(']')
+		}
+
+	case 't':
+		return scanner.skipLiteral("true")
+
+	case 'f':
+		return scanner.skipLiteral("false")
+
+	case 'n':
+		return scanner.skipLiteral("null")
+	}
+
+	_, ok := scanner.readNumber()
+	return ok
+}
+
+// skipLiteral advances past a fixed keyword, such as "true"
+func (scanner *jsonScanner) skipLiteral(literal string) bool {
+
+	if len(scanner.data)-scanner.offset < len(literal) {
+		return false
+	}
+
+	if string(scanner.data[scanner.offset:scanner.offset+len(literal)]) != literal {
+		return false
+	}
+
+	scanner.offset += len(literal)
+	return true
+}
@@ -0,0 +1,220 @@
+package geo
+
+import (
+	"math"
+	"testing"
+
+	"github.com/benpate/rosetta/sliceof"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+)
+
+// decodeJSONCorpus includes well-formed GeoJSON (handled by the fast path)
+// along with the quirks that the general decoder must continue to handle.
+var decodeJSONCorpus = []string{
+	`[1,2]`,
+	`[1,2,3]`,
+	` [ -1.5e2 , 2.25E-3 , 0 ] `,
+	`[-0,0.0]`,
+	`[1]`,
+	`[1,2,3,4]`,
+	`[1,null]`,
+	`["1",2]`,
+	`[01,2]`,
+	`[1.,2]`,
+	`[1,2]x`,
+	`{"type":"Point","coordinates":[1,2]}`,
+	`{"coordinates":[1,2,3],"type":"Point"}`,
+	`{"type":"Point","coordinates":[1,2],"bbox":[1,2,1,2],"properties":{"a":[{"b":null}],"c":true}}`,
+	`{"type":"Point","coordinates":"1,2"}`,
+	`{"type":"Point","coordinates":["1","2"]}`,
+	`{"type":["Point"],"coordinates":[1,2]}`,
+	`{"type":"Polygon","coordinates":[1,2]}`,
+	`{"type":"Point","type":"Point","coordinates":[1,2]}`,
+	`{"Type":"Point","coordinates":[1,2]}`,
+	`{"type":"Point ","coordinates":[1,2]}`,
+	`{"type":"Point","coordinates":[1,2],}`,
+	`{"type":"Polygon","coordinates":[[[1,2],[3,4],[5,6],[1,2]]]}`,
+	`{"coordinates":[[[1,2,3],[3,4,5]]],"type":"Anything"}`,
+	`{"coordinates":[[[1,2],[3,4]]]}`,
+	`{"type":"Polygon","coordinates":[[]]}`,
+	`{"type":"Polygon","coordinates":[]}`,
+	`{"type":"Polygon","coordinates":[[[1,2]],[[3,4]]]}`,
+	`{"type":"Polygon","COORDINATES":[[[1,2]]]}`,
+	`{"type":"Polygon","coordinates":[[[1,2]]],"coordinates":[[[3,4]]]}`,
+	`null`,
+	``,
+	`{`,
+	`{}`,
+}
+
+// TestDecodeJSON_FastPath confirms that whenever a fast-path JSON decoder accepts
+// an input, it produces exactly the same result as the general decoder.
+func TestDecodeJSON_FastPath(t *testing.T) {
+	for _, data := range decodeJSONCorpus {
+		requireSameJSONDecoding(t, []byte(data))
+	}
+}
+
+// TestDecodeJSON_FastPath_Coverage confirms that ordinary GeoJSON actually uses the fast path
+func TestDecodeJSON_FastPath_Coverage(t *testing.T) {
+
+	_, ok := decodePositionJSON([]byte(`[1,2,3]`))
+	require.True(t, ok)
+
+	_, ok = decodePointJSON([]byte(`{"type":"Point","coordinates":[1,2]}`))
+	require.True(t, ok)
+
+	_, ok = decodePolygonJSON([]byte(`{"type":"Polygon","coordinates":[[[1,2],[3,4],[1,2]]]}`))
+	require.True(t, ok)
+}
+
+// TestDecodeBSON_FastPath confirms that whenever a fast-path BSON decoder accepts
+// an input, it produces exactly the same result as the general decoder.
+func TestDecodeBSON_FastPath(t *testing.T) {
+
+	documents := []bson.D{
+		{{Key: "type", Value: "Point"}, {Key: "coordinates", Value: bson.A{1.0, 2.0}}},
+		{{Key: "coordinates", Value: bson.A{int32(1), 2.5, int64(3)}}, {Key: "type", Value: "Point"}},
+		{{Key: "type", Value: "Point"}, {Key: "coordinates", Value: bson.A{1.0}}},
+		{{Key: "type", Value: "Point"}, {Key: "coordinates", Value: bson.A{"1", 2.0}}},
+		{{Key: "type", Value: "Point"}, {Key: "coordinates", Value: bson.A{true, false}}},
+		{{Key: "type", Value: "Polygon"}, {Key: "coordinates", Value: bson.A{1.0, 2.0}}},
+		{{Key: "type", Value: "Point"}, {Key: "coordinates", Value: bson.A{1.0, 2.0}}, {Key: "extra", Value: true}},
+		{{Key: "type", Value: "Polygon"}, {Key: "coordinates", Value: bson.A{bson.A{bson.A{1.0, 2.0}, bson.A{3.0, 4.0}, bson.A{1.0, 2.0}}}}},
+		{{Key: "type", Value: "Polygon"}, {Key: "coordinates", Value: bson.A{bson.A{bson.A{int32(1), 2.0, 3.0}}}}},
+		{{Key: "type", Value: "Polygon"}, {Key: "coordinates", Value: bson.A{bson.A{}}}},
+		{{Key: "type", Value: "Polygon"}, {Key: "coordinates", Value: bson.A{}}},
+		{{Key: "type", Value: "Polygon"}, {Key: "coordinates", Value: bson.A{bson.A{bson.A{1.0, 2.0}}, bson.A{bson.A{3.0, 4.0}}}}},
+		{{Key: "coordinates", Value: bson.A{bson.A{bson.A{1.0, 2.0}}}}},
+		{},
+	}
+
+	for _, document := range documents {
+		data, err := bson.Marshal(document)
+		require.Nil(t, err)
+		requireSameBSONDecoding(t, data)
+	}
+
+	for _, value := range []any{bson.A{1.0, 2.0}, bson.A{int32(1), 2.0, 3.0}, bson.A{1.0}, bson.A{"1", 2.0}, "1,2"} {
+		dataType, data, err := bson.MarshalValue(value)
+		require.Nil(t, err)
+		requireSamePositionBSONDecoding(t, dataType, data)
+	}
+}
+
+// TestDecodeBSON_SameAsJSON confirms that the BSON decoders accept the same loosely-typed
+// coordinates as the JSON decoders, with the same results
+func TestDecodeBSON_SameAsJSON(t *testing.T) {
+
+	tests := []struct {
+		json     string
+		bson     bson.A
+		expected Position
+	}{
+		{`[true,false]`, bson.A{true, false}, NewPosition(1, 0)},
+		{`["1","2"]`, bson.A{"1", "2"}, NewPosition(1, 2)},
+	}
+
+	for _, test := range tests {
+
+		fromJSON := Point{}
+		require.Nil(t, fromJSON.UnmarshalJSON([]byte(`{"type":"Point","coordinates":`+test.json+`}`)), test.json)
+		require.Equal(t, test.expected, fromJSON.Position, test.json)
+
+		data, err := bson.Marshal(bson.D{{Key: "type", Value: "Point"}, {Key: "coordinates", Value: test.bson}})
+		require.Nil(t, err)
+
+		fromBSON := Point{}
+		require.Nil(t, fromBSON.UnmarshalBSON(data), test.json)
+		require.Equal(t, test.expected, fromBSON.Position, test.json)
+	}
+}
+
+// TestDecodeBSON_FastPath_Coverage confirms that ordinary GeoJSON actually uses the fast path
+func TestDecodeBSON_FastPath_Coverage(t *testing.T) {
+
+	data, err := bson.Marshal(NewPoint(1, 2))
+	require.Nil(t, err)
+
+	_, ok := decodePointBSON(data)
+	require.True(t, ok)
+
+	data, err = bson.Marshal(NewPolygon(NewPosition(1, 2), NewPosition(3, 4), NewPosition(1, 2)))
+	require.Nil(t, err)
+
+	_, ok = decodePolygonBSON(data)
+	require.True(t, ok)
+
+	dataType, data, err := NewPosition(1, 2).MarshalBSONValue()
+	require.Nil(t, err)
+
+	_, ok = decodePositionBSON(dataType, data)
+	require.True(t, ok)
+}
+
+// requireSameJSONDecoding compares each fast-path JSON decoder with its general decoder
+func requireSameJSONDecoding(t *testing.T, data []byte) {
+
+	if fast, ok := decodePositionJSON(data); ok {
+		general := Position{}
+		require.Nil(t, general.unmarshalJSONGeneral(data), string(data))
+		requireSamePosition(t, general, fast, string(data))
+	}
+
+	if fast, ok := decodePointJSON(data); ok {
+		general := Point{}
+		require.Nil(t, general.unmarshalJSONGeneral(data), string(data))
+		requireSamePosition(t, general.Position, fast, string(data))
+	}
+
+	if fast, ok := decodePolygonJSON(data); ok {
+		general := Polygon{}
+		require.Nil(t, general.unmarshalJSONGeneral(data), string(data))
+		requireSamePositions(t, general.Coordinates, fast, string(data))
+	}
+}
+
+// requireSameBSONDecoding compares each fast-path BSON document decoder with its general decoder
+func requireSameBSONDecoding(t *testing.T, data []byte) {
+
+	if fast, ok := decodePointBSON(data); ok {
+		general := Point{}
+		require.Nil(t, general.unmarshalBSONGeneral(data))
+		requireSamePosition(t, general.Position, fast, data)
+	}
+
+	if fast, ok := decodePolygonBSON(data); ok {
+		general := Polygon{}
+		require.Nil(t, general.unmarshalBSONGeneral(data))
+		requireSamePositions(t, general.Coordinates, fast, data)
+	}
+}
+
+// requireSamePositionBSONDecoding compares the fast-path Position BSON decoder with its general decoder
+func requireSamePositionBSONDecoding(t *testing.T, dataType bsontype.Type, data []byte) {
+
+	if fast, ok := decodePositionBSON(dataType, data); ok {
+		general := Position{}
+		require.Nil(t, general.unmarshalBSONValueGeneral(dataType, data))
+		requireSamePosition(t, general, fast, data)
+	}
+}
+
+// requireSamePositions requires two coordinate slices to be bit-for-bit identical
+func requireSamePositions(t *testing.T, expected sliceof.Object[Position], actual sliceof.Object[Position], message any) {
+
+	require.Equal(t, len(expected), len(actual), message)
+
+	for index := range expected {
+		requireSamePosition(t, expected[index], actual[index], message)
+	}
+}
+
+// requireSamePosition requires two Positions to be bit-for-bit identical (so that NaN equals NaN, and -0 does not equal 0)
+func requireSamePosition(t *testing.T, expected Position, actual Position, message any) {
+	require.Equal(t, math.Float64bits(expected.Longitude), math.Float64bits(actual.Longitude), message)
+	require.Equal(t, math.Float64bits(expected.Latitude), math.Float64bits(actual.Latitude), message)
+	require.Equal(t, math.Float64bits(expected.Altitude), math.Float64bits(actual.Altitude), message)
+}
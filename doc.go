@@ -1,7 +1,7 @@
-// Package geo provides geographic value types — Position, Point, Polygon, and
-// Address — along with JSON and BSON (GeoJSON) marshalling for each.
+// Package geo provides geographic value types — Position, Point, LineString,
+// Polygon, and Address — along with JSON and BSON (GeoJSON) marshalling for each.
 //
-// Position, Point, and Polygon follow the GeoJSON specification
+// Position, Point, LineString, and Polygon follow the GeoJSON specification
 // (https://datatracker.ietf.org/doc/html/rfc7946), while Address models a
 // human-readable postal address with optional geocoded coordinates.
 package geo
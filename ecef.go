@@ -0,0 +1,94 @@
+package geo
+
+import "math"
+
+// ECEF represents an Earth-Centered, Earth-Fixed cartesian coordinate on the
+// WGS84 ellipsoid. The origin is the center of the Earth, X points through the
+// equator at the prime meridian, Y through the equator at 90°E, and Z through
+// the North Pole.
+// https://en.wikipedia.org/wiki/Earth-centered,_Earth-fixed_coordinate_system
+type ECEF struct {
+	X float64 // Meters toward the equator at the prime meridian
+	Y float64 // Meters toward the equator at 90°E
+	Z float64 // Meters toward the North Pole
+}
+
+// ENU represents a local East-North-Up coordinate, measured in meters from a
+// reference Position. East and North are tangent to the ellipsoid at the
+// reference, and Up is perpendicular to it.
+// https://en.wikipedia.org/wiki/Local_tangent_plane_coordinates
+type ENU struct {
+	East  float64 // Meters east of the reference Position
+	North float64 // Meters north of the reference Position
+	Up    float64 // Meters above the reference Position
+}
+
+// ECEF converts this Position (including its Altitude above the ellipsoid) into
+// Earth-Centered, Earth-Fixed coordinates.
+func (position Position) ECEF() ECEF {
+	x, y, z := EllipsoidWGS84.toCartesian(position)
+	return ECEF{X: x, Y: y, Z: z}
+}
+
+// Position converts these ECEF coordinates back into a Position, with the
+// Altitude measured above the WGS84 ellipsoid.
+func (ecef ECEF) Position() Position {
+	return EllipsoidWGS84.fromCartesian(ecef.X, ecef.Y, ecef.Z)
+}
+
+// Distance returns the straight-line distance (in meters) between two ECEF coordinates
+func (ecef ECEF) Distance(other ECEF) float64 {
+	dx := other.X - ecef.X
+	dy := other.Y - ecef.Y
+	dz := other.Z - ecef.Z
+	return math.Sqrt(dx*dx + dy*dy + dz*dz)
+}
+
+// ENU converts this Position into local East-North-Up coordinates, relative to
+// the `reference` Position.
+func (position Position) ENU(reference Position) ENU {
+
+	target := position.ECEF()
+	origin := reference.ECEF()
+
+	dx := target.X - origin.X
+	dy := target.Y - origin.Y
+	dz := target.Z - origin.Z
+
+	sinLatitude, cosLatitude := math.Sincos(degreesToRadians(reference.Latitude))
+	sinLongitude, cosLongitude := math.Sincos(degreesToRadians(reference.Longitude))
+
+	return ENU{
+		East:  -sinLongitude*dx + cosLongitude*dy,
+		North: -sinLatitude*cosLongitude*dx - sinLatitude*sinLongitude*dy + cosLatitude*dz,
+		Up:    cosLatitude*cosLongitude*dx + cosLatitude*sinLongitude*dy + sinLatitude*dz,
+	}
+}
+
+// Position converts these East-North-Up coordinates back into a Position,
+// using the same `reference` Position that they were measured from.
+func (enu ENU) Position(reference Position) Position {
+
+	origin := reference.ECEF()
+
+	sinLatitude, cosLatitude := math.Sincos(degreesToRadians(reference.Latitude))
+	sinLongitude, cosLongitude := math.Sincos(degreesToRadians(reference.Longitude))
+
+	return ECEF{
+		X: origin.X - sinLongitude*enu.East - sinLatitude*cosLongitude*enu.North + cosLatitude*cosLongitude*enu.Up,
+		Y: origin.Y + cosLongitude*enu.East - sinLatitude*sinLongitude*enu.North + cosLatitude*sinLongitude*enu.Up,
+		Z: origin.Z + cosLatitude*enu.North + sinLatitude*enu.Up,
+	}.Position()
+}
+
+// Distance returns the straight-line distance (in meters) from the reference Position
+func (enu ENU) Distance() float64 {
+	return math.Sqrt(enu.East*enu.East + enu.North*enu.North + enu.Up*enu.Up)
+}
+
+// SlantDistance returns the straight-line (3D) distance in meters between two
+// Positions, including the difference in their Altitudes. This is the line-of-sight
+// distance through space, not the distance along the surface of the Earth.
+func (position Position) SlantDistance(other Position) float64 {
+	return position.ECEF().Distance(other.ECEF())
+}
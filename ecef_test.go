@@ -0,0 +1,95 @@
+package geo
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPosition_ECEF(t *testing.T) {
+
+	// Equator, prime meridian
+	result := NewPosition(0, 0).ECEF()
+	require.InDelta(t, 6378137, result.X, 0.000001)
+	require.InDelta(t, 0, result.Y, 0.000001)
+	require.InDelta(t, 0, result.Z, 0.000001)
+
+	// North Pole, 100m up
+	result = NewPositionWithAltitude(0, 90, 100).ECEF()
+	require.InDelta(t, 0, result.X, 0.000001)
+	require.InDelta(t, 6356852.314245, result.Z, 0.000001)
+
+	// 45°N on the 90°E meridian, using the prime vertical radius of curvature
+	radius := 6378137 / math.Sqrt(1-EllipsoidWGS84.EccentricitySquared()/2)
+	result = NewPosition(90, 45).ECEF()
+	require.InDelta(t, 0, result.X, 0.000001)
+	require.InDelta(t, radius*math.Sqrt2/2, result.Y, 0.000001)
+	require.InDelta(t, radius*(1-EllipsoidWGS84.EccentricitySquared())*math.Sqrt2/2, result.Z, 0.000001)
+}
+
+func TestECEF_Position(t *testing.T) {
+
+	for _, position := range []Position{
+		NewPositionWithAltitude(-122.4194, 37.7749, 16),
+		NewPositionWithAltitude(151.2153, -33.8568, -25),
+		NewPositionWithAltitude(179.999, 89.999, 35000),
+		NewPositionWithAltitude(0, 0, 0),
+	} {
+		result := position.ECEF().Position()
+		require.InDelta(t, position.Longitude, result.Longitude, 0.000000001)
+		require.InDelta(t, position.Latitude, result.Latitude, 0.000000001)
+		require.InDelta(t, position.Altitude, result.Altitude, 0.0001)
+	}
+}
+
+func TestPosition_ENU(t *testing.T) {
+
+	reference := NewPositionWithAltitude(-79.9822, 40.4461, 250)
+
+	// The reference is the origin of its own frame
+	origin := reference.ENU(reference)
+	require.InDelta(t, 0, origin.East, 0.000001)
+	require.InDelta(t, 0, origin.North, 0.000001)
+	require.InDelta(t, 0, origin.Up, 0.000001)
+
+	// Directly overhead
+	overhead := NewPositionWithAltitude(-79.9822, 40.4461, 350).ENU(reference)
+	require.InDelta(t, 0, overhead.East, 0.000001)
+	require.InDelta(t, 0, overhead.North, 0.000001)
+	require.InDelta(t, 100, overhead.Up, 0.000001)
+
+	// A little north and east, at the same altitude
+	nearby := NewPositionWithAltitude(-79.9812, 40.4471, 250).ENU(reference)
+	require.InDelta(t, 84.8, nearby.East, 0.1)
+	require.InDelta(t, 111.0, nearby.North, 0.1)
+	require.InDelta(t, 0, nearby.Up, 0.01)
+	require.InDelta(t, 139.7, nearby.Distance(), 0.1)
+}
+
+func TestENU_Position(t *testing.T) {
+
+	reference := NewPositionWithAltitude(151.2153, -33.8568, 10)
+
+	for _, enu := range []ENU{
+		{East: 0, North: 0, Up: 0},
+		{East: 100, North: -250, Up: 120},
+		{East: -5000, North: 3000, Up: -10},
+	} {
+		result := enu.Position(reference).ENU(reference)
+		require.InDelta(t, enu.East, result.East, 0.0001)
+		require.InDelta(t, enu.North, result.North, 0.0001)
+		require.InDelta(t, enu.Up, result.Up, 0.0001)
+	}
+}
+
+func TestPosition_SlantDistance(t *testing.T) {
+
+	ground := NewPosition(-79.9822, 40.4461)
+	drone := NewPositionWithAltitude(-79.9822, 40.4461, 120)
+	require.InDelta(t, 120, ground.SlantDistance(drone), 0.000001)
+	require.InDelta(t, 120, drone.SlantDistance(ground), 0.000001)
+
+	// One degree of longitude along the equator is a chord slightly shorter than the arc
+	require.InDelta(t, 111318.078, NewPosition(0, 0).SlantDistance(NewPosition(1, 0)), 0.001)
+}
@@ -0,0 +1,98 @@
+package geo
+
+import "math"
+
+// Ellipsoid describes the reference ellipsoid (the mathematical shape of the
+// Earth) that a coordinate reference system is built on.
+type Ellipsoid struct {
+	SemiMajorAxis float64 // Equatorial radius, in meters
+	Flattening    float64 // (a-b)/a, where a and b are the equatorial and polar radii
+}
+
+// Reference ellipsoids used by the coordinate reference systems in this package
+var (
+	// EllipsoidWGS84 is the World Geodetic System 1984 ellipsoid, which is the
+	// (implied) reference for every Position in this package.
+	EllipsoidWGS84 = Ellipsoid{SemiMajorAxis: 6378137, Flattening: 1 / 298.257223563}
+
+	// EllipsoidGRS80 is used by NAD83, ETRS89, RGF93, and GDA94/GDA2020
+	EllipsoidGRS80 = Ellipsoid{SemiMajorAxis: 6378137, Flattening: 1 / 298.257222101}
+
+	// EllipsoidAiry1830 is used by OSGB36 (the British National Grid)
+	EllipsoidAiry1830 = Ellipsoid{SemiMajorAxis: 6377563.396, Flattening: 1 / 299.3249646}
+
+	// EllipsoidClarke1866 is used by NAD27
+	EllipsoidClarke1866 = Ellipsoid{SemiMajorAxis: 6378206.4, Flattening: 1 / 294.978698214}
+
+	// EllipsoidInternational1924 (also called Hayford) is used by ED50
+	EllipsoidInternational1924 = Ellipsoid{SemiMajorAxis: 6378388, Flattening: 1 / 297.0}
+)
+
+// EccentricitySquared returns the square of the first eccentricity (e²)
+func (ellipsoid Ellipsoid) EccentricitySquared() float64 {
+	return ellipsoid.Flattening * (2 - ellipsoid.Flattening)
+}
+
+// Eccentricity returns the first eccentricity (e)
+func (ellipsoid Ellipsoid) Eccentricity() float64 {
+	return math.Sqrt(ellipsoid.EccentricitySquared())
+}
+
+// SemiMinorAxis returns the polar radius, in meters
+func (ellipsoid Ellipsoid) SemiMinorAxis() float64 {
+	return ellipsoid.SemiMajorAxis * (1 - ellipsoid.Flattening)
+}
+
+// toCartesian converts a geodetic Position (on this ellipsoid) into
+// Earth-centered, Earth-fixed X, Y, Z coordinates, in meters
+func (ellipsoid Ellipsoid) toCartesian(position Position) (x float64, y float64, z float64) {
+
+	latitude := degreesToRadians(position.Latitude)
+	longitude := degreesToRadians(position.Longitude)
+	eccentricitySquared := ellipsoid.EccentricitySquared()
+
+	sinLatitude := math.Sin(latitude)
+	cosLatitude := math.Cos(latitude)
+
+	// Radius of curvature in the prime vertical
+	radius := ellipsoid.SemiMajorAxis / math.Sqrt(1-eccentricitySquared*sinLatitude*sinLatitude)
+
+	x = (radius + position.Altitude) * cosLatitude * math.Cos(longitude)
+	y = (radius + position.Altitude) * cosLatitude * math.Sin(longitude)
+	z = (radius*(1-eccentricitySquared) + position.Altitude) * sinLatitude
+
+	return x, y, z
+}
+
+// fromCartesian converts Earth-centered, Earth-fixed X, Y, Z coordinates into a
+// geodetic Position on this ellipsoid. It iterates Bowring's formula, which
+// converges to well under a millimeter within a few rounds.
+func (ellipsoid Ellipsoid) fromCartesian(x float64, y float64, z float64) Position {
+
+	eccentricitySquared := ellipsoid.EccentricitySquared()
+	distance := math.Hypot(x, y)
+
+	longitude := math.Atan2(y, x)
+	latitude := math.Atan2(z, distance*(1-eccentricitySquared))
+
+	var altitude float64
+
+	for range 6 {
+		sinLatitude := math.Sin(latitude)
+		radius := ellipsoid.SemiMajorAxis / math.Sqrt(1-eccentricitySquared*sinLatitude*sinLatitude)
+		altitude = distance*math.Cos(latitude) + z*sinLatitude - ellipsoid.SemiMajorAxis*math.Sqrt(1-eccentricitySquared*sinLatitude*sinLatitude)
+		latitude = math.Atan2(z, distance*(1-eccentricitySquared*radius/(radius+altitude)))
+	}
+
+	return NewPositionWithAltitude(radiansToDegrees(longitude), radiansToDegrees(latitude), altitude)
+}
+
+// degreesToRadians converts an angle in degrees into radians
+func degreesToRadians(degrees float64) float64 {
+	return degrees * math.Pi / 180
+}
+
+// radiansToDegrees converts an angle in radians into degrees
+func radiansToDegrees(radians float64) float64 {
+	return radians * 180 / math.Pi
+}
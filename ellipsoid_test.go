@@ -0,0 +1,30 @@
+package geo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEllipsoid_SemiMinorAxis(t *testing.T) {
+	require.InDelta(t, 6356752.314245, EllipsoidWGS84.SemiMinorAxis(), 0.000001)
+	require.InDelta(t, 6356752.314140, EllipsoidGRS80.SemiMinorAxis(), 0.000001)
+	require.InDelta(t, 6356256.909, EllipsoidAiry1830.SemiMinorAxis(), 0.001)
+	require.InDelta(t, 6356583.800, EllipsoidClarke1866.SemiMinorAxis(), 0.001)
+	require.InDelta(t, 6356911.946, EllipsoidInternational1924.SemiMinorAxis(), 0.001)
+}
+
+func TestEllipsoid_Cartesian(t *testing.T) {
+
+	// A point on the equator and prime meridian sits exactly one semi-major axis from the center
+	x, y, z := EllipsoidWGS84.toCartesian(NewPosition(0, 0))
+	require.InDelta(t, 6378137, x, 0.000001)
+	require.InDelta(t, 0, y, 0.000001)
+	require.InDelta(t, 0, z, 0.000001)
+
+	position := NewPositionWithAltitude(-79.9822, 40.4461, 1234.5)
+	result := EllipsoidInternational1924.fromCartesian(EllipsoidInternational1924.toCartesian(position))
+	require.InDelta(t, position.Longitude, result.Longitude, 0.000000001)
+	require.InDelta(t, position.Latitude, result.Latitude, 0.000000001)
+	require.InDelta(t, position.Altitude, result.Altitude, 0.0001)
+}
@@ -0,0 +1,133 @@
+package geo
+
+import (
+	"encoding/json"
+
+	"github.com/benpate/derp"
+	"github.com/benpate/rosetta/mapof"
+)
+
+// Feature represents a GeoJSON "Feature" object: a geometry, plus any
+// properties that describe it.
+// https://datatracker.ietf.org/doc/html/rfc7946#section-3.2
+type Feature struct {
+	ID         any       // Optional identifier (a string or a number)
+	Geometry   Geometry  // Point, LineString, Polygon, or nil for an unlocated Feature
+	Properties mapof.Any // Optional properties, which may be nil
+}
+
+// NewFeature returns a Feature with the given geometry and properties
+func NewFeature(geometry Geometry, properties mapof.Any) Feature {
+	return Feature{
+		Geometry:   geometry,
+		Properties: properties,
+	}
+}
+
+// IsZero returns TRUE if this Feature has no ID, geometry, or properties
+func (feature Feature) IsZero() bool {
+	return (feature.ID == nil) && (feature.Geometry == nil) && (len(feature.Properties) == 0)
+}
+
+// NotZero returns TRUE if this Feature has an ID, a geometry, or any properties
+func (feature Feature) NotZero() bool {
+	return !feature.IsZero()
+}
+
+// BoundingBox returns the smallest BoundingBox that contains this Feature's geometry
+func (feature Feature) BoundingBox() BoundingBox {
+
+	if feature.Geometry == nil {
+		return BoundingBox{}
+	}
+
+	return feature.Geometry.BoundingBox()
+}
+
+/******************************************
+ * Marhshalling methods
+ ******************************************/
+
+// featureJSON is the intermediate format used to marshal and unmarshal Features.
+// The geometry is left raw so that its "type" can be read before it is parsed.
+type featureJSON struct {
+	Type       string          `json:"type"`
+	ID         any             `json:"id,omitempty"`
+	Geometry   json.RawMessage `json:"geometry"`
+	Properties mapof.Any       `json:"properties"`
+}
+
+// MarshalJSON is a custom json.Marshaller that returns this Feature
+// as a GeoJSON object. A nil geometry or nil properties are written as `null`.
+func (feature Feature) MarshalJSON() ([]byte, error) {
+
+	const location = "geo.Feature.MarshalJSON"
+
+	geometry, err := json.Marshal(feature.Geometry)
+
+	if err != nil {
+		return nil, derp.Wrap(err, location, "Unable to marshal geometry", feature.Geometry)
+	}
+
+	result, err := json.Marshal(featureJSON{
+		Type:       PropertyTypeFeature,
+		ID:         feature.ID,
+		Geometry:   geometry,
+		Properties: feature.Properties,
+	})
+
+	if err != nil {
+		return nil, derp.Wrap(err, location, "Unable to marshal feature", feature.ID)
+	}
+
+	return result, nil
+}
+
+/******************************************
+ * Unmarhshalling methods
+ ******************************************/
+
+// UnmarshalJSON is a custom json.Unmarshaller that parses a GeoJSON
+// Feature into this Feature object.
+func (feature *Feature) UnmarshalJSON(data []byte) error {
+
+	const location = "geo.Feature.UnmarshalJSON"
+
+	// Unmarshall JSON into an intermediate object
+	intermediate := featureJSON{}
+
+	if err := json.Unmarshal(data, &intermediate); err != nil {
+		return derp.Wrap(err, location, "Unable to unmarshal original JSON", string(data))
+	}
+
+	// Validate the "type" property
+	if intermediate.Type != PropertyTypeFeature {
+		return derp.Internal(location, "Invalid GeoJSON. Type must be 'Feature'", intermediate.Type)
+	}
+
+	// Parse the geometry (a missing geometry is the same as `null`)
+	var geometry Geometry
+
+	if len(intermediate.Geometry) > 0 {
+
+		var err error
+		geometry, err = UnmarshalGeometryJSON(intermediate.Geometry)
+
+		if err != nil {
+			return derp.Wrap(err, location, "Unable to unmarshal geometry", intermediate.ID)
+		}
+	}
+
+	// Validate the "id" property, which must be a string or a number
+	switch intermediate.ID.(type) {
+	case nil, string, float64:
+	default:
+		return derp.Internal(location, "Invalid GeoJSON. ID must be a string or a number", intermediate.ID)
+	}
+
+	feature.ID = intermediate.ID
+	feature.Geometry = geometry
+	feature.Properties = intermediate.Properties
+
+	return nil
+}
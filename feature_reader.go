@@ -0,0 +1,152 @@
+package geo
+
+import (
+	"encoding/json"
+	"io"
+	"iter"
+
+	"github.com/benpate/derp"
+)
+
+// FeatureReader reads the Features out of a GeoJSON FeatureCollection one at a
+// time, so that memory use stays constant no matter how large the collection is.
+// Only the current Feature (and any small top-level members, such as "bbox")
+// is ever held in memory.
+type FeatureReader struct {
+	decoder *json.Decoder
+}
+
+// NewFeatureReader returns a FeatureReader that reads a FeatureCollection from the provided io.Reader
+func NewFeatureReader(reader io.Reader) *FeatureReader {
+	return &FeatureReader{
+		decoder: json.NewDecoder(reader),
+	}
+}
+
+// Features returns an iterator over every Feature in the FeatureCollection.
+// If a single Feature is invalid (for instance, it uses an unsupported geometry
+// type) then its error is yielded and iteration continues with the next Feature.
+// If the document itself is malformed then the error is yielded and iteration stops.
+// The underlying io.Reader is consumed, so the iterator can only be used once.
+func (reader *FeatureReader) Features() iter.Seq2[Feature, error] {
+
+	const location = "geo.FeatureReader.Features"
+
+	return func(yield func(Feature, error) bool) {
+
+		// Read the opening brace of the FeatureCollection
+		if err := reader.expectDelimiter('{'); err != nil {
+			yield(Feature{}, derp.Wrap(err, location, "FeatureCollection must be a JSON object"))
+			return
+		}
+
+		for reader.decoder.More() {
+
+			// Read the name of the next top-level member
+			token, err := reader.decoder.Token()
+
+			if err != nil {
+				yield(Feature{}, derp.Wrap(err, location, "Unable to read FeatureCollection"))
+				return
+			}
+
+			switch token {
+
+			// Validate the "type" property
+			case PropertyType:
+				var value string
+
+				if err := reader.decoder.Decode(&value); err != nil {
+					yield(Feature{}, derp.Wrap(err, location, "Unable to read FeatureCollection type"))
+					return
+				}
+
+				if value != PropertyTypeFeatureCollection {
+					yield(Feature{}, derp.Internal(location, "Invalid GeoJSON. Type must be 'FeatureCollection'", value))
+					return
+				}
+
+			// Stream each Feature in the "features" array
+			case PropertyFeatures:
+				if !reader.readFeatures(yield) {
+					return
+				}
+
+			// Skip all other top-level members
+			default:
+				if err := reader.decoder.Decode(&json.RawMessage{}); err != nil {
+					yield(Feature{}, derp.Wrap(err, location, "Unable to read FeatureCollection", token))
+					return
+				}
+			}
+		}
+
+		// Read the closing brace of the FeatureCollection
+		if err := reader.expectDelimiter('}'); err != nil {
+			yield(Feature{}, derp.Wrap(err, location, "Unable to read FeatureCollection"))
+		}
+	}
+}
+
+// readFeatures yields each Feature in the "features" array. It returns FALSE if
+// iteration should stop, either because the caller is done, or because the
+// document is malformed.
+func (reader *FeatureReader) readFeatures(yield func(Feature, error) bool) bool {
+
+	const location = "geo.FeatureReader.readFeatures"
+
+	if err := reader.expectDelimiter('['); err != nil {
+		yield(Feature{}, derp.Wrap(err, location, "Invalid GeoJSON. Features must be an array"))
+		return false
+	}
+
+	for reader.decoder.More() {
+
+		// Read the raw Feature first, so that an invalid Feature does not
+		// prevent reading the rest of the collection
+		var raw json.RawMessage
+
+		if err := reader.decoder.Decode(&raw); err != nil {
+			yield(Feature{}, derp.Wrap(err, location, "Unable to read feature"))
+			return false
+		}
+
+		feature := Feature{}
+
+		if err := feature.UnmarshalJSON(raw); err != nil {
+			if !yield(Feature{}, derp.Wrap(err, location, "Invalid feature")) {
+				return false
+			}
+			continue
+		}
+
+		if !yield(feature, nil) {
+			return false
+		}
+	}
+
+	if err := reader.expectDelimiter(']'); err != nil {
+		yield(Feature{}, derp.Wrap(err, location, "Unable to read features"))
+		return false
+	}
+
+	return true
+}
+
+// expectDelimiter reads the next JSON token, and returns an error if it is not the expected delimiter
+func (reader *FeatureReader) expectDelimiter(expected json.Delim) error {
+
+	const location = "geo.FeatureReader.expectDelimiter"
+
+	token, err := reader.decoder.Token()
+
+	if err != nil {
+		return derp.Wrap(err, location, "Unable to read JSON token", expected.String())
+	}
+
+	if token != expected {
+		return derp.Internal(location, "Unexpected JSON token", expected.String(), token)
+	}
+
+	return nil
+}
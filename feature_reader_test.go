@@ -0,0 +1,151 @@
+package geo
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFeatureReader(t *testing.T) {
+
+	reader := NewFeatureReader(strings.NewReader(`{
+		"type": "FeatureCollection",
+		"name": "sample",
+		"bbox": [1, 2, 5, 6],
+		"features": [
+			{"type":"Feature","id":"a","geometry":{"type":"Point","coordinates":[1,2]},"properties":{"name":"A"}},
+			{"type":"Feature","id":"b","geometry":{"type":"LineString","coordinates":[[1,2],[3,4]]},"properties":null},
+			{"type":"Feature","geometry":null,"properties":{"name":"C"}}
+		]
+	}`))
+
+	var ids []any
+
+	for feature, err := range reader.Features() {
+		require.Nil(t, err)
+		ids = append(ids, feature.ID)
+	}
+
+	require.Equal(t, []any{"a", "b", nil}, ids)
+}
+
+func TestFeatureReader_Empty(t *testing.T) {
+
+	reader := NewFeatureReader(strings.NewReader(`{"type":"FeatureCollection","features":[]}`))
+
+	for range reader.Features() {
+		t.Fatal("expected no features")
+	}
+}
+
+func TestFeatureReader_InvalidFeature(t *testing.T) {
+
+	// An unsupported geometry is reported, but does not stop the iterator
+	reader := NewFeatureReader(strings.NewReader(`{"type":"FeatureCollection","features":[
+		{"type":"Feature","id":1,"geometry":{"type":"MultiPoint","coordinates":[[1,2]]}},
+		{"type":"Feature","id":2,"geometry":{"type":"Point","coordinates":[1,2]}}
+	]}`))
+
+	var errors int
+	var ids []any
+
+	for feature, err := range reader.Features() {
+		if err != nil {
+			errors++
+			continue
+		}
+		ids = append(ids, feature.ID)
+	}
+
+	require.Equal(t, 1, errors)
+	require.Equal(t, []any{float64(2)}, ids)
+}
+
+func TestFeatureReader_Malformed(t *testing.T) {
+
+	for _, data := range []string{
+		``,
+		`[]`,
+		`{"type":"Feature","features":[]}`,
+		`{"type":"FeatureCollection","features":{}}`,
+		`{"type":"FeatureCollection","features":[{"type":"Feature"`,
+		`{"type":"FeatureCollection","features":[]`,
+	} {
+		var errors int
+
+		for _, err := range NewFeatureReader(strings.NewReader(data)).Features() {
+			if err != nil {
+				errors++
+			}
+		}
+
+		require.Equal(t, 1, errors, data)
+	}
+}
+
+func TestFeatureReader_Break(t *testing.T) {
+
+	reader := NewFeatureReader(strings.NewReader(`{"type":"FeatureCollection","features":[
+		{"type":"Feature","id":1,"geometry":null},
+		{"type":"Feature","id":2,"geometry":null}
+	]}`))
+
+	var count int
+
+	for range reader.Features() {
+		count++
+		break
+	}
+
+	require.Equal(t, 1, count)
+}
+
+// TestFeatureReader_Streaming confirms that features are read as they arrive,
+// by reading from a FeatureCollection that never ends.
+func TestFeatureReader_Streaming(t *testing.T) {
+
+	source := &endlessFeatureCollection{}
+	reader := NewFeatureReader(source)
+
+	var count int
+
+	for feature, err := range reader.Features() {
+		require.Nil(t, err)
+		require.Equal(t, NewPoint(1, 2), feature.Geometry)
+
+		count++
+
+		if count == 100_000 {
+			break
+		}
+	}
+
+	// Only a small buffer beyond the features that were actually used has been read
+	require.Less(t, source.read, 100_000*len(endlessFeature)+64*1024)
+}
+
+const endlessFeature = `{"type":"Feature","geometry":{"type":"Point","coordinates":[1,2]},"properties":{"name":"Feature"}},`
+
+// endlessFeatureCollection is an io.Reader that returns a FeatureCollection with infinitely many Features
+type endlessFeatureCollection struct {
+	read int
+}
+
+func (source *endlessFeatureCollection) Read(buffer []byte) (int, error) {
+
+	const header = `{"type":"FeatureCollection","features":[`
+
+	for index := range buffer {
+
+		if source.read < len(header) {
+			buffer[index] = header[source.read]
+		} else {
+			buffer[index] = endlessFeature[(source.read-len(header))%len(endlessFeature)]
+		}
+
+		source.read++
+	}
+
+	return len(buffer), nil
+}
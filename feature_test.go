@@ -0,0 +1,88 @@
+package geo
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/benpate/rosetta/mapof"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFeature_Zeroer(t *testing.T) {
+	require.True(t, Feature{}.IsZero())
+	require.False(t, NewFeature(NewPoint(1, 2), nil).IsZero())
+	require.False(t, Feature{ID: "abc"}.IsZero())
+	require.True(t, NewFeature(nil, nil).IsZero())
+	require.False(t, NewFeature(nil, nil).NotZero())
+}
+
+func TestFeature_BoundingBox(t *testing.T) {
+	require.Equal(t, NewBoundingBox(1, 2, 1, 2), NewFeature(NewPoint(1, 2), nil).BoundingBox())
+	require.Equal(t, BoundingBox{}, Feature{}.BoundingBox())
+}
+
+func TestFeature_MarshalJSON(t *testing.T) {
+
+	feature := Feature{
+		ID:         "abc",
+		Geometry:   NewPoint(1, 2),
+		Properties: mapof.Any{"name": "Somewhere"},
+	}
+
+	result, err := json.Marshal(feature)
+	require.Nil(t, err)
+	require.Equal(t, `{"type":"Feature","id":"abc","geometry":{"type":"Point","coordinates":[1,2]},"properties":{"name":"Somewhere"}}`, string(result))
+}
+
+func TestFeature_MarshalJSON_Empty(t *testing.T) {
+	result, err := json.Marshal(Feature{})
+	require.Nil(t, err)
+	require.Equal(t, `{"type":"Feature","geometry":null,"properties":null}`, string(result))
+}
+
+func TestFeature_UnmarshalJSON(t *testing.T) {
+
+	feature := Feature{}
+	err := json.Unmarshal([]byte(`{"type":"Feature","id":7,"geometry":{"type":"LineString","coordinates":[[1,2],[3,4]]},"properties":{"name":"Route"}}`), &feature)
+
+	require.Nil(t, err)
+	require.Equal(t, float64(7), feature.ID)
+	require.Equal(t, NewLineString(NewPosition(1, 2), NewPosition(3, 4)), feature.Geometry)
+	require.Equal(t, "Route", feature.Properties.GetString("name"))
+}
+
+func TestFeature_UnmarshalJSON_NullGeometry(t *testing.T) {
+
+	feature := Feature{Geometry: NewPoint(1, 2)}
+	err := json.Unmarshal([]byte(`{"type":"Feature","geometry":null,"properties":null}`), &feature)
+
+	require.Nil(t, err)
+	require.True(t, feature.IsZero())
+}
+
+func TestFeature_UnmarshalJSON_RoundTrip(t *testing.T) {
+
+	original := Feature{
+		ID:         "parcel-1",
+		Geometry:   NewPolygon(NewPosition(1, 2), NewPosition(3, 4), NewPosition(5, 6), NewPosition(1, 2)),
+		Properties: mapof.Any{"area": 12.5},
+	}
+
+	data, err := json.Marshal(original)
+	require.Nil(t, err)
+
+	result := Feature{}
+	require.Nil(t, json.Unmarshal(data, &result))
+	require.Equal(t, original, result)
+}
+
+func TestFeature_UnmarshalJSON_Invalid(t *testing.T) {
+
+	feature := Feature{}
+
+	require.NotNil(t, feature.UnmarshalJSON([]byte(`{"type":"Point","coordinates":[1,2]}`)))
+	require.NotNil(t, feature.UnmarshalJSON([]byte(`{"type":"Feature","geometry":{"type":"MultiPoint","coordinates":[]}}`)))
+	require.NotNil(t, feature.UnmarshalJSON([]byte(`{"type":"Feature","id":true,"geometry":null}`)))
+	require.NotNil(t, feature.UnmarshalJSON([]byte(`{"type":"Feature","properties":[]}`)))
+	require.NotNil(t, feature.UnmarshalJSON([]byte(`{`)))
+}
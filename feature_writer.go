@@ -0,0 +1,104 @@
+package geo
+
+import (
+	"io"
+	"iter"
+
+	"github.com/benpate/derp"
+)
+
+// FeatureWriter writes a GeoJSON FeatureCollection one Feature at a time, so that
+// memory use stays constant no matter how large the collection is. Each Feature
+// is written on its own line. Callers must call Close to finish the collection.
+// Wrap the destination in a bufio.Writer when it is a file or network connection.
+type FeatureWriter struct {
+	writer  io.Writer
+	started bool
+	closed  bool
+}
+
+// NewFeatureWriter returns a FeatureWriter that writes a FeatureCollection to the provided io.Writer
+func NewFeatureWriter(writer io.Writer) *FeatureWriter {
+	return &FeatureWriter{
+		writer: writer,
+	}
+}
+
+// Write adds a single Feature to the FeatureCollection
+func (writer *FeatureWriter) Write(feature Feature) error {
+
+	const location = "geo.FeatureWriter.Write"
+
+	if writer.closed {
+		return derp.Internal(location, "FeatureWriter is already closed")
+	}
+
+	data, err := feature.MarshalJSON()
+
+	if err != nil {
+		return derp.Wrap(err, location, "Unable to marshal feature", feature.ID)
+	}
+
+	// The first Feature opens the collection. All others follow a comma.
+	separator := []byte(",\n")
+
+	if !writer.started {
+		separator = []byte(`{"type":"FeatureCollection","features":[` + "\n")
+		writer.started = true
+	}
+
+	if _, err := writer.writer.Write(separator); err != nil {
+		return derp.Wrap(err, location, "Unable to write separator")
+	}
+
+	if _, err := writer.writer.Write(data); err != nil {
+		return derp.Wrap(err, location, "Unable to write feature", feature.ID)
+	}
+
+	return nil
+}
+
+// WriteAll adds every Feature from an iterator (such as FeatureReader.Features)
+// to the FeatureCollection. It stops at the first error.
+func (writer *FeatureWriter) WriteAll(features iter.Seq2[Feature, error]) error {
+
+	const location = "geo.FeatureWriter.WriteAll"
+
+	for feature, err := range features {
+
+		if err != nil {
+			return derp.Wrap(err, location, "Unable to read feature")
+		}
+
+		if err := writer.Write(feature); err != nil {
+			return derp.Wrap(err, location, "Unable to write feature")
+		}
+	}
+
+	return nil
+}
+
+// Close finishes the FeatureCollection. If no Features were written, then
+// this writes an empty FeatureCollection. Close does not close the underlying io.Writer.
+func (writer *FeatureWriter) Close() error {
+
+	const location = "geo.FeatureWriter.Close"
+
+	if writer.closed {
+		return nil
+	}
+
+	writer.closed = true
+
+	closing := "\n]}\n"
+
+	if !writer.started {
+		closing = `{"type":"FeatureCollection","features":[]}` + "\n"
+	}
+
+	if _, err := io.WriteString(writer.writer, closing); err != nil {
+		return derp.Wrap(err, location, "Unable to close FeatureCollection")
+	}
+
+	return nil
+}
@@ -0,0 +1,78 @@
+package geo
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"iter"
+	"strings"
+	"testing"
+
+	"github.com/benpate/rosetta/mapof"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFeatureWriter(t *testing.T) {
+
+	buffer := bytes.Buffer{}
+	writer := NewFeatureWriter(&buffer)
+
+	require.Nil(t, writer.Write(Feature{ID: "a", Geometry: NewPoint(1, 2)}))
+	require.Nil(t, writer.Write(Feature{ID: "b", Properties: mapof.Any{"name": "B"}}))
+	require.Nil(t, writer.Close())
+
+	expected := `{"type":"FeatureCollection","features":[
+{"type":"Feature","id":"a","geometry":{"type":"Point","coordinates":[1,2]},"properties":null},
+{"type":"Feature","id":"b","geometry":null,"properties":{"name":"B"}}
+]}
+`
+	require.Equal(t, expected, buffer.String())
+	require.True(t, json.Valid(buffer.Bytes()))
+}
+
+func TestFeatureWriter_Empty(t *testing.T) {
+
+	buffer := bytes.Buffer{}
+	writer := NewFeatureWriter(&buffer)
+
+	require.Nil(t, writer.Close())
+	require.Nil(t, writer.Close())
+	require.Equal(t, `{"type":"FeatureCollection","features":[]}`+"\n", buffer.String())
+}
+
+func TestFeatureWriter_Closed(t *testing.T) {
+
+	writer := NewFeatureWriter(&bytes.Buffer{})
+
+	require.Nil(t, writer.Close())
+	require.NotNil(t, writer.Write(Feature{}))
+}
+
+func TestFeatureWriter_WriteAll_RoundTrip(t *testing.T) {
+
+	original := `{"type":"FeatureCollection","features":[
+{"type":"Feature","id":"a","geometry":{"type":"Point","coordinates":[1,2]},"properties":{"name":"A"}},
+{"type":"Feature","id":"b","geometry":{"type":"Polygon","coordinates":[[[1,2],[3,4],[5,6],[1,2]]]},"properties":null}
+]}
+`
+
+	buffer := bytes.Buffer{}
+	writer := NewFeatureWriter(&buffer)
+
+	require.Nil(t, writer.WriteAll(NewFeatureReader(strings.NewReader(original)).Features()))
+	require.Nil(t, writer.Close())
+	require.Equal(t, original, buffer.String())
+}
+
+func TestFeatureWriter_WriteAll_Error(t *testing.T) {
+
+	features := iter.Seq2[Feature, error](func(yield func(Feature, error) bool) {
+		_ = yield(Feature{ID: "a"}, nil) && yield(Feature{}, errors.New("boom")) && yield(Feature{ID: "c"}, nil)
+	})
+
+	buffer := bytes.Buffer{}
+	writer := NewFeatureWriter(&buffer)
+
+	require.NotNil(t, writer.WriteAll(features))
+	require.NotContains(t, buffer.String(), `"c"`)
+}
@@ -0,0 +1,215 @@
+package geo
+
+import (
+	"bytes"
+	"encoding/json"
+	"slices"
+
+	"github.com/benpate/derp"
+)
+
+// ForeignMembers holds the members of a GeoJSON object that are not part of its
+// type (such as "crs", "id" on a geometry, or vendor-specific keys), exactly as
+// they appeared in the original JSON.
+// https://datatracker.ietf.org/doc/html/rfc7946#section-6.1
+type ForeignMembers map[string]json.RawMessage
+
+// WithForeignMembers wraps a GeoJSON value (a Point, LineString, Polygon, or Feature)
+// so that any foreign members are captured when it is unmarshalled, and written
+// back out unchanged when it is marshalled. Use it in place of the bare type
+// wherever GeoJSON must pass through losslessly:
+//
+//	var point geo.WithForeignMembers[geo.Point]
+//	err := json.Unmarshal(data, &point)
+//	fmt.Println(point.Value.Longitude, string(point.ForeignMembers["crs"]))
+type WithForeignMembers[T any] struct {
+	Value          T
+	ForeignMembers ForeignMembers
+}
+
+// NewWithForeignMembers returns a value wrapped with the provided foreign members
+func NewWithForeignMembers[T any](value T, foreignMembers ForeignMembers) WithForeignMembers[T] {
+	return WithForeignMembers[T]{
+		Value:          value,
+		ForeignMembers: foreignMembers,
+	}
+}
+
+// MarshalJSON is a custom json.Marshaller that writes the wrapped value, followed by
+// its foreign members (in alphabetical order). Members that belong to the wrapped
+// value's type always take precedence. Geometries that marshal as `null` (such as
+// a zero Point) are written in full when there are foreign members, so that even
+// these objects pass through unchanged.
+func (wrapper WithForeignMembers[T]) MarshalJSON() ([]byte, error) {
+
+	const location = "geo.WithForeignMembers.MarshalJSON"
+
+	if len(wrapper.ForeignMembers) == 0 {
+
+		result, err := json.Marshal(wrapper.Value)
+
+		if err != nil {
+			return nil, derp.Wrap(err, location, "Unable to marshal value")
+		}
+
+		return result, nil
+	}
+
+	result, err := marshalGeoJSONObject(wrapper.Value)
+
+	if err != nil {
+		return nil, derp.Wrap(err, location, "Unable to marshal value")
+	}
+
+	if string(result) == "null" {
+		result = []byte("{}")
+	}
+
+	if result[0] != '{' {
+		return nil, derp.Internal(location, "Foreign members can only be added to JSON objects", string(result))
+	}
+
+	// Find the members that the value already wrote
+	written := map[string]json.RawMessage{}
+
+	if err := json.Unmarshal(result, &written); err != nil {
+		return nil, derp.Wrap(err, location, "Unable to read marshalled value", string(result))
+	}
+
+	known, _ := geoJSONMembers(wrapper.Value)
+
+	// Append each foreign member before the closing brace
+	result = bytes.TrimRight(result, " \t\r\n")
+	result = result[:len(result)-1]
+
+	keys := make([]string, 0, len(wrapper.ForeignMembers))
+
+	for key := range wrapper.ForeignMembers {
+		if _, exists := written[key]; !exists && !slices.Contains(known, key) {
+			keys = append(keys, key)
+		}
+	}
+
+	slices.Sort(keys)
+
+	for _, key := range keys {
+
+		name, err := json.Marshal(key)
+
+		if err != nil {
+			return nil, derp.Wrap(err, location, "Unable to marshal foreign member name", key)
+		}
+
+		value := wrapper.ForeignMembers[key]
+
+		if !json.Valid(value) {
+			return nil, derp.Internal(location, "Foreign member must be valid JSON", key, string(value))
+		}
+
+		if len(written) > 0 {
+			result = append(result, ',')
+		}
+
+		result = append(result, name...)
+		result = append(result, ':')
+		result = append(result, value...)
+		written[key] = value
+	}
+
+	return append(result, '}'), nil
+}
+
+// UnmarshalJSON is a custom json.Unmarshaller that parses the wrapped value, and then
+// captures every member that does not belong to the wrapped value's type.
+func (wrapper *WithForeignMembers[T]) UnmarshalJSON(data []byte) error {
+
+	const location = "geo.WithForeignMembers.UnmarshalJSON"
+
+	// Unmarshal the wrapped value
+	var value T
+
+	if err := json.Unmarshal(data, &value); err != nil {
+		return derp.Wrap(err, location, "Unable to unmarshal value", string(data))
+	}
+
+	// Collect every member of the original object
+	var members ForeignMembers
+
+	if err := json.Unmarshal(data, &members); err != nil {
+		return derp.Wrap(err, location, "Unable to unmarshal members", string(data))
+	}
+
+	// Remove the members that belong to the wrapped value
+	known, ok := geoJSONMembers(value)
+
+	if !ok {
+
+		// Other types are checked by marshalling them again
+		marshalled, err := json.Marshal(value)
+
+		if err != nil {
+			return derp.Wrap(err, location, "Unable to marshal value")
+		}
+
+		if marshalled[0] == '{' {
+
+			written := map[string]json.RawMessage{}
+
+			if err := json.Unmarshal(marshalled, &written); err != nil {
+				return derp.Wrap(err, location, "Unable to read marshalled value", string(marshalled))
+			}
+
+			for key := range written {
+				known = append(known, key)
+			}
+		}
+	}
+
+	for _, key := range known {
+		delete(members, key)
+	}
+
+	if len(members) == 0 {
+		members = nil
+	}
+
+	wrapper.Value = value
+	wrapper.ForeignMembers = members
+
+	return nil
+}
+
+// geoJSONMembers returns the members that belong to each GeoJSON type in this
+// package. It returns FALSE for any other type.
+func geoJSONMembers(value any) ([]string, bool) {
+
+	switch value.(type) {
+
+	case Point, LineString, Polygon:
+		return []string{PropertyType, PropertyCoordinates}, true
+
+	case Feature:
+		return []string{PropertyType, PropertyID, PropertyGeometry, PropertyProperties}, true
+	}
+
+	return nil, false
+}
+
+// marshalGeoJSONObject marshals a value into JSON. Geometries are always written
+// as GeoJSON objects, even the zero values that MarshalJSON writes as `null`.
+func marshalGeoJSONObject(value any) ([]byte, error) {
+
+	switch geometry := value.(type) {
+
+	case Point:
+		return json.Marshal(geometry.MarshalStruct())
+
+	case LineString:
+		return json.Marshal(geometry.MarshalStruct())
+
+	case Polygon:
+		return json.Marshal(geometry.MarshalStruct())
+	}
+
+	return json.Marshal(value)
+}
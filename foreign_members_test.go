@@ -0,0 +1,148 @@
+package geo
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/benpate/rosetta/mapof"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithForeignMembers_Point(t *testing.T) {
+
+	original := `{"type":"Point","coordinates":[1,2],"crs":{"type":"name","properties":{"name":"EPSG:4326"}},"id":"abc","x-vendor":[1, 2,  3]}`
+
+	point := WithForeignMembers[Point]{}
+	require.Nil(t, json.Unmarshal([]byte(original), &point))
+
+	require.Equal(t, NewPoint(1, 2), point.Value)
+	require.Equal(t, ForeignMembers{
+		"crs":      json.RawMessage(`{"type":"name","properties":{"name":"EPSG:4326"}}`),
+		"id":       json.RawMessage(`"abc"`),
+		"x-vendor": json.RawMessage(`[1, 2,  3]`),
+	}, point.ForeignMembers)
+
+	// Foreign members are written byte-for-byte (json.Marshal would compact their whitespace)
+	result, err := point.MarshalJSON()
+	require.Nil(t, err)
+	require.Equal(t, original, string(result))
+}
+
+func TestWithForeignMembers_NoForeignMembers(t *testing.T) {
+
+	original := `{"type":"LineString","coordinates":[[1,2],[3,4]]}`
+
+	lineString := WithForeignMembers[LineString]{}
+	require.Nil(t, json.Unmarshal([]byte(original), &lineString))
+	require.Nil(t, lineString.ForeignMembers)
+
+	result, err := json.Marshal(lineString)
+	require.Nil(t, err)
+	require.Equal(t, original, string(result))
+}
+
+func TestWithForeignMembers_Polygon(t *testing.T) {
+
+	original := `{"type":"Polygon","coordinates":[[[1,2],[3,4],[5,6],[1,2]]],"bbox":[1,2,5,6]}`
+
+	polygon := WithForeignMembers[Polygon]{}
+	require.Nil(t, json.Unmarshal([]byte(original), &polygon))
+	require.Equal(t, ForeignMembers{"bbox": json.RawMessage(`[1,2,5,6]`)}, polygon.ForeignMembers)
+
+	result, err := json.Marshal(polygon)
+	require.Nil(t, err)
+	require.Equal(t, original, string(result))
+}
+
+func TestWithForeignMembers_Feature(t *testing.T) {
+
+	original := `{"type":"Feature","id":"a","geometry":{"type":"Point","coordinates":[1,2]},"properties":{"name":"A"},"title":"Example"}`
+
+	feature := WithForeignMembers[Feature]{}
+	require.Nil(t, json.Unmarshal([]byte(original), &feature))
+
+	require.Equal(t, Feature{ID: "a", Geometry: NewPoint(1, 2), Properties: mapof.Any{"name": "A"}}, feature.Value)
+	require.Equal(t, ForeignMembers{"title": json.RawMessage(`"Example"`)}, feature.ForeignMembers)
+
+	result, err := json.Marshal(feature)
+	require.Nil(t, err)
+	require.Equal(t, original, string(result))
+}
+
+func TestWithForeignMembers_StructField(t *testing.T) {
+
+	type record struct {
+		Location WithForeignMembers[Point] `json:"location"`
+	}
+
+	original := `{"location":{"type":"Point","coordinates":[1,2],"accuracy":5}}`
+
+	value := record{}
+	require.Nil(t, json.Unmarshal([]byte(original), &value))
+	require.Equal(t, json.RawMessage(`5`), value.Location.ForeignMembers["accuracy"])
+
+	result, err := json.Marshal(value)
+	require.Nil(t, err)
+	require.Equal(t, original, string(result))
+}
+
+func TestWithForeignMembers_ZeroValue(t *testing.T) {
+
+	// A Point at [0,0] marshals as null, but its own members are still not foreign
+	original := `{"type":"Point","coordinates":[0,0],"crs":null}`
+
+	point := WithForeignMembers[Point]{}
+	require.Nil(t, json.Unmarshal([]byte(original), &point))
+	require.Equal(t, ForeignMembers{"crs": json.RawMessage(`null`)}, point.ForeignMembers)
+
+	result, err := json.Marshal(point)
+	require.Nil(t, err)
+	require.JSONEq(t, original, string(result))
+
+	// Other zero geometries are written in full, too
+	lineString := WithForeignMembers[LineString]{}
+	require.Nil(t, json.Unmarshal([]byte(`{"type":"LineString","coordinates":[],"id":7}`), &lineString))
+	require.Equal(t, ForeignMembers{"id": json.RawMessage(`7`)}, lineString.ForeignMembers)
+
+	result, err = json.Marshal(lineString)
+	require.Nil(t, err)
+	require.JSONEq(t, `{"type":"LineString","coordinates":[],"id":7}`, string(result))
+
+	// Features without an ID do not capture it as a foreign member
+	feature := WithForeignMembers[Feature]{}
+	require.Nil(t, json.Unmarshal([]byte(`{"type":"Feature","geometry":null,"properties":null}`), &feature))
+	require.Nil(t, feature.ForeignMembers)
+
+	// Without foreign members, zero values still marshal as null
+	result, err = json.Marshal(WithForeignMembers[Point]{})
+	require.Nil(t, err)
+	require.Equal(t, `null`, string(result))
+}
+
+func TestWithForeignMembers_Precedence(t *testing.T) {
+
+	// Members written by the value itself cannot be overridden
+	point := NewWithForeignMembers(NewPoint(1, 2), ForeignMembers{
+		"type":  json.RawMessage(`"LineString"`),
+		"extra": json.RawMessage(`true`),
+	})
+
+	result, err := json.Marshal(point)
+	require.Nil(t, err)
+	require.Equal(t, `{"type":"Point","coordinates":[1,2],"extra":true}`, string(result))
+}
+
+func TestWithForeignMembers_Invalid(t *testing.T) {
+
+	point := WithForeignMembers[Point]{ForeignMembers: ForeignMembers{"a": json.RawMessage(`true`)}}
+
+	require.NotNil(t, json.Unmarshal([]byte(`{"type":"Polygon","coordinates":[1,2]}`), &point))
+	require.NotNil(t, json.Unmarshal([]byte(`{`), &point))
+	require.Equal(t, ForeignMembers{"a": json.RawMessage(`true`)}, point.ForeignMembers)
+
+	_, err := json.Marshal(NewWithForeignMembers(NewPoint(1, 2), ForeignMembers{"bad": json.RawMessage(`{`)}))
+	require.NotNil(t, err)
+
+	_, err = json.Marshal(NewWithForeignMembers(NewPosition(1, 2), ForeignMembers{"a": json.RawMessage(`true`)}))
+	require.NotNil(t, err)
+}
@@ -1,12 +1,18 @@
 package geo
 
 import (
+	"math"
+	"slices"
+	"strings"
 	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
 )
 
 // FuzzPoint_UnmarshalJSON confirms that the Point JSON decoder never panics,
 // regardless of the input bytes. A well-formed input round-trips; a malformed
-// one must return an error instead of crashing.
+// one must return an error instead of crashing. Anything that the fast path
+// accepts must decode exactly as the general decoder does.
 func FuzzPoint_UnmarshalJSON(f *testing.F) {
 
 	f.Add(`{"type":"Point","coordinates":[1,2]}`)
@@ -19,10 +25,12 @@ func FuzzPoint_UnmarshalJSON(f *testing.F) {
 	f.Fuzz(func(t *testing.T, data string) {
 		point := Point{}
 		_ = point.UnmarshalJSON([]byte(data))
+		requireSameJSONDecoding(t, []byte(data))
 	})
 }
 
-// FuzzPolygon_UnmarshalJSON confirms that the Polygon JSON decoder never panics.
+// FuzzPolygon_UnmarshalJSON confirms that the Polygon JSON decoder never panics,
+// and that its fast path always agrees with the general decoder.
 func FuzzPolygon_UnmarshalJSON(f *testing.F) {
 
 	f.Add(`{"type":"Polygon","coordinates":[[[1,2],[3,4]]]}`)
@@ -35,6 +43,41 @@ func FuzzPolygon_UnmarshalJSON(f *testing.F) {
 	f.Fuzz(func(t *testing.T, data string) {
 		polygon := Polygon{}
 		_ = polygon.UnmarshalJSON([]byte(data))
+		requireSameJSONDecoding(t, []byte(data))
+	})
+}
+
+// FuzzPosition_UnmarshalJSON confirms that the Position JSON decoder never panics,
+// and that its fast path always agrees with the general decoder.
+func FuzzPosition_UnmarshalJSON(f *testing.F) {
+
+	for _, data := range decodeJSONCorpus {
+		f.Add(data)
+	}
+
+	f.Fuzz(func(t *testing.T, data string) {
+		position := Position{}
+		_ = position.UnmarshalJSON([]byte(data))
+		requireSameJSONDecoding(t, []byte(data))
+	})
+}
+
+// FuzzUnmarshalBSON confirms that the fast-path BSON decoders never panic, and
+// always agree with the general decoders. The general decoders are only called
+// on documents that the fast path accepts, because the driver's own decoder can
+// panic on some malformed documents.
+func FuzzUnmarshalBSON(f *testing.F) {
+
+	for _, value := range []any{NewPoint(1, 2), NewPointWithAltitude(1, 2, 3), NewPolygon(NewPosition(1, 2), NewPosition(3, 4), NewPosition(1, 2))} {
+		data, _ := bson.Marshal(value)
+		f.Add(data)
+	}
+
+	f.Add([]byte{})
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		requireSameBSONDecoding(t, data)
+		requireSamePositionBSONDecoding(t, bson.TypeArray, data)
 	})
 }
 
@@ -60,3 +103,189 @@ func FuzzNewPolygonFromString(f *testing.F) {
 		}
 	})
 }
+
+// FuzzDecodePolyline confirms that the polyline decoder never panics, and that
+// anything it accepts can be encoded and decoded again without changing.
+func FuzzDecodePolyline(f *testing.F) {
+
+	f.Add("_p~iF~ps|U_ulLnnqC_mqNvxq`@", PolylinePrecision5)
+	f.Add("_izlhA~rlgdF_{geC~ywl@_kwzCn`{nI", PolylinePrecision6)
+	f.Add("_p~iF", PolylinePrecision5)
+	f.Add("", 0)
+	f.Add("~~~~~~~~~~~~~~~~", PolylineMaxPrecision)
+
+	f.Fuzz(func(t *testing.T, data string, precision int) {
+
+		positions, err := DecodePolyline(data, precision)
+
+		if err != nil {
+			return
+		}
+
+		encoded, err := EncodePolyline(positions, precision)
+
+		if err != nil {
+			t.Fatalf("unable to encode decoded polyline %q: %v", data, err)
+		}
+
+		again, err := DecodePolyline(encoded, precision)
+
+		if err != nil {
+			t.Fatalf("unable to decode re-encoded polyline %q: %v", encoded, err)
+		}
+
+		if !slices.Equal(positions, again) {
+			t.Fatalf("expected %v, got %v", positions, again)
+		}
+	})
+}
+
+// FuzzParseGeoURI confirms that the geo URI parser never panics, and that
+// anything it accepts can be written and parsed again.
+func FuzzParseGeoURI(f *testing.F) {
+
+	f.Add("geo:37.78,-122.4;u=35")
+	f.Add("geo:37.78,-122.4;crs=wgs84;u=35;foo=bar%20baz")
+	f.Add("GEO:90,10,100")
+	f.Add("geo:1,2," + strings.Repeat("9", 400))
+	f.Add("geo:1,2;u=" + strings.Repeat("9", 400))
+	f.Add("geo:")
+	f.Add("")
+
+	f.Fuzz(func(t *testing.T, data string) {
+		geoURI, err := ParseGeoURI(data)
+
+		if err != nil {
+			return
+		}
+
+		if _, err := ParseGeoURI(geoURI.String()); err != nil {
+			t.Fatalf("expected %q to re-parse, got %v", geoURI.String(), err)
+		}
+	})
+}
+
+// FuzzParsePosition confirms that the human-readable coordinate parser never
+// panics, and never returns a Position that is off the planet.
+func FuzzParsePosition(f *testing.F) {
+
+	f.Add(`40°26'46"N 79°58'56"W`)
+	f.Add("N 40 26.767 W 79 58.933")
+	f.Add("+40.4461-079.9822/")
+	f.Add("40.4461, -79.9822")
+	f.Add("")
+
+	f.Fuzz(func(t *testing.T, data string) {
+		position, err := ParsePosition(data)
+
+		if err != nil {
+			return
+		}
+
+		if validateLongitudeLatitude(position) != nil {
+			t.Fatalf("expected a valid position, got %v", position)
+		}
+	})
+}
+
+// FuzzParseMGRS confirms that the MGRS parser never panics, and never returns
+// a Position that is off the planet.
+func FuzzParseMGRS(f *testing.F) {
+
+	f.Add("31UDQ4825111932")
+	f.Add("31U DQ 48251 11932")
+	f.Add("18TWL")
+	f.Add("60XVT99")
+	f.Add("")
+
+	f.Fuzz(func(t *testing.T, data string) {
+		position, err := ParseMGRS(data)
+
+		if err != nil {
+			return
+		}
+
+		if validateLongitudeLatitude(position) != nil {
+			t.Fatalf("expected a valid position, got %v", position)
+		}
+	})
+}
+
+// FuzzParseAddress confirms that the address parser never panics, and that
+// every component it returns has a value.
+func FuzzParseAddress(f *testing.F) {
+
+	f.Add("1600 Pennsylvania Ave NW, Washington, DC 20500", "")
+	f.Add("Flat 3, 10 Downing Street, London SW1A 2AA", "GB")
+	f.Add("Unter den Linden 77, 10117 Berlin", "DE")
+	f.Add("〒100-0005 東京都千代田区丸の内1-1-1", "")
+	f.Add("", "")
+
+	f.Fuzz(func(t *testing.T, formatted string, hint string) {
+		for _, component := range ParseAddressComponents(formatted, hint) {
+			if component.Value == "" {
+				t.Fatalf("expected a value for %s", component.Label)
+			}
+		}
+
+		_, _ = ParseAddress(formatted, hint)
+	})
+}
+
+func FuzzNormalizePostalCode(f *testing.F) {
+
+	f.Add("CA", "k1a0b1")
+	f.Add("GB", "sw1a2aa")
+	f.Add("US", "22162 1010")
+	f.Add("LV", "lv 1073")
+	f.Add("NL", "1234–ab")
+	f.Add("", "")
+
+	f.Fuzz(func(t *testing.T, country string, postalCode string) {
+
+		result, err := NormalizePostalCode(country, postalCode)
+
+		if err != nil {
+			return
+		}
+
+		// Canonical postal codes must not change again
+		again, err := NormalizePostalCode(country, result)
+
+		if err != nil {
+			t.Fatalf("canonical postal code %q is not valid: %v", result, err)
+		}
+
+		if again != result {
+			t.Fatalf("expected %q to be canonical, got %q", result, again)
+		}
+	})
+}
+
+// FuzzAddress_Similarity confirms that address comparisons never panic, and that
+// they are symmetric and stay between 0 and 1.
+func FuzzAddress_Similarity(f *testing.F) {
+
+	f.Add("123 Main St., Springfield, IL 62701", "123 Main Street, Suite 4, Springfield, IL")
+	f.Add("Hauptstr. 5, 80331 München", "Hauptstraße 5, 80331 Munchen, Germany")
+	f.Add("〒100-0005 東京都千代田区丸の内1-1-1", "")
+	f.Add("", "")
+
+	f.Fuzz(func(t *testing.T, formatted string, other string) {
+
+		address, otherAddress := Address{Formatted: formatted}, Address{Formatted: other}
+		similarity := address.Similarity(otherAddress)
+
+		if !(similarity >= 0) || (similarity > 1) {
+			t.Fatalf("similarity of %q and %q is out of range: %v", formatted, other, similarity)
+		}
+
+		if reverse := otherAddress.Similarity(address); math.Abs(similarity-reverse) > 1e-9 {
+			t.Fatalf("similarity of %q and %q is not symmetric: %v and %v", formatted, other, similarity, reverse)
+		}
+
+		if address.Equal(otherAddress) != otherAddress.Equal(address) {
+			t.Fatalf("equality of %q and %q is not symmetric", formatted, other)
+		}
+	})
+}
@@ -0,0 +1,155 @@
+package geo
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/benpate/derp"
+)
+
+// Geocoder finds the location of an Address. Implementations read the Address's
+// Formatted value (or its parsed fields, when Formatted is empty) and fill in its
+// coordinates and parsed fields. They return a derp NotFound error when nothing matches.
+type Geocoder interface {
+	Geocode(ctx context.Context, address *Address) error
+}
+
+// ReverseGeocoder finds the Address at a Point. Implementations return a derp
+// NotFound error when there is no Address nearby.
+type ReverseGeocoder interface {
+	ReverseGeocode(ctx context.Context, point Point) (Address, error)
+}
+
+// Geocode fills in the coordinates and parsed fields of this Address using the
+// provided Geocoder. Values that the Geocoder returns replace the existing ones (as
+// in Merge with AddressMergePreferIncoming), and everything else is kept, including
+// the Name, and the Formatted value unless it is empty.
+func (address *Address) Geocode(ctx context.Context, geocoder Geocoder) error {
+
+	const location = "geo.Address.Geocode"
+
+	if geocoder == nil {
+		return derp.Internal(location, "Geocoder must not be nil")
+	}
+
+	if address.GeocodeQuery() == "" {
+		return derp.Validation("Address must have a formatted value or parsed fields to geocode")
+	}
+
+	if err := geocoder.Geocode(ctx, address); err != nil {
+		return derp.Wrap(err, location, "Unable to geocode address", address.GeocodeQuery())
+	}
+
+	return nil
+}
+
+// GeocodeQuery returns the text that Geocoders search for: the Formatted value
+// if there is one, or the parsed fields written on a single line.
+func (address Address) GeocodeQuery() string {
+
+	if formatted := strings.TrimSpace(address.Formatted); formatted != "" {
+		return formatted
+	}
+
+	if !address.HasAddress() {
+		return ""
+	}
+
+	return address.Format(AddressFormatSingleLine)
+}
+
+// applyGeocode copies the values that a Geocoder returned into this Address, using
+// AddressMergePreferIncoming: the geocoded coordinates and address components replace
+// the existing ones, and every value that the Geocoder did not return (such as Street2,
+// PlusCode, or Timezone) is kept. The Name is always kept, and so is the Formatted
+// value unless it is empty.
+func (address *Address) applyGeocode(result Address) {
+
+	if address.Formatted != "" {
+		result.Formatted = ""
+	}
+
+	address.Merge(result, AddressMergePreferIncoming)
+}
+
+// geocodeQuery sends only the GeocodeQuery of an Address to a Geocoder, and returns
+// the values that the Geocoder found: its coordinates and address components. The
+// result holds nothing from the Address itself (not even its Formatted value), so
+// CachedGeocoder and DedupedGeocoder can share it between callers.
+func geocodeQuery(ctx context.Context, geocoder Geocoder, query string) (Address, error) {
+
+	result := Address{Formatted: query}
+
+	if err := geocoder.Geocode(ctx, &result); err != nil {
+		return Address{}, err
+	}
+
+	result.Formatted = ""
+	return result, nil
+}
+
+// geocoderHouseNumberLast lists the countries where the house number is written after the street name
+var geocoderHouseNumberLast = map[string]bool{
+	"AR": true, "AT": true, "BA": true, "BE": true, "BG": true, "BR": true, "CH": true, "CL": true,
+	"CZ": true, "DE": true, "DK": true, "EE": true, "ES": true, "FI": true, "GR": true, "HR": true,
+	"HU": true, "IS": true, "IT": true, "LT": true, "LV": true, "MX": true, "NL": true, "NO": true,
+	"PL": true, "PT": true, "RO": true, "RS": true, "SE": true, "SI": true, "SK": true, "TR": true,
+}
+
+// geocoderStreet combines a house number and a street name in the order used by a country
+func geocoderStreet(country string, houseNumber string, street string) string {
+
+	if (houseNumber == "") || (street == "") {
+		return strings.TrimSpace(houseNumber + street)
+	}
+
+	if geocoderHouseNumberLast[country] {
+		return street + " " + houseNumber
+	}
+
+	return houseNumber + " " + street
+}
+
+// geocoderGet sends a GET request to a geocoding service and decodes its JSON response into result
+func geocoderGet(ctx context.Context, client *http.Client, endpoint string, query url.Values, userAgent string, result any) error {
+
+	const location = "geo.geocoderGet"
+
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint+"?"+query.Encode(), nil)
+
+	if err != nil {
+		return derp.Wrap(err, location, "Unable to create request", endpoint)
+	}
+
+	request.Header.Set("Accept", "application/json")
+
+	if userAgent != "" {
+		request.Header.Set("User-Agent", userAgent)
+	}
+
+	response, err := client.Do(request)
+
+	if err != nil {
+		return derp.Wrap(err, location, "Unable to reach geocoding service", endpoint)
+	}
+
+	defer response.Body.Close()
+
+	// Non-2xx responses keep their status code (and any Retry-After header)
+	if (response.StatusCode < 200) || (response.StatusCode > 299) {
+		return derp.Wrap(derp.NewHTTPError(request, response), location, "Geocoding service returned an error", endpoint)
+	}
+
+	if err := json.NewDecoder(response.Body).Decode(result); err != nil {
+		return derp.Wrap(err, location, "Unable to decode response from geocoding service", endpoint)
+	}
+
+	return nil
+}
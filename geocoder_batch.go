@@ -0,0 +1,53 @@
+package geo
+
+import (
+	"context"
+	"sync"
+
+	"github.com/benpate/derp"
+)
+
+// GeocodeBatch geocodes every Address in a slice (in place) using up to workers
+// concurrent requests. It returns one error for each Address, in the same order
+// as the input, which is nil for the addresses that were geocoded successfully.
+// Addresses that have not started when ctx is canceled report the context's error.
+// To respect a service's limits, wrap the geocoder in a RateLimitedGeocoder.
+func GeocodeBatch(ctx context.Context, geocoder Geocoder, addresses []Address, workers int) []error {
+
+	const location = "geo.GeocodeBatch"
+
+	result := make([]error, len(addresses))
+	indexes := make(chan int)
+
+	var wg sync.WaitGroup
+
+	for range min(max(workers, 1), max(len(addresses), 1)) {
+		wg.Go(func() {
+			for index := range indexes {
+				if err := addresses[index].Geocode(ctx, geocoder); err != nil {
+					result[index] = derp.Wrap(err, location, "Unable to geocode address", index)
+				}
+			}
+		})
+	}
+
+	// Send each address to the workers, until the context is canceled
+	for index := range addresses {
+
+		if ctx.Err() != nil {
+			result[index] = derp.Wrap(ctx.Err(), location, "Canceled before geocoding address", index)
+			continue
+		}
+
+		select {
+		case indexes <- index:
+		case <-ctx.Done():
+			result[index] = derp.Wrap(ctx.Err(), location, "Canceled before geocoding address", index)
+		}
+	}
+
+	close(indexes)
+	wg.Wait()
+
+	return result
+}
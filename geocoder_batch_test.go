@@ -0,0 +1,93 @@
+package geo
+
+import (
+	"context"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/benpate/derp"
+	"github.com/stretchr/testify/require"
+)
+
+// batchGeocoder is a Geocoder that returns a Latitude based on the query, and
+// fails on queries that contain "fail". It records the most concurrent requests.
+type batchGeocoder struct {
+	active  atomic.Int32
+	highest atomic.Int32
+}
+
+func (geocoder *batchGeocoder) Geocode(_ context.Context, address *Address) error {
+
+	active := geocoder.active.Add(1)
+	defer geocoder.active.Add(-1)
+
+	for {
+		highest := geocoder.highest.Load()
+		if (active <= highest) || geocoder.highest.CompareAndSwap(highest, active) {
+			break
+		}
+	}
+
+	time.Sleep(5 * time.Millisecond)
+
+	if strings.Contains(address.Formatted, "fail") {
+		return derp.NotFound("test", "No results", address.Formatted)
+	}
+
+	address.applyGeocode(Address{Latitude: float64(len(address.Formatted)), Longitude: 1})
+	return nil
+}
+
+func TestGeocodeBatch(t *testing.T) {
+
+	geocoder := &batchGeocoder{}
+	addresses := []Address{
+		{Formatted: "a"},
+		{Formatted: "bb"},
+		{Formatted: "fail"},
+		{Formatted: "dddd"},
+		{},
+		{Formatted: "ffffff"},
+		{Formatted: "ggggggg"},
+		{Formatted: "hhhhhhhh"},
+	}
+
+	errs := GeocodeBatch(context.Background(), geocoder, addresses, 3)
+	require.Equal(t, len(addresses), len(errs))
+
+	// Results stay in the same order as the input
+	for index, address := range addresses {
+		switch index {
+		case 2:
+			require.True(t, derp.IsNotFound(errs[index]))
+			require.False(t, address.HasGeocode())
+		case 4:
+			require.True(t, derp.IsValidationError(errs[index]))
+		default:
+			require.Nil(t, errs[index])
+			require.Equal(t, float64(len(address.Formatted)), address.Latitude)
+		}
+	}
+
+	require.LessOrEqual(t, geocoder.highest.Load(), int32(3))
+	require.Greater(t, geocoder.highest.Load(), int32(1))
+}
+
+func TestGeocodeBatch_Empty(t *testing.T) {
+	require.Equal(t, []error{}, GeocodeBatch(context.Background(), &batchGeocoder{}, nil, 4))
+}
+
+func TestGeocodeBatch_Canceled(t *testing.T) {
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	addresses := []Address{{Formatted: "a"}, {Formatted: "b"}}
+	errs := GeocodeBatch(ctx, &batchGeocoder{}, addresses, 0)
+
+	require.NotNil(t, errs[0])
+	require.NotNil(t, errs[1])
+	require.False(t, addresses[0].HasGeocode())
+}
@@ -0,0 +1,159 @@
+package geo
+
+import (
+	"container/list"
+	"context"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/benpate/derp"
+)
+
+// CachedGeocoder is a Geocoder that remembers the results of another Geocoder in
+// memory. Results are keyed by the normalized GeocodeQuery of each Address, so
+// "1600 Pennsylvania Ave., Washington" and "1600 pennsylvania ave washington" share
+// an entry. Entries hold only what the other Geocoder returned (coordinates and address
+// components), so each caller keeps its own Name, Formatted, Street2, PlusCode, and
+// Timezone values. The least recently used entries are removed when the cache is full,
+// and entries expire after a fixed time. Errors are not cached. It is safe for concurrent use.
+type CachedGeocoder struct {
+	geocoder Geocoder
+	capacity int
+	ttl      time.Duration
+	now      func() time.Time
+
+	mutex   sync.Mutex
+	entries map[string]*list.Element
+	order   *list.List // Most recently used entries are at the front
+}
+
+// geocodeCacheEntry is a single result in a CachedGeocoder
+type geocodeCacheEntry struct {
+	key     string
+	address Address
+	expires time.Time
+}
+
+// NewCachedGeocoder returns a CachedGeocoder that holds up to capacity results from
+// geocoder, each for the duration of ttl. A capacity of zero (or less) means that
+// the cache has no size limit, and a ttl of zero (or less) means that results never expire.
+func NewCachedGeocoder(geocoder Geocoder, capacity int, ttl time.Duration) *CachedGeocoder {
+	return &CachedGeocoder{
+		geocoder: geocoder,
+		capacity: capacity,
+		ttl:      ttl,
+		now:      time.Now,
+		entries:  map[string]*list.Element{},
+		order:    list.New(),
+	}
+}
+
+// Geocode implements the Geocoder interface, returning a cached result when there is one
+func (cache *CachedGeocoder) Geocode(ctx context.Context, address *Address) error {
+
+	const location = "geo.CachedGeocoder.Geocode"
+
+	key := geocodeKey(address.GeocodeQuery())
+
+	if result, ok := cache.load(key); ok {
+		address.applyGeocode(result)
+		return nil
+	}
+
+	result, err := geocodeQuery(ctx, cache.geocoder, address.GeocodeQuery())
+
+	if err != nil {
+		return derp.Wrap(err, location, "Unable to geocode address")
+	}
+
+	cache.store(key, result)
+	address.applyGeocode(result)
+	return nil
+}
+
+// Len returns the number of results in the cache, including any that have expired
+// but have not been removed yet
+func (cache *CachedGeocoder) Len() int {
+
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	return cache.order.Len()
+}
+
+// Purge removes every result from the cache
+func (cache *CachedGeocoder) Purge() {
+
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	cache.entries = map[string]*list.Element{}
+	cache.order.Init()
+}
+
+// load returns the cached result for a key, if it has not expired
+func (cache *CachedGeocoder) load(key string) (Address, bool) {
+
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	element, ok := cache.entries[key]
+
+	if !ok {
+		return Address{}, false
+	}
+
+	entry := element.Value.(*geocodeCacheEntry)
+
+	if !entry.expires.IsZero() && !cache.now().Before(entry.expires) {
+		cache.order.Remove(element)
+		delete(cache.entries, key)
+		return Address{}, false
+	}
+
+	cache.order.MoveToFront(element)
+	return entry.address, true
+}
+
+// store adds a result to the cache, removing the least recently used results if the cache is full
+func (cache *CachedGeocoder) store(key string, address Address) {
+
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	entry := &geocodeCacheEntry{
+		key:     key,
+		address: address,
+	}
+
+	if cache.ttl > 0 {
+		entry.expires = cache.now().Add(cache.ttl)
+	}
+
+	if element, ok := cache.entries[key]; ok {
+		element.Value = entry
+		cache.order.MoveToFront(element)
+		return
+	}
+
+	cache.entries[key] = cache.order.PushFront(entry)
+
+	for (cache.capacity > 0) && (cache.order.Len() > cache.capacity) {
+		oldest := cache.order.Back()
+		cache.order.Remove(oldest)
+		delete(cache.entries, oldest.Value.(*geocodeCacheEntry).key)
+	}
+}
+
+// geocodeKey normalizes a geocoding query for use as a cache key, ignoring case,
+// punctuation, and whitespace
+func geocodeKey(query string) string {
+
+	fields := strings.FieldsFunc(strings.ToLower(query), func(r rune) bool {
+		return unicode.IsSpace(r) || unicode.IsPunct(r)
+	})
+
+	return strings.Join(fields, " ")
+}
@@ -0,0 +1,141 @@
+package geo
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/benpate/derp"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCachedGeocoder(t *testing.T) {
+
+	inner := &testGeocoder{result: Address{Locality: "Washington", Country: "US", Latitude: 38.9, Longitude: -77}}
+	cache := NewCachedGeocoder(inner, 10, time.Hour)
+
+	first := Address{Name: "First", Formatted: "1600 Pennsylvania Ave., Washington"}
+	require.Nil(t, first.Geocode(context.Background(), cache))
+	require.Equal(t, 1, inner.count())
+	require.Equal(t, 38.9, first.Latitude)
+
+	// Queries that normalize to the same key use the cached result
+	second := Address{Name: "Second", Formatted: "  1600 PENNSYLVANIA AVE  washington "}
+	require.Nil(t, second.Geocode(context.Background(), cache))
+	require.Equal(t, 1, inner.count())
+	require.Equal(t, "Second", second.Name)
+	require.Equal(t, "  1600 PENNSYLVANIA AVE  washington ", second.Formatted)
+	require.Equal(t, "Washington", second.Locality)
+	require.Equal(t, 38.9, second.Latitude)
+
+	// Different queries do not
+	third := Address{Formatted: "10 Downing Street, London"}
+	require.Nil(t, third.Geocode(context.Background(), cache))
+	require.Equal(t, 2, inner.count())
+	require.Equal(t, 2, cache.Len())
+
+	cache.Purge()
+	require.Equal(t, 0, cache.Len())
+}
+
+func TestCachedGeocoder_CallerValues(t *testing.T) {
+
+	inner := &testGeocoder{result: Address{Street1: "221B Baker Street", Locality: "London", Country: "GB", Latitude: 51.52, Longitude: -0.16}}
+	cache := NewCachedGeocoder(inner, 10, time.Hour)
+
+	// Two callers share a query, but not their other values
+	first := Address{Formatted: "221B Baker Street, London", Street2: "Flat 1", PlusCode: "9C3XGV3C+W2", Timezone: "Europe/London"}
+	second := Address{Formatted: "221B Baker Street, London", Street2: "Flat 2"}
+
+	require.Nil(t, first.Geocode(context.Background(), cache))
+	require.Nil(t, second.Geocode(context.Background(), cache))
+	require.Equal(t, 1, inner.count())
+
+	require.Equal(t, "Flat 1", first.Street2)
+	require.Equal(t, "9C3XGV3C+W2", first.PlusCode)
+	require.Equal(t, "Europe/London", first.Timezone)
+
+	require.Equal(t, Address{
+		Formatted: "221B Baker Street, London",
+		Street1:   "221B Baker Street",
+		Street2:   "Flat 2",
+		Locality:  "London",
+		Country:   "GB",
+		Latitude:  51.52,
+		Longitude: -0.16,
+	}, second)
+
+	// Callers without a Formatted value do not receive another caller's
+	third := Address{Street1: "221B Baker Street", Locality: "London"}
+	require.Nil(t, third.Geocode(context.Background(), cache))
+	require.Equal(t, "", third.Formatted)
+	require.Equal(t, 51.52, third.Latitude)
+}
+
+func TestCachedGeocoder_Expires(t *testing.T) {
+
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	inner := &testGeocoder{result: Address{Latitude: 1, Longitude: 2}}
+
+	cache := NewCachedGeocoder(inner, 0, time.Minute)
+	cache.now = func() time.Time { return now }
+
+	address := Address{Formatted: "Somewhere"}
+	require.Nil(t, address.Geocode(context.Background(), cache))
+	require.Nil(t, address.Geocode(context.Background(), cache))
+	require.Equal(t, 1, inner.count())
+
+	now = now.Add(59 * time.Second)
+	require.Nil(t, address.Geocode(context.Background(), cache))
+	require.Equal(t, 1, inner.count())
+
+	now = now.Add(time.Second)
+	require.Nil(t, address.Geocode(context.Background(), cache))
+	require.Equal(t, 2, inner.count())
+}
+
+func TestCachedGeocoder_LeastRecentlyUsed(t *testing.T) {
+
+	inner := &testGeocoder{result: Address{Latitude: 1, Longitude: 2}}
+	cache := NewCachedGeocoder(inner, 2, 0)
+
+	geocode := func(formatted string) {
+		address := Address{Formatted: formatted}
+		require.Nil(t, address.Geocode(context.Background(), cache))
+	}
+
+	geocode("a")
+	geocode("b")
+	geocode("a") // "b" is now the least recently used
+	geocode("c") // which removes "b"
+	require.Equal(t, 3, inner.count())
+	require.Equal(t, 2, cache.Len())
+
+	geocode("a")
+	geocode("c")
+	require.Equal(t, 3, inner.count())
+
+	geocode("b")
+	require.Equal(t, 4, inner.count())
+}
+
+func TestCachedGeocoder_Errors(t *testing.T) {
+
+	inner := &testGeocoder{err: derp.NotFound("test", "No results")}
+	cache := NewCachedGeocoder(inner, 10, time.Hour)
+
+	address := Address{Formatted: "Nowhere"}
+	require.True(t, derp.IsNotFound(address.Geocode(context.Background(), cache)))
+	require.True(t, derp.IsNotFound(address.Geocode(context.Background(), cache)))
+
+	// Errors are not cached
+	require.Equal(t, 2, inner.count())
+	require.Equal(t, 0, cache.Len())
+	require.Equal(t, Address{Formatted: "Nowhere"}, address)
+}
+
+func TestGeocodeKey(t *testing.T) {
+	require.Equal(t, "1600 pennsylvania ave washington", geocodeKey("1600 Pennsylvania Ave., Washington"))
+	require.Equal(t, "東京都千代田区丸の内1 1 1", geocodeKey("東京都千代田区丸の内1-1-1"))
+	require.Equal(t, "", geocodeKey(" ,. "))
+}
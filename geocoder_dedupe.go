@@ -0,0 +1,122 @@
+package geo
+
+import (
+	"context"
+	"sync"
+
+	"github.com/benpate/derp"
+)
+
+// DedupedGeocoder is a Geocoder that combines concurrent requests for the same
+// address (by normalized GeocodeQuery) into a single request to another Geocoder,
+// and shares the result with every caller. It is safe for concurrent use.
+type DedupedGeocoder struct {
+	geocoder Geocoder
+
+	mutex sync.Mutex
+	calls map[string]*geocodeCall
+}
+
+// geocodeCall is a request that is in progress, which other callers can wait for
+type geocodeCall struct {
+	done    chan struct{}
+	cancel  context.CancelFunc
+	waiters int // Callers still waiting for this request (guarded by DedupedGeocoder.mutex)
+	address Address
+	err     error
+}
+
+// NewDedupedGeocoder returns a DedupedGeocoder that sends requests to geocoder
+func NewDedupedGeocoder(geocoder Geocoder) *DedupedGeocoder {
+	return &DedupedGeocoder{
+		geocoder: geocoder,
+		calls:    map[string]*geocodeCall{},
+	}
+}
+
+// Geocode implements the Geocoder interface. If a request for the same address is
+// already in progress, then this waits for its result (or for ctx to be canceled)
+// instead of making another request. Callers share one result, including its error,
+// but each caller keeps its own values for everything that the other Geocoder does
+// not return.
+//
+// The shared request does not belong to any one caller: it keeps the first caller's
+// context values, but it is only canceled once every waiting caller has given up.
+func (deduped *DedupedGeocoder) Geocode(ctx context.Context, address *Address) error {
+
+	const location = "geo.DedupedGeocoder.Geocode"
+
+	query := address.GeocodeQuery()
+	key := geocodeKey(query)
+
+	deduped.mutex.Lock()
+
+	// Start a new request, unless one is already in progress
+	call, ok := deduped.calls[key]
+
+	if !ok {
+		callContext, cancel := context.WithCancel(context.WithoutCancel(ctx))
+		call = &geocodeCall{
+			done:   make(chan struct{}),
+			cancel: cancel,
+		}
+
+		deduped.calls[key] = call
+		go deduped.run(callContext, key, call, query)
+	}
+
+	call.waiters++
+	deduped.mutex.Unlock()
+
+	select {
+
+	case <-call.done:
+		if call.err != nil {
+			return derp.Wrap(call.err, location, "Unable to geocode address")
+		}
+
+		address.applyGeocode(call.address)
+		return nil
+
+	case <-ctx.Done():
+		deduped.leave(key, call)
+		return derp.Wrap(ctx.Err(), location, "Canceled while waiting for geocoding result")
+	}
+}
+
+// run makes a shared request, and then wakes every caller that is waiting for it
+func (deduped *DedupedGeocoder) run(ctx context.Context, key string, call *geocodeCall, query string) {
+
+	call.address, call.err = geocodeQuery(ctx, deduped.geocoder, query)
+
+	deduped.mutex.Lock()
+
+	if deduped.calls[key] == call {
+		delete(deduped.calls, key)
+	}
+
+	deduped.mutex.Unlock()
+
+	call.cancel()
+	close(call.done)
+}
+
+// leave removes a caller that has stopped waiting for a request. The request is
+// canceled when no callers are left, so that later callers start a new one.
+func (deduped *DedupedGeocoder) leave(key string, call *geocodeCall) {
+
+	deduped.mutex.Lock()
+	defer deduped.mutex.Unlock()
+
+	call.waiters--
+
+	if call.waiters > 0 {
+		return
+	}
+
+	call.cancel()
+
+	if deduped.calls[key] == call {
+		delete(deduped.calls, key)
+	}
+}
@@ -0,0 +1,176 @@
+package geo
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/benpate/derp"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDedupedGeocoder(t *testing.T) {
+
+	inner := &testGeocoder{
+		result: Address{Locality: "London", Latitude: 51.5, Longitude: -0.12},
+		block:  make(chan struct{}),
+	}
+
+	deduped := NewDedupedGeocoder(inner)
+	addresses := []Address{
+		{Name: "A", Formatted: "10 Downing Street, London", Street2: "Flat 1"},
+		{Name: "B", Formatted: "10 downing street london"},
+		{Name: "C", Formatted: "10 DOWNING STREET, LONDON", Street2: "Flat 3"},
+	}
+
+	var wg sync.WaitGroup
+	errs := make([]error, len(addresses))
+
+	for index := range addresses {
+		wg.Go(func() {
+			errs[index] = addresses[index].Geocode(context.Background(), deduped)
+		})
+	}
+
+	// Wait for the first request to arrive, and give the others time to join it
+	require.Eventually(t, func() bool { return inner.count() > 0 }, time.Second, time.Millisecond)
+	time.Sleep(20 * time.Millisecond)
+	close(inner.block)
+	wg.Wait()
+
+	require.Equal(t, 1, inner.count())
+
+	for index, address := range addresses {
+		require.Nil(t, errs[index])
+		require.Equal(t, "London", address.Locality)
+		require.Equal(t, 51.5, address.Latitude)
+	}
+
+	// Each caller keeps its own Name, Formatted, and Street2 values
+	require.Equal(t, "B", addresses[1].Name)
+	require.Equal(t, "10 downing street london", addresses[1].Formatted)
+	require.Equal(t, []string{"Flat 1", "", "Flat 3"}, []string{addresses[0].Street2, addresses[1].Street2, addresses[2].Street2})
+
+	// Later requests are not deduplicated
+	address := Address{Formatted: "10 Downing Street, London"}
+	require.Nil(t, address.Geocode(context.Background(), deduped))
+	require.Equal(t, 2, inner.count())
+}
+
+func TestDedupedGeocoder_SharedError(t *testing.T) {
+
+	inner := &testGeocoder{
+		err:   derp.NotFound("test", "No results"),
+		block: make(chan struct{}),
+	}
+
+	deduped := NewDedupedGeocoder(inner)
+
+	var wg sync.WaitGroup
+	errs := make([]error, 2)
+
+	for index := range errs {
+		wg.Go(func() {
+			address := Address{Formatted: "Nowhere"}
+			errs[index] = address.Geocode(context.Background(), deduped)
+		})
+	}
+
+	require.Eventually(t, func() bool { return inner.count() > 0 }, time.Second, time.Millisecond)
+	time.Sleep(20 * time.Millisecond)
+	close(inner.block)
+	wg.Wait()
+
+	require.Equal(t, 1, inner.count())
+	require.True(t, derp.IsNotFound(errs[0]))
+	require.True(t, derp.IsNotFound(errs[1]))
+}
+
+func TestDedupedGeocoder_Canceled(t *testing.T) {
+
+	inner := &testGeocoder{
+		result: Address{Latitude: 1, Longitude: 2},
+		block:  make(chan struct{}),
+	}
+
+	deduped := NewDedupedGeocoder(inner)
+
+	go func() {
+		address := Address{Formatted: "Somewhere"}
+		_ = address.Geocode(context.Background(), deduped)
+	}()
+
+	require.Eventually(t, func() bool { return inner.count() > 0 }, time.Second, time.Millisecond)
+
+	// Callers that wait can give up without affecting the request in progress
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	address := Address{Formatted: "Somewhere"}
+	require.NotNil(t, address.Geocode(ctx, deduped))
+	require.False(t, address.HasGeocode())
+
+	close(inner.block)
+}
+
+func TestDedupedGeocoder_FirstCallerCanceled(t *testing.T) {
+
+	inner := &testGeocoder{
+		result: Address{Latitude: 1, Longitude: 2},
+		block:  make(chan struct{}),
+	}
+
+	deduped := NewDedupedGeocoder(inner)
+
+	// The first caller starts the request, and then gives up
+	ctx, cancel := context.WithCancel(context.Background())
+	first := make(chan error)
+
+	go func() {
+		address := Address{Formatted: "Somewhere"}
+		first <- address.Geocode(ctx, deduped)
+	}()
+
+	require.Eventually(t, func() bool { return inner.count() > 0 }, time.Second, time.Millisecond)
+
+	second := make(chan error)
+	address := Address{Formatted: "Somewhere"}
+
+	go func() {
+		second <- address.Geocode(context.Background(), deduped)
+	}()
+
+	// Give the second caller time to join the request
+	time.Sleep(20 * time.Millisecond)
+	cancel()
+	require.ErrorIs(t, <-first, context.Canceled)
+
+	// The request continues for the caller that is still waiting
+	close(inner.block)
+	require.Nil(t, <-second)
+	require.Equal(t, 1, inner.count())
+	require.Equal(t, 1.0, address.Latitude)
+}
+
+func TestDedupedGeocoder_EveryCallerCanceled(t *testing.T) {
+
+	inner := &testGeocoder{
+		result: Address{Latitude: 1, Longitude: 2},
+		block:  make(chan struct{}),
+	}
+
+	deduped := NewDedupedGeocoder(inner)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	address := Address{Formatted: "Somewhere"}
+	require.NotNil(t, address.Geocode(ctx, deduped))
+
+	// Once nobody is waiting, the request is canceled, and later callers start a new one
+	close(inner.block)
+	require.Nil(t, address.Geocode(context.Background(), deduped))
+	require.Equal(t, 2, inner.count())
+	require.True(t, address.HasGeocode())
+}
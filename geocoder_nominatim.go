@@ -0,0 +1,167 @@
+package geo
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/benpate/derp"
+)
+
+// NominatimEndpoint is the public OpenStreetMap Nominatim server. Its usage policy
+// (https://operations.osmfoundation.org/policies/nominatim/) requires a UserAgent
+// that identifies your application, and allows at most one request per second.
+const NominatimEndpoint = "https://nominatim.openstreetmap.org"
+
+// NominatimGeocoder is a Geocoder and ReverseGeocoder for the Nominatim API
+// https://nominatim.org/release-docs/latest/api/Overview/
+type NominatimGeocoder struct {
+	Endpoint  string       // Base URL of the server, such as NominatimEndpoint
+	UserAgent string       // Identifies your application to the server
+	Email     string       // Optional contact address, for servers that ask for one
+	Language  string       // Optional language for results, such as "en" or "de,en"
+	Client    *http.Client // Optional HTTP client (http.DefaultClient when nil)
+}
+
+// NewNominatimGeocoder returns a NominatimGeocoder for the server at endpoint
+func NewNominatimGeocoder(endpoint string, userAgent string) NominatimGeocoder {
+	return NominatimGeocoder{
+		Endpoint:  endpoint,
+		UserAgent: userAgent,
+	}
+}
+
+// nominatimPlace is a single result from the Nominatim API, in "jsonv2" format
+type nominatimPlace struct {
+	Latitude    string            `json:"lat"`
+	Longitude   string            `json:"lon"`
+	DisplayName string            `json:"display_name"`
+	Address     map[string]string `json:"address"`
+	Error       string            `json:"error"`
+}
+
+// Geocode implements the Geocoder interface, using Nominatim's /search endpoint
+func (geocoder NominatimGeocoder) Geocode(ctx context.Context, address *Address) error {
+
+	const location = "geo.NominatimGeocoder.Geocode"
+
+	query := geocoder.query()
+	query.Set("q", address.GeocodeQuery())
+	query.Set("limit", "1")
+
+	var places []nominatimPlace
+
+	if err := geocoderGet(ctx, geocoder.Client, geocoder.url("/search"), query, geocoder.UserAgent, &places); err != nil {
+		return derp.Wrap(err, location, "Unable to search Nominatim")
+	}
+
+	if len(places) == 0 {
+		return derp.NotFound(location, "No results for address", address.GeocodeQuery())
+	}
+
+	result, err := places[0].address()
+
+	if err != nil {
+		return derp.Wrap(err, location, "Unable to read Nominatim result")
+	}
+
+	address.applyGeocode(result)
+	return nil
+}
+
+// ReverseGeocode implements the ReverseGeocoder interface, using Nominatim's /reverse endpoint
+func (geocoder NominatimGeocoder) ReverseGeocode(ctx context.Context, point Point) (Address, error) {
+
+	const location = "geo.NominatimGeocoder.ReverseGeocode"
+
+	query := geocoder.query()
+	query.Set("lat", strconv.FormatFloat(point.Latitude, 'f', -1, 64))
+	query.Set("lon", strconv.FormatFloat(point.Longitude, 'f', -1, 64))
+
+	var place nominatimPlace
+
+	if err := geocoderGet(ctx, geocoder.Client, geocoder.url("/reverse"), query, geocoder.UserAgent, &place); err != nil {
+		return Address{}, derp.Wrap(err, location, "Unable to reverse geocode with Nominatim")
+	}
+
+	// Nominatim reports "Unable to geocode" (with a 200 status) when nothing is nearby
+	if place.Error != "" {
+		return Address{}, derp.NotFound(location, "No address at point", point.LatLon(), place.Error)
+	}
+
+	result, err := place.address()
+
+	if err != nil {
+		return Address{}, derp.Wrap(err, location, "Unable to read Nominatim result")
+	}
+
+	return result, nil
+}
+
+// query returns the query parameters that are sent with every request
+func (geocoder NominatimGeocoder) query() url.Values {
+
+	result := url.Values{}
+	result.Set("format", "jsonv2")
+	result.Set("addressdetails", "1")
+
+	if geocoder.Email != "" {
+		result.Set("email", geocoder.Email)
+	}
+
+	if geocoder.Language != "" {
+		result.Set("accept-language", geocoder.Language)
+	}
+
+	return result
+}
+
+// url returns the full URL of an API endpoint
+func (geocoder NominatimGeocoder) url(path string) string {
+	return strings.TrimSuffix(geocoder.Endpoint, "/") + path
+}
+
+// address converts a Nominatim result into an Address
+func (place nominatimPlace) address() (Address, error) {
+
+	const location = "geo.nominatimPlace.address"
+
+	latitude, err := strconv.ParseFloat(place.Latitude, 64)
+
+	if err != nil {
+		return Address{}, derp.Wrap(err, location, "Invalid latitude", place.Latitude)
+	}
+
+	longitude, err := strconv.ParseFloat(place.Longitude, 64)
+
+	if err != nil {
+		return Address{}, derp.Wrap(err, location, "Invalid longitude", place.Longitude)
+	}
+
+	country := strings.ToUpper(place.Address["country_code"])
+
+	return Address{
+		Formatted:  place.DisplayName,
+		Street1:    geocoderStreet(country, place.Address["house_number"], place.Address["road"]),
+		Locality:   place.first("city", "town", "village", "hamlet", "municipality"),
+		Region:     place.first("state", "province", "region"),
+		PostalCode: place.Address["postcode"],
+		Country:    country,
+		Latitude:   latitude,
+		Longitude:  longitude,
+	}, nil
+}
+
+// first returns the first of the named address parts that has a value
+func (place nominatimPlace) first(names ...string) string {
+
+	for _, name := range names {
+		if value := place.Address[name]; value != "" {
+			return value
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,142 @@
+package geo
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/benpate/derp"
+	"github.com/stretchr/testify/require"
+)
+
+// newNominatimServer returns a local stand-in for a Nominatim server
+func newNominatimServer(t *testing.T) *httptest.Server {
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+		query := r.URL.Query()
+		require.Equal(t, "test-agent/1.0", r.Header.Get("User-Agent"))
+		require.Equal(t, "jsonv2", query.Get("format"))
+		require.Equal(t, "1", query.Get("addressdetails"))
+		require.Equal(t, "de", query.Get("accept-language"))
+
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.URL.Path {
+
+		case "/search":
+			require.Equal(t, "1", query.Get("limit"))
+
+			if query.Get("q") != "Unter den Linden 77, Berlin" {
+				_, _ = w.Write([]byte(`[]`))
+				return
+			}
+
+			_, _ = w.Write([]byte(`[{
+				"place_id": 1,
+				"lat": "52.5163",
+				"lon": "13.3809",
+				"display_name": "Hotel Adlon, 77, Unter den Linden, Mitte, Berlin, 10117, Deutschland",
+				"address": {
+					"tourism": "Hotel Adlon",
+					"house_number": "77",
+					"road": "Unter den Linden",
+					"suburb": "Mitte",
+					"city": "Berlin",
+					"state": "Berlin",
+					"ISO3166-2-lvl4": "DE-BE",
+					"postcode": "10117",
+					"country": "Deutschland",
+					"country_code": "de"
+				}
+			}]`))
+
+		case "/reverse":
+			if query.Get("lat") != "45.4215" || query.Get("lon") != "-75.6972" {
+				_, _ = w.Write([]byte(`{"error": "Unable to geocode"}`))
+				return
+			}
+
+			_, _ = w.Write([]byte(`{
+				"lat": "45.42153",
+				"lon": "-75.69719",
+				"display_name": "111, Wellington Street, Ottawa, Ontario, K1A 0A9, Canada",
+				"address": {
+					"house_number": "111",
+					"road": "Wellington Street",
+					"town": "Ottawa",
+					"state": "Ontario",
+					"postcode": "K1A 0A9",
+					"country": "Canada",
+					"country_code": "ca"
+				}
+			}`))
+
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+func TestNominatimGeocoder_Geocode(t *testing.T) {
+
+	server := newNominatimServer(t)
+	defer server.Close()
+
+	geocoder := NewNominatimGeocoder(server.URL+"/", "test-agent/1.0")
+	geocoder.Language = "de"
+
+	address := Address{Formatted: "Unter den Linden 77, Berlin"}
+	require.Nil(t, address.Geocode(context.Background(), geocoder))
+
+	require.Equal(t, Address{
+		Formatted:  "Unter den Linden 77, Berlin",
+		Street1:    "Unter den Linden 77",
+		Locality:   "Berlin",
+		Region:     "Berlin",
+		PostalCode: "10117",
+		Country:    "DE",
+		Latitude:   52.5163,
+		Longitude:  13.3809,
+	}, address)
+}
+
+func TestNominatimGeocoder_Geocode_NotFound(t *testing.T) {
+
+	server := newNominatimServer(t)
+	defer server.Close()
+
+	geocoder := NewNominatimGeocoder(server.URL, "test-agent/1.0")
+	geocoder.Language = "de"
+
+	address := Address{Formatted: "Nowhere at all"}
+	err := address.Geocode(context.Background(), geocoder)
+	require.True(t, derp.IsNotFound(err))
+}
+
+func TestNominatimGeocoder_ReverseGeocode(t *testing.T) {
+
+	server := newNominatimServer(t)
+	defer server.Close()
+
+	geocoder := NewNominatimGeocoder(server.URL, "test-agent/1.0")
+	geocoder.Language = "de"
+
+	address, err := geocoder.ReverseGeocode(context.Background(), NewPoint(-75.6972, 45.4215))
+	require.Nil(t, err)
+	require.Equal(t, Address{
+		Formatted:  "111, Wellington Street, Ottawa, Ontario, K1A 0A9, Canada",
+		Street1:    "111 Wellington Street",
+		Locality:   "Ottawa",
+		Region:     "Ontario",
+		PostalCode: "K1A 0A9",
+		Country:    "CA",
+		Latitude:   45.42153,
+		Longitude:  -75.69719,
+	}, address)
+
+	// Points in the middle of the ocean have no address
+	_, err = geocoder.ReverseGeocode(context.Background(), NewPoint(-30, 0))
+	require.True(t, derp.IsNotFound(err))
+}
@@ -0,0 +1,137 @@
+package geo
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/benpate/derp"
+)
+
+// PeliasGeocoder is a Geocoder and ReverseGeocoder for Pelias-compatible APIs,
+// such as a self-hosted Pelias server or geocode.earth
+// https://github.com/pelias/documentation
+type PeliasGeocoder struct {
+	Endpoint  string       // Base URL of the server, such as "https://api.geocode.earth"
+	APIKey    string       // Optional API key, for hosted services that require one
+	UserAgent string       // Optional value for the User-Agent header
+	Language  string       // Optional language for results, such as "en"
+	Client    *http.Client // Optional HTTP client (http.DefaultClient when nil)
+}
+
+// NewPeliasGeocoder returns a PeliasGeocoder for the server at endpoint
+func NewPeliasGeocoder(endpoint string, apiKey string) PeliasGeocoder {
+	return PeliasGeocoder{
+		Endpoint: endpoint,
+		APIKey:   apiKey,
+	}
+}
+
+// peliasResponse is the GeoJSON FeatureCollection returned by every Pelias endpoint
+type peliasResponse struct {
+	Features []Feature `json:"features"`
+}
+
+// Geocode implements the Geocoder interface, using Pelias's /v1/search endpoint
+func (geocoder PeliasGeocoder) Geocode(ctx context.Context, address *Address) error {
+
+	const location = "geo.PeliasGeocoder.Geocode"
+
+	query := geocoder.query()
+	query.Set("text", address.GeocodeQuery())
+
+	result, err := geocoder.get(ctx, "/v1/search", query)
+
+	if err != nil {
+		return derp.Wrap(err, location, "Unable to search Pelias", address.GeocodeQuery())
+	}
+
+	address.applyGeocode(result)
+	return nil
+}
+
+// ReverseGeocode implements the ReverseGeocoder interface, using Pelias's /v1/reverse endpoint
+func (geocoder PeliasGeocoder) ReverseGeocode(ctx context.Context, point Point) (Address, error) {
+
+	const location = "geo.PeliasGeocoder.ReverseGeocode"
+
+	query := geocoder.query()
+	query.Set("point.lat", strconv.FormatFloat(point.Latitude, 'f', -1, 64))
+	query.Set("point.lon", strconv.FormatFloat(point.Longitude, 'f', -1, 64))
+
+	result, err := geocoder.get(ctx, "/v1/reverse", query)
+
+	if err != nil {
+		return Address{}, derp.Wrap(err, location, "Unable to reverse geocode with Pelias", point.LatLon())
+	}
+
+	return result, nil
+}
+
+// query returns the query parameters that are sent with every request
+func (geocoder PeliasGeocoder) query() url.Values {
+
+	result := url.Values{}
+	result.Set("size", "1")
+
+	if geocoder.APIKey != "" {
+		result.Set("api_key", geocoder.APIKey)
+	}
+
+	if geocoder.Language != "" {
+		result.Set("lang", geocoder.Language)
+	}
+
+	return result
+}
+
+// get sends a request to a Pelias endpoint, and returns the first result as an Address
+func (geocoder PeliasGeocoder) get(ctx context.Context, path string, query url.Values) (Address, error) {
+
+	const location = "geo.PeliasGeocoder.get"
+
+	var response peliasResponse
+	endpoint := strings.TrimSuffix(geocoder.Endpoint, "/") + path
+
+	if err := geocoderGet(ctx, geocoder.Client, endpoint, query, geocoder.UserAgent, &response); err != nil {
+		return Address{}, derp.Wrap(err, location, "Unable to query Pelias")
+	}
+
+	if len(response.Features) == 0 {
+		return Address{}, derp.NotFound(location, "No results")
+	}
+
+	feature := response.Features[0]
+	point, ok := feature.Geometry.(Point)
+
+	if !ok {
+		return Address{}, derp.Internal(location, "Pelias result must have a Point geometry", feature.Geometry)
+	}
+
+	properties := feature.Properties
+	country := strings.ToUpper(properties.GetString("country_code"))
+
+	// Older servers only return the alpha-3 code
+	if match, ok := LookupCountry(properties.GetString("country_a")); (country == "") && ok {
+		country = match.Alpha2
+	}
+
+	locality := properties.GetString("locality")
+
+	if locality == "" {
+		locality = properties.GetString("localadmin")
+	}
+
+	return Address{
+		Formatted:  properties.GetString("label"),
+		Street1:    geocoderStreet(country, properties.GetString("housenumber"), properties.GetString("street")),
+		Locality:   locality,
+		Region:     properties.GetString("region"),
+		PostalCode: properties.GetString("postalcode"),
+		Country:    country,
+		Latitude:   point.Latitude,
+		Longitude:  point.Longitude,
+	}, nil
+}
@@ -0,0 +1,138 @@
+package geo
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/benpate/derp"
+	"github.com/stretchr/testify/require"
+)
+
+// newPeliasServer returns a local stand-in for a Pelias server
+func newPeliasServer(t *testing.T) *httptest.Server {
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+		query := r.URL.Query()
+		require.Equal(t, "secret", query.Get("api_key"))
+		require.Equal(t, "1", query.Get("size"))
+
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.URL.Path {
+
+		case "/v1/search":
+			if query.Get("text") != "1600 Pennsylvania Ave NW, Washington DC" {
+				_, _ = w.Write([]byte(`{"type": "FeatureCollection", "features": []}`))
+				return
+			}
+
+			_, _ = w.Write([]byte(`{
+				"geocoding": {"version": "0.2"},
+				"type": "FeatureCollection",
+				"features": [{
+					"type": "Feature",
+					"geometry": {"type": "Point", "coordinates": [-77.036547, 38.897675]},
+					"properties": {
+						"layer": "address",
+						"name": "1600 Pennsylvania Avenue NW",
+						"housenumber": "1600",
+						"street": "Pennsylvania Avenue NW",
+						"postalcode": "20500",
+						"confidence": 1,
+						"country": "United States",
+						"country_a": "USA",
+						"region": "District of Columbia",
+						"region_a": "DC",
+						"locality": "Washington",
+						"label": "1600 Pennsylvania Avenue NW, Washington, DC, USA"
+					},
+					"bbox": [-77.04, 38.89, -77.03, 38.9]
+				}],
+				"bbox": [-77.04, 38.89, -77.03, 38.9]
+			}`))
+
+		case "/v1/reverse":
+			_, _ = w.Write([]byte(`{
+				"type": "FeatureCollection",
+				"features": [{
+					"type": "Feature",
+					"geometry": {"type": "Point", "coordinates": [2.2945, 48.8584]},
+					"properties": {
+						"housenumber": "5",
+						"street": "Avenue Anatole France",
+						"postalcode": "75007",
+						"country_code": "fr",
+						"region": "Paris",
+						"localadmin": "Paris",
+						"label": "5 Avenue Anatole France, Paris, France"
+					}
+				}]
+			}`))
+
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"geocoding": {"errors": ["invalid path"]}}`))
+		}
+	}))
+}
+
+func TestPeliasGeocoder_Geocode(t *testing.T) {
+
+	server := newPeliasServer(t)
+	defer server.Close()
+
+	geocoder := NewPeliasGeocoder(server.URL, "secret")
+
+	address := Address{Formatted: "1600 Pennsylvania Ave NW, Washington DC"}
+	require.Nil(t, address.Geocode(context.Background(), geocoder))
+
+	require.Equal(t, Address{
+		Formatted:  "1600 Pennsylvania Ave NW, Washington DC",
+		Street1:    "1600 Pennsylvania Avenue NW",
+		Locality:   "Washington",
+		Region:     "District of Columbia",
+		PostalCode: "20500",
+		Country:    "US",
+		Latitude:   38.897675,
+		Longitude:  -77.036547,
+	}, address)
+
+	// Unknown addresses are not found
+	address = Address{Formatted: "Nowhere at all"}
+	require.True(t, derp.IsNotFound(address.Geocode(context.Background(), geocoder)))
+}
+
+func TestPeliasGeocoder_ReverseGeocode(t *testing.T) {
+
+	server := newPeliasServer(t)
+	defer server.Close()
+
+	geocoder := NewPeliasGeocoder(server.URL, "secret")
+
+	address, err := geocoder.ReverseGeocode(context.Background(), NewPoint(2.2945, 48.8584))
+	require.Nil(t, err)
+	require.Equal(t, Address{
+		Formatted:  "5 Avenue Anatole France, Paris, France",
+		Street1:    "5 Avenue Anatole France",
+		Locality:   "Paris",
+		Region:     "Paris",
+		PostalCode: "75007",
+		Country:    "FR",
+		Latitude:   48.8584,
+		Longitude:  2.2945,
+	}, address)
+}
+
+func TestPeliasGeocoder_ServerError(t *testing.T) {
+
+	server := newPeliasServer(t)
+	defer server.Close()
+
+	geocoder := NewPeliasGeocoder(server.URL+"/wrong", "secret")
+
+	_, err := geocoder.ReverseGeocode(context.Background(), NewPoint(0, 0))
+	require.True(t, derp.IsBadRequest(err))
+}
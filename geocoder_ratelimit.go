@@ -0,0 +1,134 @@
+package geo
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/benpate/derp"
+)
+
+// RateLimiter is a token bucket that limits how often requests are made, such as
+// the one request per second that the public Nominatim server allows. One
+// RateLimiter can be shared by several Geocoders (or ReverseGeocoders) that use
+// the same service. It is safe for concurrent use.
+type RateLimiter struct {
+	interval time.Duration // Time to add one token to the bucket
+	burst    float64       // Size of the bucket
+	now      func() time.Time
+
+	mutex   sync.Mutex
+	tokens  float64   // Tokens in the bucket at the time of the last update (negative when requests are waiting)
+	updated time.Time // Time of the last update
+}
+
+// NewRateLimiter returns a RateLimiter that allows perSecond requests each second,
+// with bursts of up to burst requests at once. The bucket starts full. A rate of
+// zero (or less) does not limit requests at all.
+func NewRateLimiter(perSecond float64, burst int) *RateLimiter {
+
+	result := &RateLimiter{
+		burst:  float64(max(burst, 1)),
+		now:    time.Now,
+		tokens: float64(max(burst, 1)),
+	}
+
+	if perSecond > 0 {
+		result.interval = time.Duration(float64(time.Second) / perSecond)
+	}
+
+	return result
+}
+
+// Wait blocks until a request is allowed, or until the context is canceled
+func (limiter *RateLimiter) Wait(ctx context.Context) error {
+
+	const location = "geo.RateLimiter.Wait"
+
+	delay := limiter.reserve()
+
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+
+	case <-timer.C:
+		return nil
+
+	case <-ctx.Done():
+		limiter.cancel()
+		return derp.Wrap(ctx.Err(), location, "Canceled while waiting for rate limit")
+	}
+}
+
+// reserve takes a token from the bucket, and returns how long to wait until that token is available
+func (limiter *RateLimiter) reserve() time.Duration {
+
+	if limiter.interval <= 0 {
+		return 0
+	}
+
+	limiter.mutex.Lock()
+	defer limiter.mutex.Unlock()
+
+	now := limiter.now()
+
+	// Refill the bucket for the time since the last update
+	if !limiter.updated.IsZero() {
+		limiter.tokens += float64(now.Sub(limiter.updated)) / float64(limiter.interval)
+		limiter.tokens = min(limiter.tokens, limiter.burst)
+	}
+
+	limiter.updated = now
+	limiter.tokens--
+
+	if limiter.tokens >= 0 {
+		return 0
+	}
+
+	return time.Duration(-limiter.tokens * float64(limiter.interval))
+}
+
+// cancel returns a reserved token to the bucket
+func (limiter *RateLimiter) cancel() {
+
+	limiter.mutex.Lock()
+	defer limiter.mutex.Unlock()
+
+	limiter.tokens = min(limiter.tokens+1, limiter.burst)
+}
+
+// RateLimitedGeocoder is a Geocoder that waits for a RateLimiter before each request
+type RateLimitedGeocoder struct {
+	geocoder Geocoder
+	limiter  *RateLimiter
+}
+
+// NewRateLimitedGeocoder returns a RateLimitedGeocoder that sends requests to geocoder
+// no faster than limiter allows
+func NewRateLimitedGeocoder(geocoder Geocoder, limiter *RateLimiter) RateLimitedGeocoder {
+	return RateLimitedGeocoder{
+		geocoder: geocoder,
+		limiter:  limiter,
+	}
+}
+
+// Geocode implements the Geocoder interface
+func (geocoder RateLimitedGeocoder) Geocode(ctx context.Context, address *Address) error {
+
+	const location = "geo.RateLimitedGeocoder.Geocode"
+
+	if err := geocoder.limiter.Wait(ctx); err != nil {
+		return derp.Wrap(err, location, "Unable to geocode address")
+	}
+
+	if err := geocoder.geocoder.Geocode(ctx, address); err != nil {
+		return derp.Wrap(err, location, "Unable to geocode address")
+	}
+
+	return nil
+}
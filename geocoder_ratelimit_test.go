@@ -0,0 +1,85 @@
+package geo
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateLimiter_Reserve(t *testing.T) {
+
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	limiter := NewRateLimiter(1, 2)
+	limiter.now = func() time.Time { return now }
+
+	// The bucket starts full
+	require.Equal(t, time.Duration(0), limiter.reserve())
+	require.Equal(t, time.Duration(0), limiter.reserve())
+
+	// Then each request waits its turn
+	require.Equal(t, time.Second, limiter.reserve())
+	require.Equal(t, 2*time.Second, limiter.reserve())
+
+	// Time refills the bucket
+	now = now.Add(3 * time.Second)
+	require.Equal(t, time.Duration(0), limiter.reserve())
+
+	// But never past its size
+	now = now.Add(time.Hour)
+	require.Equal(t, time.Duration(0), limiter.reserve())
+	require.Equal(t, time.Duration(0), limiter.reserve())
+	require.Equal(t, time.Second, limiter.reserve())
+}
+
+func TestRateLimiter_Wait(t *testing.T) {
+
+	limiter := NewRateLimiter(50, 1)
+	start := time.Now()
+
+	for range 4 {
+		require.Nil(t, limiter.Wait(context.Background()))
+	}
+
+	require.GreaterOrEqual(t, time.Since(start), 55*time.Millisecond)
+}
+
+func TestRateLimiter_Wait_Canceled(t *testing.T) {
+
+	limiter := NewRateLimiter(0.001, 1)
+	require.Nil(t, limiter.Wait(context.Background()))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	require.NotNil(t, limiter.Wait(ctx))
+}
+
+func TestRateLimiter_Unlimited(t *testing.T) {
+
+	limiter := NewRateLimiter(0, 0)
+
+	for range 100 {
+		require.Equal(t, time.Duration(0), limiter.reserve())
+	}
+}
+
+func TestRateLimitedGeocoder(t *testing.T) {
+
+	inner := &testGeocoder{result: Address{Latitude: 1, Longitude: 2}}
+	limiter := NewRateLimiter(0.001, 1)
+	geocoder := NewRateLimitedGeocoder(inner, limiter)
+
+	address := Address{Formatted: "Somewhere"}
+	require.Nil(t, address.Geocode(context.Background(), geocoder))
+	require.Equal(t, 1.0, address.Latitude)
+
+	// The next request would wait for over 15 minutes
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	require.NotNil(t, address.Geocode(ctx, geocoder))
+	require.Equal(t, 1, inner.count())
+}
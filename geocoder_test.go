@@ -0,0 +1,169 @@
+package geo
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/benpate/derp"
+	"github.com/stretchr/testify/require"
+)
+
+// testGeocoder is a Geocoder that returns a fixed result (or error), and records its queries.
+// When block is not nil, each request waits for it to be closed first.
+type testGeocoder struct {
+	result  Address
+	err     error
+	block   chan struct{}
+	mutex   sync.Mutex
+	queries []string
+}
+
+func (geocoder *testGeocoder) Geocode(ctx context.Context, address *Address) error {
+
+	geocoder.mutex.Lock()
+	geocoder.queries = append(geocoder.queries, address.GeocodeQuery())
+	geocoder.mutex.Unlock()
+
+	if geocoder.block != nil {
+		select {
+		case <-geocoder.block:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	if geocoder.err != nil {
+		return geocoder.err
+	}
+
+	address.applyGeocode(geocoder.result)
+	return nil
+}
+
+// count returns the number of requests that the testGeocoder has received
+func (geocoder *testGeocoder) count() int {
+
+	geocoder.mutex.Lock()
+	defer geocoder.mutex.Unlock()
+
+	return len(geocoder.queries)
+}
+
+func TestAddress_Geocode(t *testing.T) {
+
+	geocoder := &testGeocoder{
+		result: Address{
+			Formatted: "White House, 1600 Pennsylvania Avenue Northwest, Washington, DC 20500, United States",
+			Street1:   "1600 Pennsylvania Avenue Northwest",
+			Locality:  "Washington",
+			Region:    "District of Columbia",
+			Country:   "US",
+			Latitude:  38.8977,
+			Longitude: -77.0365,
+		},
+	}
+
+	address := Address{
+		Name:      "The White House",
+		Formatted: "1600 Pennsylvania Ave, Washington DC",
+		Street2:   "West Wing",
+		Region:    "DC",
+		PlusCode:  "87C4VXX7+39",
+		Timezone:  "America/New_York",
+	}
+
+	require.Nil(t, address.Geocode(context.Background(), geocoder))
+	require.Equal(t, []string{"1600 Pennsylvania Ave, Washington DC"}, geocoder.queries)
+
+	// Geocoded values replace existing ones, and values that the geocoder did not return are kept
+	require.Equal(t, Address{
+		Name:      "The White House",
+		Formatted: "1600 Pennsylvania Ave, Washington DC",
+		Street1:   "1600 Pennsylvania Avenue Northwest",
+		Street2:   "West Wing",
+		Locality:  "Washington",
+		Region:    "District of Columbia",
+		Country:   "US",
+		PlusCode:  "87C4VXX7+39",
+		Timezone:  "America/New_York",
+		Latitude:  38.8977,
+		Longitude: -77.0365,
+	}, address)
+}
+
+func TestAddress_Geocode_EmptyFormatted(t *testing.T) {
+
+	geocoder := &testGeocoder{result: Address{Formatted: "Somewhere", Latitude: 1, Longitude: 2}}
+	address := Address{Street1: "10 Downing Street", Locality: "London", Country: "GB"}
+
+	require.Nil(t, address.Geocode(context.Background(), geocoder))
+	require.Equal(t, []string{"10 Downing Street, London, United Kingdom"}, geocoder.queries)
+	require.Equal(t, "Somewhere", address.Formatted)
+}
+
+func TestAddress_Geocode_Errors(t *testing.T) {
+
+	address := Address{}
+	err := address.Geocode(context.Background(), &testGeocoder{})
+	require.True(t, derp.IsValidationError(err))
+
+	address = Address{Formatted: "Nowhere"}
+	require.NotNil(t, address.Geocode(context.Background(), nil))
+
+	err = address.Geocode(context.Background(), &testGeocoder{err: derp.NotFound("test", "No results")})
+	require.True(t, derp.IsNotFound(err))
+	require.Equal(t, Address{Formatted: "Nowhere"}, address)
+}
+
+func TestAddress_GeocodeQuery(t *testing.T) {
+	require.Equal(t, "", Address{}.GeocodeQuery())
+	require.Equal(t, "somewhere", Address{Formatted: "  somewhere "}.GeocodeQuery())
+	require.Equal(t, "Paris, France", Address{Locality: "Paris", Country: "FR"}.GeocodeQuery())
+}
+
+func TestGeocoderStreet(t *testing.T) {
+	require.Equal(t, "1600 Pennsylvania Avenue", geocoderStreet("US", "1600", "Pennsylvania Avenue"))
+	require.Equal(t, "Unter den Linden 77", geocoderStreet("DE", "77", "Unter den Linden"))
+	require.Equal(t, "Main Street", geocoderStreet("US", "", "Main Street"))
+	require.Equal(t, "12", geocoderStreet("DE", "12", ""))
+	require.Equal(t, "", geocoderStreet("", "", ""))
+}
+
+func TestGeocoderGet_HTTPErrors(t *testing.T) {
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/busy":
+			w.Header().Set("Retry-After", "30")
+			w.WriteHeader(http.StatusTooManyRequests)
+		case "/broken":
+			_, _ = w.Write([]byte("not json"))
+		default:
+			w.WriteHeader(http.StatusForbidden)
+		}
+	}))
+	defer server.Close()
+
+	var result any
+
+	err := geocoderGet(context.Background(), nil, server.URL+"/busy", nil, "", &result)
+	tooMany, retryAfter := derp.IsTooManyRequests(err)
+	require.True(t, tooMany)
+	require.Equal(t, 30*time.Second, retryAfter)
+
+	err = geocoderGet(context.Background(), nil, server.URL+"/forbidden", nil, "", &result)
+	require.True(t, derp.IsForbidden(err))
+
+	err = geocoderGet(context.Background(), nil, server.URL+"/broken", nil, "", &result)
+	require.NotNil(t, err)
+
+	// Canceled contexts stop the request
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = geocoderGet(ctx, nil, server.URL+"/broken", nil, "", &result)
+	require.NotNil(t, err)
+}
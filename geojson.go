@@ -6,6 +6,12 @@ type GeoJSONPoint struct {
 	Coordinates []float64 `json:"coordinates" bson:"coordinates"` // Whatevs
 }
 
+// GeoJSONLineString represents the "strict" format for a LineString in GeoJSON
+type GeoJSONLineString struct {
+	Type        string      `json:"type"        bson:"type"`        // This should always be "LineString"
+	Coordinates [][]float64 `json:"coordinates" bson:"coordinates"` // One coordinate pair per vertex
+}
+
 // GeoJSONPolygon represents the "strict" format for a Polygon in GeoJSON.
 // is is used here to simplify conversion to/from serialization formats
 type GeoJSONPolygon struct {
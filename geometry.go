@@ -0,0 +1,110 @@
+package geo
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/benpate/derp"
+)
+
+// Geometry is implemented by each of the GeoJSON geometry types in this
+// package (Point, LineString, and Polygon)
+type Geometry interface {
+
+	// GeoJSON returns the geometry as a GeoJSON object (a "type"/"coordinates" map)
+	GeoJSON() map[string]any
+
+	// BoundingBox returns the smallest BoundingBox that contains the geometry
+	BoundingBox() BoundingBox
+
+	// IsZero returns TRUE if the geometry is empty
+	IsZero() bool
+}
+
+// UnmarshalGeometryJSON parses any of the GeoJSON geometries supported by this
+// package (Point, LineString, or Polygon), using its "type" property to choose
+// the right one. A JSON `null` returns a nil Geometry.
+func UnmarshalGeometryJSON(data []byte) (Geometry, error) {
+
+	const location = "geo.UnmarshalGeometryJSON"
+
+	// Read the "type" property to find the right geometry
+	intermediate := struct {
+		Type *string `json:"type"`
+	}{}
+
+	if err := json.Unmarshal(data, &intermediate); err != nil {
+		return nil, derp.Wrap(err, location, "Unable to unmarshal original JSON", string(data))
+	}
+
+	// `null` (or an object without a type) is a missing geometry
+	if intermediate.Type == nil {
+
+		if string(bytes.TrimSpace(data)) == "null" {
+			return nil, nil
+		}
+
+		return nil, derp.Internal(location, "Invalid GeoJSON. Geometry must have a 'type'", string(data))
+	}
+
+	switch *intermediate.Type {
+
+	case PropertyTypePoint:
+		result := Point{}
+
+		if err := result.UnmarshalJSON(data); err != nil {
+			return nil, derp.Wrap(err, location, "Unable to unmarshal Point")
+		}
+
+		return result, nil
+
+	case PropertyTypeLineString:
+		result := LineString{}
+
+		if err := result.UnmarshalJSON(data); err != nil {
+			return nil, derp.Wrap(err, location, "Unable to unmarshal LineString")
+		}
+
+		return result, nil
+
+	case PropertyTypePolygon:
+		result := Polygon{}
+
+		if err := result.UnmarshalJSON(data); err != nil {
+			return nil, derp.Wrap(err, location, "Unable to unmarshal Polygon")
+		}
+
+		return result, nil
+	}
+
+	return nil, derp.Internal(location, "Unsupported geometry type", *intermediate.Type)
+}
+
+// dereferenceGeometry returns the value inside a pointer to one of this package's
+// geometries (or a nil Geometry if the pointer is nil), so that a *Polygon can be
+// handled like a Polygon. Other values are returned unchanged.
+func dereferenceGeometry(geometry Geometry) Geometry {
+
+	switch typed := geometry.(type) {
+
+	case *Point:
+		if typed == nil {
+			return nil
+		}
+		return *typed
+
+	case *LineString:
+		if typed == nil {
+			return nil
+		}
+		return *typed
+
+	case *Polygon:
+		if typed == nil {
+			return nil
+		}
+		return *typed
+	}
+
+	return geometry
+}
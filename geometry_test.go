@@ -0,0 +1,43 @@
+package geo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnmarshalGeometryJSON(t *testing.T) {
+
+	geometry, err := UnmarshalGeometryJSON([]byte(`{"type":"Point","coordinates":[1,2]}`))
+	require.Nil(t, err)
+	require.Equal(t, NewPoint(1, 2), geometry)
+
+	geometry, err = UnmarshalGeometryJSON([]byte(`{"type":"LineString","coordinates":[[1,2],[3,4]]}`))
+	require.Nil(t, err)
+	require.Equal(t, NewLineString(NewPosition(1, 2), NewPosition(3, 4)), geometry)
+
+	geometry, err = UnmarshalGeometryJSON([]byte(`{"type":"Polygon","coordinates":[[[1,2],[3,4],[1,2]]]}`))
+	require.Nil(t, err)
+	require.Equal(t, NewPolygon(NewPosition(1, 2), NewPosition(3, 4), NewPosition(1, 2)), geometry)
+}
+
+func TestUnmarshalGeometryJSON_Null(t *testing.T) {
+	geometry, err := UnmarshalGeometryJSON([]byte(` null `))
+	require.Nil(t, err)
+	require.Nil(t, geometry)
+}
+
+func TestUnmarshalGeometryJSON_Invalid(t *testing.T) {
+
+	_, err := UnmarshalGeometryJSON([]byte(`{"type":"MultiPolygon","coordinates":[]}`))
+	require.NotNil(t, err)
+
+	_, err = UnmarshalGeometryJSON([]byte(`{"coordinates":[1,2]}`))
+	require.NotNil(t, err)
+
+	_, err = UnmarshalGeometryJSON([]byte(`{"type":"Point","coordinates":[1]}`))
+	require.NotNil(t, err)
+
+	_, err = UnmarshalGeometryJSON([]byte(`[`))
+	require.NotNil(t, err)
+}
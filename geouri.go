@@ -0,0 +1,278 @@
+package geo
+
+import (
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/benpate/derp"
+	"github.com/benpate/rosetta/mapof"
+)
+
+// Geo URI scheme and parameter names defined by RFC 5870
+// https://datatracker.ietf.org/doc/html/rfc5870
+const (
+	// GeoURIScheme is the URI scheme (including the colon) used by geo URIs.
+	GeoURIScheme = "geo:"
+
+	// GeoURIParameterCRS is the coordinate reference system parameter.
+	GeoURIParameterCRS = "crs"
+
+	// GeoURIParameterUncertainty is the uncertainty (in meters) parameter.
+	GeoURIParameterUncertainty = "u"
+
+	// GeoURICRSWGS84 is the only coordinate reference system defined by RFC 5870,
+	// and the default when no "crs" parameter is present.
+	GeoURICRSWGS84 = "wgs84"
+)
+
+// geoURICoordinate matches the "coord-a/b/c" productions of RFC 5870
+var geoURICoordinate = regexp.MustCompile(`^-?[0-9]+(\.[0-9]+)?$`)
+
+// geoURIUncertainty matches the "uval" production of RFC 5870
+var geoURIUncertainty = regexp.MustCompile(`^[0-9]+(\.[0-9]+)?$`)
+
+// geoURILabel matches the "labeltext" production of RFC 5870 (used for parameter names and CRS labels)
+var geoURILabel = regexp.MustCompile(`^[A-Za-z0-9-]+$`)
+
+// geoURIValue matches the "pvalue" production of RFC 5870 (before percent-decoding)
+var geoURIValue = regexp.MustCompile(`^([A-Za-z0-9\-._~\[\]:&+$]|%[0-9A-Fa-f]{2})+$`)
+
+// GeoURI represents a parsed "geo" URI
+// https://datatracker.ietf.org/doc/html/rfc5870
+type GeoURI struct {
+	Point       Point        // Location of the URI. Altitude is populated from the optional third coordinate.
+	Uncertainty float64      // Uncertainty of the location, in meters. Zero when the "u" parameter is omitted or zero.
+	Parameters  mapof.String // Additional (non-crs, non-u) parameters, keyed by lowercase name and percent-decoded
+}
+
+// NewGeoURI returns a GeoURI for the given Point, with no uncertainty or parameters.
+func NewGeoURI(point Point) GeoURI {
+	return GeoURI{
+		Point:      point,
+		Parameters: mapof.NewString(),
+	}
+}
+
+// ParseGeoURI parses an RFC 5870 "geo" URI, such as "geo:37.78,-122.4;u=35".
+// The scheme, parameter names, and "crs" value are matched case-insensitively.
+// If present, "crs" must be the first parameter and must be "wgs84", and "u"
+// must come before any other parameters.
+func ParseGeoURI(value string) (GeoURI, error) {
+
+	const location = "geo.ParseGeoURI"
+
+	result := NewGeoURI(Point{})
+
+	// Validate the scheme
+	if (len(value) < len(GeoURIScheme)) || !strings.EqualFold(value[:len(GeoURIScheme)], GeoURIScheme) {
+		return GeoURI{}, derp.Internal(location, "URI must begin with 'geo:'", value)
+	}
+
+	parts := strings.Split(value[len(GeoURIScheme):], ";")
+
+	// Parse the coordinates
+	coordinates := strings.Split(parts[0], ",")
+
+	if (len(coordinates) != 2) && (len(coordinates) != 3) {
+		return GeoURI{}, derp.Internal(location, "URI must contain 2 or 3 coordinates", value)
+	}
+
+	values := make([]float64, len(coordinates))
+
+	for index, coordinate := range coordinates {
+
+		if !geoURICoordinate.MatchString(coordinate) {
+			return GeoURI{}, derp.Internal(location, "Invalid coordinate", value, coordinate)
+		}
+
+		// The regular expression guarantees the syntax, but not that the value fits in a float64
+		parsed, err := strconv.ParseFloat(coordinate, 64)
+
+		if err != nil {
+			return GeoURI{}, derp.Wrap(err, location, "Coordinate is out of range", value, coordinate)
+		}
+
+		values[index] = parsed
+	}
+
+	// Coordinates are latitude, longitude[, altitude] in WGS84
+	latitude, longitude := values[0], values[1]
+
+	if (latitude < -90) || (latitude > 90) {
+		return GeoURI{}, derp.Internal(location, "Latitude must be between -90 and 90", value)
+	}
+
+	if (longitude < -180) || (longitude > 180) {
+		return GeoURI{}, derp.Internal(location, "Longitude must be between -180 and 180", value)
+	}
+
+	// Longitude is meaningless at the poles, and MUST be ignored (RFC 5870 section 3.4.2)
+	if (latitude == 90) || (latitude == -90) {
+		longitude = 0
+	}
+
+	result.Point = NewPoint(longitude, latitude)
+
+	if len(values) == 3 {
+		result.Point.Altitude = values[2]
+	}
+
+	// Parse parameters
+	hasUncertainty := false
+
+	for index, parameter := range parts[1:] {
+
+		name, parameterValue, hasValue := strings.Cut(parameter, "=")
+
+		if !geoURILabel.MatchString(name) {
+			return GeoURI{}, derp.Internal(location, "Invalid parameter name", value, name)
+		}
+
+		// Parameter names are case-insensitive (RFC 5870 section 3.3)
+		name = strings.ToLower(name)
+
+		switch name {
+
+		case GeoURIParameterCRS:
+
+			if index != 0 {
+				return GeoURI{}, derp.Internal(location, "'crs' must be the first parameter", value)
+			}
+
+			if !strings.EqualFold(parameterValue, GeoURICRSWGS84) {
+				return GeoURI{}, derp.Internal(location, "Unsupported coordinate reference system", value, parameterValue)
+			}
+
+		case GeoURIParameterUncertainty:
+
+			if hasUncertainty || (len(result.Parameters) > 0) {
+				return GeoURI{}, derp.Internal(location, "'u' must appear once, before any other parameters", value)
+			}
+
+			if !geoURIUncertainty.MatchString(parameterValue) {
+				return GeoURI{}, derp.Internal(location, "Invalid uncertainty", value, parameterValue)
+			}
+
+			uncertainty, err := strconv.ParseFloat(parameterValue, 64)
+
+			if err != nil {
+				return GeoURI{}, derp.Wrap(err, location, "Uncertainty is out of range", value, parameterValue)
+			}
+
+			result.Uncertainty = uncertainty
+			hasUncertainty = true
+
+		default:
+
+			if _, exists := result.Parameters[name]; exists {
+				return GeoURI{}, derp.Internal(location, "Duplicate parameter", value, name)
+			}
+
+			if hasValue {
+
+				if !geoURIValue.MatchString(parameterValue) {
+					return GeoURI{}, derp.Internal(location, "Invalid parameter value", value, name)
+				}
+
+				decoded, err := url.PathUnescape(parameterValue)
+
+				if err != nil {
+					return GeoURI{}, derp.Wrap(err, location, "Invalid percent-encoding in parameter value", value, name)
+				}
+
+				parameterValue = decoded
+			}
+
+			result.Parameters[name] = parameterValue
+		}
+	}
+
+	return result, nil
+}
+
+// String returns this GeoURI in RFC 5870 format. The (default) "crs" parameter is
+// omitted, "u" is included when Uncertainty is non-zero, and any additional
+// parameters are written in alphabetical order.
+func (geoURI GeoURI) String() string {
+
+	var builder strings.Builder
+
+	builder.WriteString(GeoURIScheme)
+	builder.WriteString(formatGeoURICoordinate(geoURI.Point.Latitude))
+	builder.WriteByte(',')
+	builder.WriteString(formatGeoURICoordinate(geoURI.Point.Longitude))
+
+	if geoURI.Point.Altitude != 0 {
+		builder.WriteByte(',')
+		builder.WriteString(formatGeoURICoordinate(geoURI.Point.Altitude))
+	}
+
+	if geoURI.Uncertainty > 0 {
+		builder.WriteString(";" + GeoURIParameterUncertainty + "=")
+		builder.WriteString(strconv.FormatFloat(geoURI.Uncertainty, 'f', -1, 64))
+	}
+
+	// Write additional parameters in a predictable order
+	names := geoURI.Parameters.Keys()
+	sort.Strings(names)
+
+	for _, name := range names {
+		builder.WriteByte(';')
+		builder.WriteString(strings.ToLower(name))
+
+		if value := geoURI.Parameters[name]; value != "" {
+			builder.WriteByte('=')
+			builder.WriteString(escapeGeoURIValue(value))
+		}
+	}
+
+	return builder.String()
+}
+
+// formatGeoURICoordinate formats a coordinate losslessly, without exponents
+func formatGeoURICoordinate(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
+
+// escapeGeoURIValue percent-encodes every byte that is not allowed
+// unescaped in a geo URI parameter value ("paramchar" in RFC 5870)
+func escapeGeoURIValue(value string) string {
+
+	const hex = "0123456789ABCDEF"
+
+	var builder strings.Builder
+
+	for index := 0; index < len(value); index++ {
+
+		character := value[index]
+
+		if isGeoURIParamChar(character) {
+			builder.WriteByte(character)
+			continue
+		}
+
+		builder.WriteByte('%')
+		builder.WriteByte(hex[character>>4])
+		builder.WriteByte(hex[character&0x0f])
+	}
+
+	return builder.String()
+}
+
+// isGeoURIParamChar returns TRUE if the character is "unreserved" or "p-unreserved"
+func isGeoURIParamChar(character byte) bool {
+
+	switch {
+	case (character >= 'a') && (character <= 'z'):
+		return true
+	case (character >= 'A') && (character <= 'Z'):
+		return true
+	case (character >= '0') && (character <= '9'):
+		return true
+	}
+
+	return strings.IndexByte("-._~[]:&+$", character) >= 0
+}
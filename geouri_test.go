@@ -0,0 +1,109 @@
+package geo
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/benpate/rosetta/mapof"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseGeoURI(t *testing.T) {
+
+	result, err := ParseGeoURI("geo:37.78,-122.4;crs=wgs84;u=35")
+	require.Nil(t, err)
+	require.Equal(t, NewPoint(-122.4, 37.78), result.Point)
+	require.Equal(t, 35.0, result.Uncertainty)
+	require.Empty(t, result.Parameters)
+}
+
+func TestParseGeoURI_Altitude(t *testing.T) {
+
+	result, err := ParseGeoURI("geo:48.2010,16.3695,183")
+	require.Nil(t, err)
+	require.Equal(t, NewPointWithAltitude(16.3695, 48.2010, 183), result.Point)
+	require.Zero(t, result.Uncertainty)
+}
+
+func TestParseGeoURI_CaseFolding(t *testing.T) {
+
+	// The scheme, parameter names, and crs value are all case-insensitive
+	result, err := ParseGeoURI("GEO:66,30;CRS=WGS84;U=6.500;Foo=Bar%20Baz;flag")
+	require.Nil(t, err)
+	require.Equal(t, NewPoint(30, 66), result.Point)
+	require.Equal(t, 6.5, result.Uncertainty)
+
+	// Parameter values are percent-decoded, but otherwise left untouched
+	require.Equal(t, mapof.String{"foo": "Bar Baz", "flag": ""}, result.Parameters)
+}
+
+func TestParseGeoURI_Poles(t *testing.T) {
+
+	// Longitude is ignored at the poles
+	result, err := ParseGeoURI("geo:90,-22.43;crs=WGS84")
+	require.Nil(t, err)
+	require.Equal(t, NewPoint(0, 90), result.Point)
+
+	result, err = ParseGeoURI("geo:-90,180")
+	require.Nil(t, err)
+	require.Equal(t, NewPoint(0, -90), result.Point)
+}
+
+func TestParseGeoURI_Errors(t *testing.T) {
+
+	checkError := func(value string) {
+		_, err := ParseGeoURI(value)
+		require.NotNil(t, err, value)
+	}
+
+	checkError("")
+	checkError("geo")
+	checkError("http://example.com")
+	checkError("geo:37.78")
+	checkError("geo:1,2,3,4")
+	checkError("geo:+37.78,-122.4")      // no leading "+" allowed
+	checkError("geo:37.,-122.4")         // fractional digits are required after "."
+	checkError("geo:.5,-122.4")          // integer digits are required
+	checkError("geo:1e2,3")              // no exponents
+	checkError("geo:91,0")               // latitude out of range
+	checkError("geo:0,180.5")            // longitude out of range
+	checkError("geo:0,0;crs=nad27")      // unsupported crs
+	checkError("geo:0,0;u=-1")           // uncertainty cannot be negative
+	checkError("geo:0,0;u=1;u=2")        // duplicate uncertainty
+	checkError("geo:0,0;a=1;u=2")        // u after other parameters
+	checkError("geo:0,0;a=1;A=2")        // duplicate (case-folded) parameter
+	checkError("geo:0,0;a b=1")          // invalid parameter name
+	checkError("geo:0,0;a=")             // empty parameter value
+	checkError("geo:0,0;a=b c")          // invalid character in value
+	checkError("geo:0,0;a=%zz")          // invalid percent-encoding
+	checkError("geo:0,0;u=35;crs=wgs84") // crs must be the first parameter
+	checkError("geo:0,0;crs=wgs84;crs=wgs84")
+	checkError("geo:1,2," + strings.Repeat("9", 400))   // altitude overflows a float64
+	checkError("geo:1," + strings.Repeat("9", 400))     // longitude overflows a float64
+	checkError("geo:1,2;u=" + strings.Repeat("9", 400)) // uncertainty overflows a float64
+}
+
+func TestGeoURI_String(t *testing.T) {
+
+	geoURI := NewGeoURI(NewPointWithAltitude(-122.4, 37.78, 10))
+	geoURI.Uncertainty = 35
+	geoURI.Parameters["name"] = "Café; bar"
+	geoURI.Parameters["flag"] = ""
+
+	require.Equal(t, "geo:37.78,-122.4,10;u=35;flag;name=Caf%C3%A9%3B%20bar", geoURI.String())
+
+	// Round-trip back through the parser
+	result, err := ParseGeoURI(geoURI.String())
+	require.Nil(t, err)
+	require.Equal(t, geoURI, result)
+}
+
+func TestPoint_GeoURI(t *testing.T) {
+	require.Equal(t, "geo:37.78,-122.4", NewPoint(-122.4, 37.78).GeoURI())
+	require.Equal(t, "geo:0,0", Point{}.GeoURI())
+}
+
+func TestAddress_GeoURI(t *testing.T) {
+	require.Equal(t, "", Address{Formatted: "123 Main St"}.GeoURI())
+	require.Equal(t, "geo:34.25,-118.5", Address{Longitude: -118.5, Latitude: 34.25}.GeoURI())
+}
@@ -0,0 +1,365 @@
+package geo
+
+import (
+	_ "embed"
+	"strings"
+	"sync"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// The ISO 3166 tables are generated from the Debian iso-codes project
+// (https://salsa.debian.org/iso-codes-team/iso-codes), and are parsed the first
+// time that they are used.
+
+//go:embed data/iso3166-1.tsv
+var iso3166CountriesTSV string
+
+//go:embed data/iso3166-1-names.tsv
+var iso3166CountryNamesTSV string
+
+//go:embed data/iso3166-2.tsv
+var iso3166SubdivisionsTSV string
+
+// Country is a single entry in the ISO 3166-1 table of countries
+type Country struct {
+	Alpha2       string // Two-letter code, such as "US"
+	Alpha3       string // Three-letter code, such as "USA"
+	Numeric      string // Three-digit numeric code (UN M49), such as "840"
+	Name         string // ISO short name in English, such as "Korea, Republic of"
+	CommonName   string // Common name in English (if different), such as "South Korea"
+	OfficialName string // Official name in English (if different), such as "United States of America"
+}
+
+// Subdivision is a single entry in the ISO 3166-2 table of country subdivisions
+type Subdivision struct {
+	Code    string // Full code, such as "US-CA"
+	Country string // ISO 3166-1 alpha-2 code of the country, such as "US"
+	Parent  string // Full code of the parent subdivision (if any), such as "FR-IDF"
+	Type    string // Kind of subdivision, such as "State", "Province", or "Prefecture"
+	Name    string // Name of the subdivision, usually in a local language
+}
+
+// iso3166CountryAliases are other common names for countries, which are not in the ISO tables
+var iso3166CountryAliases = map[string]string{
+	"america":          "US",
+	"uk":               "GB",
+	"great britain":    "GB",
+	"britain":          "GB",
+	"england":          "GB",
+	"scotland":         "GB",
+	"wales":            "GB",
+	"northern ireland": "GB",
+	"holland":          "NL",
+	"the netherlands":  "NL",
+	"russia":           "RU",
+	"czech republic":   "CZ",
+	"ivory coast":      "CI",
+	"macedonia":        "MK",
+	"swaziland":        "SZ",
+	"burma":            "MM",
+	"cape verde":       "CV",
+	"vatican":          "VA",
+	"vatican city":     "VA",
+	"palestine":        "PS",
+	"east timor":       "TL",
+	"brunei":           "BN",
+	"micronesia":       "FM",
+	"turkey":           "TR",
+	"nippon":           "JP",
+}
+
+// iso3166Tables holds the parsed ISO 3166 tables, and indexes into them
+type iso3166Tables struct {
+	countries          []Country
+	countriesByCode    map[string]int               // alpha-2, alpha-3, and numeric codes
+	countriesByName    map[string]int               // every name (in any language) that identifies exactly one country
+	localizedNames     map[string]map[string]string // alpha-2 => language => name
+	subdivisions       []Subdivision
+	subdivisionsByCode map[string]int            // full code (upper case)
+	subdivisionsByName map[string]map[string]int // alpha-2 => name key => index
+}
+
+// iso3166 parses the embedded ISO 3166 tables the first time they are used
+var iso3166 = sync.OnceValue(func() *iso3166Tables {
+
+	tables := &iso3166Tables{
+		countriesByCode:    map[string]int{},
+		countriesByName:    map[string]int{},
+		localizedNames:     map[string]map[string]string{},
+		subdivisionsByCode: map[string]int{},
+		subdivisionsByName: map[string]map[string]int{},
+	}
+
+	// Names that match more than one country are ambiguous, and are not used
+	ambiguous := map[string]bool{}
+
+	addName := func(name string, index int) {
+
+		key := iso3166Key(name)
+
+		if (key == "") || ambiguous[key] {
+			return
+		}
+
+		if existing, ok := tables.countriesByName[key]; ok && (existing != index) {
+			delete(tables.countriesByName, key)
+			ambiguous[key] = true
+			return
+		}
+
+		tables.countriesByName[key] = index
+	}
+
+	// ISO 3166-1 countries
+	for _, fields := range iso3166Rows(iso3166CountriesTSV, 6) {
+
+		index := len(tables.countries)
+
+		tables.countries = append(tables.countries, Country{
+			Alpha2:       fields[0],
+			Alpha3:       fields[1],
+			Numeric:      fields[2],
+			Name:         fields[3],
+			CommonName:   fields[4],
+			OfficialName: fields[5],
+		})
+
+		tables.countriesByCode[fields[0]] = index
+		tables.countriesByCode[fields[1]] = index
+		tables.countriesByCode[fields[2]] = index
+
+		addName(fields[3], index)
+		addName(fields[4], index)
+		addName(fields[5], index)
+	}
+
+	// Localized country names. The header row lists the languages.
+	_, header, _ := strings.Cut(strings.SplitN(iso3166CountryNamesTSV, "\n", 3)[1], "\t")
+	languages := strings.Split(header, "\t")
+
+	for _, fields := range iso3166Rows(iso3166CountryNamesTSV, len(languages)+1) {
+
+		index, ok := tables.countriesByCode[fields[0]]
+
+		if !ok {
+			continue
+		}
+
+		names := make(map[string]string, len(languages))
+
+		for column, language := range languages {
+			if name := fields[column+1]; name != "" {
+				names[language] = name
+				addName(name, index)
+			}
+		}
+
+		tables.localizedNames[fields[0]] = names
+	}
+
+	for alias, code := range iso3166CountryAliases {
+		addName(alias, tables.countriesByCode[code])
+	}
+
+	// ISO 3166-2 subdivisions
+	for _, fields := range iso3166Rows(iso3166SubdivisionsTSV, 4) {
+
+		country, _, _ := strings.Cut(fields[0], "-")
+		index := len(tables.subdivisions)
+
+		tables.subdivisions = append(tables.subdivisions, Subdivision{
+			Code:    fields[0],
+			Country: country,
+			Parent:  fields[1],
+			Type:    fields[2],
+			Name:    fields[3],
+		})
+
+		tables.subdivisionsByCode[fields[0]] = index
+
+		if tables.subdivisionsByName[country] == nil {
+			tables.subdivisionsByName[country] = map[string]int{}
+		}
+
+		// When two subdivisions share a name (such as a region and a department), prefer the top-level one
+		key := iso3166Key(fields[3])
+
+		if existing, ok := tables.subdivisionsByName[country][key]; !ok || (tables.subdivisions[existing].Parent != "") {
+			tables.subdivisionsByName[country][key] = index
+		}
+	}
+
+	// Japanese prefectures may also be written in Japanese (JP-01 through JP-47)
+	for index, prefecture := range addressPrefectures {
+		if subdivision, ok := tables.subdivisionsByCode["JP-"+padDigits(index+1, 2)]; ok {
+			tables.subdivisionsByName["JP"][iso3166Key(prefecture)] = subdivision
+		}
+	}
+
+	return tables
+})
+
+// LookupCountry finds a country by its ISO 3166-1 alpha-2, alpha-3, or numeric code,
+// or by its name. Names may be in English (the ISO, common, or official name), in any
+// of the embedded languages (such as "États-Unis" or "Deutschland"), or a common
+// alias (such as "UK" or "Holland"), and are matched without regard to case or accents.
+func LookupCountry(value string) (Country, bool) {
+
+	tables := iso3166()
+	value = strings.TrimSpace(value)
+
+	if index, ok := tables.countriesByCode[strings.ToUpper(value)]; ok {
+		return tables.countries[index], true
+	}
+
+	if index, ok := tables.countriesByName[iso3166Key(value)]; ok {
+		return tables.countries[index], true
+	}
+
+	return Country{}, false
+}
+
+// Countries returns every country in the ISO 3166-1 table, sorted by alpha-2 code
+func Countries() []Country {
+	return append([]Country(nil), iso3166().countries...)
+}
+
+// DisplayName returns the name of this country that people use in English,
+// such as "South Korea" instead of "Korea, Republic of"
+func (country Country) DisplayName() string {
+
+	if country.CommonName != "" {
+		return country.CommonName
+	}
+
+	return country.Name
+}
+
+// LocalizedName returns the name of this country in a language, which is identified
+// by a BCP 47 tag such as "fr", "de-CH", or "zh-Hans". Embedded languages are ar, de,
+// es, fr, it, ja, ko, nl, pl, pt, ru, sv, tr, and zh (Simplified Chinese). Other
+// languages (including English) return the DisplayName.
+func (country Country) LocalizedName(language string) string {
+
+	language, _, _ = strings.Cut(strings.ToLower(strings.ReplaceAll(language, "_", "-")), "-")
+
+	if name, ok := iso3166().localizedNames[country.Alpha2][language]; ok {
+		return name
+	}
+
+	return country.DisplayName()
+}
+
+// LookupSubdivision finds a subdivision of a country (which may be any value accepted
+// by LookupCountry) by its full ISO 3166-2 code ("US-CA"), the part of the code after
+// the country ("CA"), or its name ("California"). Names are matched without regard
+// to case or accents.
+func LookupSubdivision(country string, value string) (Subdivision, bool) {
+
+	tables := iso3166()
+	value = strings.TrimSpace(value)
+
+	// Full codes include the country
+	if index, ok := tables.subdivisionsByCode[strings.ToUpper(value)]; ok {
+		return tables.subdivisions[index], true
+	}
+
+	found, ok := LookupCountry(country)
+
+	if !ok {
+		return Subdivision{}, false
+	}
+
+	if index, ok := tables.subdivisionsByCode[found.Alpha2+"-"+strings.ToUpper(value)]; ok {
+		return tables.subdivisions[index], true
+	}
+
+	if index, ok := tables.subdivisionsByName[found.Alpha2][iso3166Key(value)]; ok {
+		return tables.subdivisions[index], true
+	}
+
+	return Subdivision{}, false
+}
+
+// Subdivisions returns every ISO 3166-2 subdivision of a country (which may be
+// any value accepted by LookupCountry), sorted by code
+func Subdivisions(country string) []Subdivision {
+
+	found, ok := LookupCountry(country)
+
+	if !ok {
+		return nil
+	}
+
+	var result []Subdivision
+
+	for _, subdivision := range iso3166().subdivisions {
+		if subdivision.Country == found.Alpha2 {
+			result = append(result, subdivision)
+		}
+	}
+
+	return result
+}
+
+// LocalCode returns the part of this subdivision's code after the country, such as "CA" for "US-CA"
+func (subdivision Subdivision) LocalCode() string {
+	_, result, _ := strings.Cut(subdivision.Code, "-")
+	return result
+}
+
+// Normalize converts this Address's Country into an ISO 3166-1 alpha-2 code, and its
+// Region into the local part of an ISO 3166-2 subdivision code (such as "CA" for
+// California, or "ON" for Ontario). Values that are not recognized are left unchanged.
+func (address *Address) Normalize() {
+
+	if country, ok := LookupCountry(address.Country); ok {
+		address.Country = country.Alpha2
+	}
+
+	if address.Region == "" {
+		return
+	}
+
+	if subdivision, ok := LookupSubdivision(address.Country, address.Region); ok && (subdivision.Country == address.Country) {
+		address.Region = subdivision.LocalCode()
+	}
+}
+
+// iso3166Rows splits an embedded table into rows of tab-separated fields,
+// skipping comments and rows without enough fields
+func iso3166Rows(data string, columns int) [][]string {
+
+	var result [][]string
+
+	for line := range strings.Lines(data) {
+
+		line = strings.TrimRight(line, "\r\n")
+
+		if (line == "") || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		if fields := strings.Split(line, "\t"); len(fields) >= columns {
+			result = append(result, fields)
+		}
+	}
+
+	return result
+}
+
+// iso3166Key normalizes a name for lookups by removing case, accents, and periods
+func iso3166Key(value string) string {
+
+	var builder strings.Builder
+
+	for _, r := range norm.NFD.String(strings.ToLower(value)) {
+		if !unicode.Is(unicode.Mn, r) && (r != '.') {
+			builder.WriteRune(r)
+		}
+	}
+
+	return strings.Join(strings.Fields(norm.NFC.String(builder.String())), " ")
+}
@@ -0,0 +1,198 @@
+package geo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLookupCountry(t *testing.T) {
+
+	tests := map[string]string{
+		"US":                       "US",
+		"us":                       "US",
+		"USA":                      "US",
+		"840":                      "US",
+		"United States":            "US",
+		"United States of America": "US",
+		"america":                  "US",
+		"États-Unis":               "US",
+		"Etats-Unis":               "US",
+		"Deutschland":              "DE",
+		"Allemagne":                "DE",
+		"Germany":                  "DE",
+		"DEU":                      "DE",
+		"276":                      "DE",
+		"UK":                       "GB",
+		"United Kingdom":           "GB",
+		"Great Britain":            "GB",
+		"  japan ":                 "JP",
+		"日本":                       "JP",
+		"South Korea":              "KR",
+		"Korea, Republic of":       "KR",
+		"대한민국":                     "KR",
+		"Côte d'Ivoire":            "CI",
+		"Cote d'Ivoire":            "CI",
+		"Ivory Coast":              "CI",
+		"Holland":                  "NL",
+		"Российская Федерация": "RU",
+		"Russia": "RU",
+	}
+
+	for value, expected := range tests {
+		country, ok := LookupCountry(value)
+		require.True(t, ok, value)
+		require.Equal(t, expected, country.Alpha2, value)
+	}
+
+	for _, value := range []string{"", "XX", "Atlantis", "999"} {
+		_, ok := LookupCountry(value)
+		require.False(t, ok, value)
+	}
+}
+
+func TestLookupCountry_Codes(t *testing.T) {
+
+	country, ok := LookupCountry("Canada")
+	require.True(t, ok)
+	require.Equal(t, Country{Alpha2: "CA", Alpha3: "CAN", Numeric: "124", Name: "Canada"}, country)
+
+	country, ok = LookupCountry("004")
+	require.True(t, ok)
+	require.Equal(t, "AFG", country.Alpha3)
+	require.Equal(t, "Afghanistan", country.DisplayName())
+}
+
+func TestCountry_LocalizedName(t *testing.T) {
+
+	country, ok := LookupCountry("DE")
+	require.True(t, ok)
+
+	require.Equal(t, "Deutschland", country.LocalizedName("de"))
+	require.Equal(t, "Deutschland", country.LocalizedName("de-CH"))
+	require.Equal(t, "Deutschland", country.LocalizedName("de_AT"))
+	require.Equal(t, "Allemagne", country.LocalizedName("fr"))
+	require.Equal(t, "ドイツ", country.LocalizedName("ja"))
+	require.Equal(t, "德国", country.LocalizedName("zh-Hans"))
+	require.Equal(t, "Germany", country.LocalizedName("en"))
+	require.Equal(t, "Germany", country.LocalizedName(""))
+	require.Equal(t, "Germany", country.LocalizedName("xx"))
+
+	country, ok = LookupCountry("KR")
+	require.True(t, ok)
+	require.Equal(t, "South Korea", country.LocalizedName("en-US"))
+}
+
+func TestCountries(t *testing.T) {
+
+	countries := Countries()
+	require.Equal(t, 249, len(countries))
+	require.Equal(t, "AD", countries[0].Alpha2)
+
+	// Modifying the result does not change the table
+	countries[0].Name = "Changed"
+	require.Equal(t, "Andorra", Countries()[0].Name)
+}
+
+func TestLookupSubdivision(t *testing.T) {
+
+	tests := []struct {
+		country  string
+		value    string
+		expected string
+	}{
+		{"US", "CA", "US-CA"},
+		{"US", "ca", "US-CA"},
+		{"US", "California", "US-CA"},
+		{"USA", "california", "US-CA"},
+		{"", "US-CA", "US-CA"},
+		{"United States", "District of Columbia", "US-DC"},
+		{"CA", "Ontario", "CA-ON"},
+		{"CA", "Quebec", "CA-QC"},
+		{"CA", "Québec", "CA-QC"},
+		{"AU", "New South Wales", "AU-NSW"},
+		{"AU", "nsw", "AU-NSW"},
+		{"JP", "Tokyo", "JP-13"},
+		{"JP", "東京都", "JP-13"},
+		{"JP", "沖縄県", "JP-47"},
+		{"JP", "13", "JP-13"},
+		{"DE", "Bayern", "DE-BY"},
+		{"FR", "Île-de-France", "FR-IDF"},
+		{"FR", "Ile-de-France", "FR-IDF"},
+		{"FR", "75", "FR-75"},
+		{"GB", "Scotland", "GB-SCT"},
+	}
+
+	for _, test := range tests {
+		subdivision, ok := LookupSubdivision(test.country, test.value)
+		require.True(t, ok, test.country+" "+test.value)
+		require.Equal(t, test.expected, subdivision.Code, test.country+" "+test.value)
+	}
+
+	// Unknown values and unknown countries
+	_, ok := LookupSubdivision("US", "Ontario")
+	require.False(t, ok)
+
+	_, ok = LookupSubdivision("XX", "CA")
+	require.False(t, ok)
+}
+
+func TestSubdivision_Fields(t *testing.T) {
+
+	subdivision, ok := LookupSubdivision("FR", "75")
+	require.True(t, ok)
+	require.Equal(t, "FR", subdivision.Country)
+	require.Equal(t, "FR-IDF", subdivision.Parent)
+	require.Equal(t, "75", subdivision.LocalCode())
+
+	subdivision, ok = LookupSubdivision("US", "NY")
+	require.True(t, ok)
+	require.Equal(t, Subdivision{Code: "US-NY", Country: "US", Type: "State", Name: "New York"}, subdivision)
+}
+
+func TestSubdivisions(t *testing.T) {
+
+	subdivisions := Subdivisions("Japan")
+	require.Equal(t, 47, len(subdivisions))
+	require.Equal(t, "JP-01", subdivisions[0].Code)
+
+	require.Nil(t, Subdivisions("Atlantis"))
+}
+
+func TestAddress_Normalize(t *testing.T) {
+
+	tests := []struct {
+		country        string
+		region         string
+		expectedCounty string
+		expectedRegion string
+	}{
+		{"United States", "California", "US", "CA"},
+		{"USA", "ca", "US", "CA"},
+		{"us", "New York", "US", "NY"},
+		{"Canada", "Québec", "CA", "QC"},
+		{"Australia", "New South Wales", "AU", "NSW"},
+		{"日本", "東京都", "JP", "13"},
+		{"Deutschland", "Bayern", "DE", "BY"},
+		{"France", "", "FR", ""},
+		{"United Kingdom", "Somewhere Else", "GB", "Somewhere Else"},
+		{"Atlantis", "California", "Atlantis", "California"},
+		{"", "California", "", "California"},
+	}
+
+	for _, test := range tests {
+
+		address := Address{
+			Street1:  "1 Main Street",
+			Country:  test.country,
+			Region:   test.region,
+			Locality: "Somewhere",
+		}
+
+		address.Normalize()
+		require.Equal(t, test.expectedCounty, address.Country, test.country)
+		require.Equal(t, test.expectedRegion, address.Region, test.region)
+		require.Equal(t, "1 Main Street", address.Street1)
+		require.Equal(t, "Somewhere", address.Locality)
+	}
+}
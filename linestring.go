@@ -0,0 +1,210 @@
+package geo
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/benpate/derp"
+	"github.com/benpate/rosetta/slice"
+	"github.com/benpate/rosetta/sliceof"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// LineString represents a GeoJSON "LineString" object
+// https://datatracker.ietf.org/doc/html/rfc7946#section-3.1.4
+type LineString struct {
+	Coordinates sliceof.Object[Position]
+}
+
+// NewLineString returns a LineString made up of the given positions.
+func NewLineString(coordinates ...Position) LineString {
+	return LineString{
+		Coordinates: coordinates,
+	}
+}
+
+// NewLineStringFromPolyline decodes a string created by Google's Encoded Polyline
+// Algorithm (see PolylinePrecision5 and PolylinePrecision6) into a LineString.
+func NewLineStringFromPolyline(data string, precision int) (LineString, error) {
+
+	const location = "geo.NewLineStringFromPolyline"
+
+	coordinates, err := DecodePolyline(data, precision)
+
+	if err != nil {
+		return LineString{}, derp.Wrap(err, location, "Unable to decode polyline")
+	}
+
+	return NewLineString(coordinates...), nil
+}
+
+// IsZero returns TRUE if this LineString has no coordinates.
+func (lineString LineString) IsZero() bool {
+	return lineString.Coordinates.IsZero()
+}
+
+// NotZero returns TRUE if this LineString has at least one coordinate.
+func (lineString LineString) NotZero() bool {
+	return !lineString.IsZero()
+}
+
+// BoundingBox returns the smallest BoundingBox that contains this LineString
+func (lineString LineString) BoundingBox() BoundingBox {
+	return NewBoundingBoxFromPositions(lineString.Coordinates...)
+}
+
+// Transform converts every coordinate in this LineString from one coordinate reference system into another
+func (lineString LineString) Transform(from Projection, to Projection) (LineString, error) {
+
+	const location = "geo.LineString.Transform"
+
+	coordinates, err := transformPositions(lineString.Coordinates, from, to)
+
+	if err != nil {
+		return LineString{}, derp.Wrap(err, location, "Unable to transform line string")
+	}
+
+	return NewLineString(coordinates...), nil
+}
+
+/******************************************
+ * Marhshalling methods
+ ******************************************/
+
+// String returns the coordinates as a comma-delimited "lon,lat,lon,lat,..." string.
+func (lineString LineString) String() string {
+	if lineString.IsZero() {
+		return ""
+	}
+
+	result := slice.Map(lineString.Coordinates, Position.String)
+	return strings.Join(result, ",")
+}
+
+// Polyline returns the coordinates encoded with Google's Encoded Polyline
+// Algorithm, at the given precision (see PolylinePrecision5 and PolylinePrecision6)
+func (lineString LineString) Polyline(precision int) (string, error) {
+
+	const location = "geo.LineString.Polyline"
+
+	result, err := EncodePolyline(lineString.Coordinates, precision)
+
+	if err != nil {
+		return "", derp.Wrap(err, location, "Unable to encode LineString")
+	}
+
+	return result, nil
+}
+
+// GeoJSON returns a GeoJSON representation of this LineString
+func (lineString LineString) GeoJSON() map[string]any {
+	return map[string]any{
+		PropertyType:        PropertyTypeLineString,
+		PropertyCoordinates: lineString.MarshalSlice(),
+	}
+}
+
+// MarshalSlice returns a slice of coordinate pairs, which is the
+// standard way of representing a GeoJSON LineString
+func (lineString LineString) MarshalSlice() [][]float64 {
+	return slice.Map(lineString.Coordinates, Position.MarshalSlice)
+}
+
+// MarshalStruct returns this LineString as a strongly-typed GeoJSONLineString.
+func (lineString LineString) MarshalStruct() GeoJSONLineString {
+	return GeoJSONLineString{
+		Type:        PropertyTypeLineString,
+		Coordinates: lineString.MarshalSlice(),
+	}
+}
+
+// MarshalJSON is a custom json.Marshaller that returns this LineString
+// as a GeoJSON object.
+func (lineString LineString) MarshalJSON() ([]byte, error) {
+
+	if lineString.IsZero() {
+		return json.Marshal(nil)
+	}
+
+	return json.Marshal(lineString.MarshalStruct())
+}
+
+// MarshalBSON is a custom BSON marshaller that serializes this
+// LineString into a GeoJSON object.
+func (lineString LineString) MarshalBSON() ([]byte, error) {
+	return bson.Marshal(lineString.MarshalStruct())
+}
+
+/******************************************
+ * Unmarhshalling methods
+ ******************************************/
+
+// UnmarshalStruct populates this LineString from a strongly-typed GeoJSONLineString.
+func (lineString *LineString) UnmarshalStruct(data GeoJSONLineString) error {
+
+	const location = "geo.LineString.UnmarshalStruct"
+
+	// Validate the "type" property
+	if data.Type != PropertyTypeLineString {
+		return derp.Internal(location, "Invalid GeoJSON. Type must be 'LineString'", data.Type)
+	}
+
+	// Validate the "coordinates" property, which must exist (but may be empty)
+	if data.Coordinates == nil {
+		return derp.Internal(location, "Invalid GeoJSON. Coordinates are required")
+	}
+
+	// Initialize variable / clear existing values
+	lineString.Coordinates = make(sliceof.Object[Position], len(data.Coordinates))
+
+	// Copy/translate coordinates into Position
+	for index, coordinate := range data.Coordinates {
+		if err := lineString.Coordinates[index].UnmarshalSlice(coordinate); err != nil {
+			return derp.Internal(location, "Invalid coordinate at index", index, coordinate)
+		}
+	}
+
+	return nil
+}
+
+// UnmarshalJSON is a custom json.Unmarshaller that parses a GeoJSON
+// object into this LineString object.
+func (lineString *LineString) UnmarshalJSON(data []byte) error {
+
+	const location = "geo.LineString.UnmarshalJSON"
+
+	// Unmarshall JSON into an intermediate object
+	intermediate := GeoJSONLineString{}
+
+	if err := json.Unmarshal(data, &intermediate); err != nil {
+		return derp.Wrap(err, location, "Unable to unmarshal original JSON", string(data))
+	}
+
+	// Unmarshal from intermediate object into this LineString
+	if err := lineString.UnmarshalStruct(intermediate); err != nil {
+		return derp.Wrap(err, location, "Unable to unmarshal from struct", intermediate)
+	}
+
+	return nil
+}
+
+// UnmarshalBSON is a custom BSON unmarshaller that deserializes
+// a GeoJSON object into this LineString structure.
+func (lineString *LineString) UnmarshalBSON(data []byte) error {
+
+	const location = "geo.LineString.UnmarshalBSON"
+
+	// Unmarshall BSON into an intermediate object
+	intermediate := GeoJSONLineString{}
+
+	if err := bson.Unmarshal(data, &intermediate); err != nil {
+		return derp.Wrap(err, location, "Unable to unmarshal original BSON", string(data))
+	}
+
+	// Unmarshal from intermediate object into this LineString
+	if err := lineString.UnmarshalStruct(intermediate); err != nil {
+		return derp.Wrap(err, location, "Unable to unmarshal from struct", intermediate)
+	}
+
+	return nil
+}
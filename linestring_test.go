@@ -0,0 +1,119 @@
+package geo
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestLineString_Zeroer(t *testing.T) {
+	require.True(t, NewLineString().IsZero())
+	require.False(t, NewLineString().NotZero())
+
+	require.False(t, NewLineString(NewPosition(0, 0)).IsZero())
+	require.True(t, NewLineString(NewPosition(0, 0)).NotZero())
+}
+
+func TestLineString_String(t *testing.T) {
+
+	require.Equal(t, "", NewLineString().String())
+
+	lineString := NewLineString(
+		NewPosition(1, 2),
+		NewPosition(3, 4),
+	)
+	require.Equal(t, "1,2,3,4", lineString.String())
+}
+
+func TestLineString_GeoJSON(t *testing.T) {
+
+	lineString := NewLineString(
+		NewPosition(1, 2),
+		NewPosition(3, 4),
+	)
+
+	result := lineString.GeoJSON()
+	require.Equal(t, PropertyTypeLineString, result[PropertyType])
+	require.Equal(t, [][]float64{{1, 2}, {3, 4}}, result[PropertyCoordinates])
+}
+
+func TestLineString_UnmarshalStruct_Errors(t *testing.T) {
+
+	lineString := LineString{}
+
+	// Wrong GeoJSON type
+	require.NotNil(t, lineString.UnmarshalStruct(GeoJSONLineString{
+		Type:        PropertyTypePolygon,
+		Coordinates: [][]float64{{1, 2}},
+	}))
+
+	// A coordinate has an invalid length
+	require.NotNil(t, lineString.UnmarshalStruct(GeoJSONLineString{
+		Type:        PropertyTypeLineString,
+		Coordinates: [][]float64{{1}},
+	}))
+
+	// Coordinates are missing
+	require.NotNil(t, lineString.UnmarshalStruct(GeoJSONLineString{
+		Type: PropertyTypeLineString,
+	}))
+	require.NotNil(t, lineString.UnmarshalJSON([]byte(`{"type":"LineString"}`)))
+
+	// Empty coordinates are allowed
+	require.Nil(t, lineString.UnmarshalJSON([]byte(`{"type":"LineString","coordinates":[]}`)))
+}
+
+func TestLineString_UnmarshalJSON_Errors(t *testing.T) {
+
+	lineString := LineString{}
+	require.NotNil(t, lineString.UnmarshalJSON([]byte("not json")))
+	require.NotNil(t, lineString.UnmarshalJSON([]byte(`{"type":"Point","coordinates":[[1,2]]}`)))
+}
+
+func TestLineString_UnmarshalBSON_Error(t *testing.T) {
+
+	lineString := LineString{}
+	require.NotNil(t, lineString.UnmarshalBSON([]byte("not bson")))
+}
+
+func TestLineString_JSON(t *testing.T) {
+
+	l1 := NewLineString(
+		NewPosition(1, 2),
+		NewPositionWithAltitude(3, 4, 5),
+	)
+
+	data, err1 := json.Marshal(l1)
+	require.Nil(t, err1)
+	require.Equal(t, `{"type":"LineString","coordinates":[[1,2],[3,4,5]]}`, string(data))
+
+	l2 := LineString{}
+	err2 := json.Unmarshal(data, &l2)
+	require.Nil(t, err2)
+	require.Equal(t, l1, l2)
+}
+
+func TestLineString_JSON_OmitZero(t *testing.T) {
+
+	data, err := json.Marshal(NewLineString())
+	require.Nil(t, err)
+	require.Equal(t, "null", string(data))
+}
+
+func TestLineString_BSON(t *testing.T) {
+
+	l1 := NewLineString(
+		NewPosition(1, 2),
+		NewPosition(3, 4),
+	)
+
+	data, err1 := bson.Marshal(l1)
+	require.Nil(t, err1)
+
+	l2 := LineString{}
+	err2 := bson.Unmarshal(data, &l2)
+	require.Nil(t, err2)
+	require.Equal(t, l1, l2)
+}
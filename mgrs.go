@@ -0,0 +1,274 @@
+package geo
+
+import (
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/benpate/derp"
+)
+
+// MGRS / USNG precisions, in digits per axis. Each additional digit makes
+// the referenced grid cell ten times smaller.
+const (
+	// MGRSPrecision100km identifies a 100km grid square only ("18TWL")
+	MGRSPrecision100km = 0
+
+	// MGRSPrecision10km identifies a 10km cell ("18TWL84")
+	MGRSPrecision10km = 1
+
+	// MGRSPrecision1km identifies a 1km cell ("18TWL8545")
+	MGRSPrecision1km = 2
+
+	// MGRSPrecision100m identifies a 100m cell ("18TWL856452")
+	MGRSPrecision100m = 3
+
+	// MGRSPrecision10m identifies a 10m cell ("18TWL85634527")
+	MGRSPrecision10m = 4
+
+	// MGRSPrecision1m identifies a 1m cell ("18TWL8563245271")
+	MGRSPrecision1m = 5
+)
+
+// Letters used by the MGRS grid. "I" and "O" are never used.
+const (
+	mgrsBands   = "CDEFGHJKLMNPQRSTUVWX"
+	mgrsRows    = "ABCDEFGHJKLMNPQRSTUV"
+	mgrsSquare  = 100000.0
+	mgrsRowSpan = 2000000.0
+)
+
+// mgrsColumns are the 100km column letters, which repeat every three zones
+var mgrsColumns = [3]string{"ABCDEFGH", "JKLMNPQR", "STUVWXYZ"}
+
+// mgrsPattern matches a compact MGRS reference (after spaces are removed)
+var mgrsPattern = regexp.MustCompile(`^([0-9]{1,2})([A-Z])([A-Z])([A-Z])([0-9]*)$`)
+
+// MGRS returns this Position as a Military Grid Reference System string, such as
+// "18TWL8563245271". Precision is the number of digits per axis, from
+// MGRSPrecision100km (0) to MGRSPrecision1m (5). Digits are truncated (not rounded)
+// so that the reference always names the cell that contains this Position.
+func (position Position) MGRS(precision int) (string, error) {
+
+	const location = "geo.Position.MGRS"
+
+	zoneBand, square, easting, northing, err := position.mgrsParts(precision)
+
+	if err != nil {
+		return "", derp.Wrap(err, location, "Unable to calculate MGRS")
+	}
+
+	return zoneBand + square + easting + northing, nil
+}
+
+// USNG returns this Position as a United States National Grid string, which is
+// MGRS written with spaces between its parts, such as "18T WL 85632 45271".
+func (position Position) USNG(precision int) (string, error) {
+
+	const location = "geo.Position.USNG"
+
+	zoneBand, square, easting, northing, err := position.mgrsParts(precision)
+
+	if err != nil {
+		return "", derp.Wrap(err, location, "Unable to calculate USNG")
+	}
+
+	if precision == MGRSPrecision100km {
+		return zoneBand + " " + square, nil
+	}
+
+	return zoneBand + " " + square + " " + easting + " " + northing, nil
+}
+
+// mgrsParts calculates the zone/band, 100km square, and numeric parts of an MGRS reference
+func (position Position) mgrsParts(precision int) (zoneBand string, square string, easting string, northing string, err error) {
+
+	const location = "geo.Position.mgrsParts"
+
+	if (precision < MGRSPrecision100km) || (precision > MGRSPrecision1m) {
+		return "", "", "", "", derp.Internal(location, "MGRS precision must be between 0 and 5", precision)
+	}
+
+	utm, err := position.UTM()
+
+	if err != nil {
+		return "", "", "", "", derp.Wrap(err, location, "Unable to convert to UTM", position)
+	}
+
+	// Latitude band (8° tall, except for the 12° "X" band)
+	band := mgrsBands[mgrsBandIndex(position.Latitude)]
+
+	// 100km square letters
+	column := int(math.Floor(utm.Easting / mgrsSquare))
+	row := int(math.Floor(utm.Northing/mgrsSquare)) % len(mgrsRows)
+
+	if utm.Zone%2 == 0 {
+		row = (row + 5) % len(mgrsRows)
+	}
+
+	// Truncate the position within the square to the requested precision
+	cellSize := math.Pow10(5 - precision)
+	eastingDigits := int(math.Floor(math.Mod(utm.Easting, mgrsSquare) / cellSize))
+	northingDigits := int(math.Floor(math.Mod(utm.Northing, mgrsSquare) / cellSize))
+
+	zoneBand = strconv.Itoa(utm.Zone) + string(band)
+	square = string(mgrsColumns[(utm.Zone-1)%3][column-1]) + string(mgrsRows[row])
+
+	return zoneBand, square, padDigits(eastingDigits, precision), padDigits(northingDigits, precision), nil
+}
+
+// ParseMGRS parses a Military Grid Reference System (or USNG) string, such as
+// "18TWL8563245271" or "18T WL 85632 45271", and returns the center of the
+// grid cell that it names. It returns an error if the string is malformed,
+// or if the 100km grid square does not exist in the given zone and latitude band.
+func ParseMGRS(value string) (Position, error) {
+
+	const location = "geo.ParseMGRS"
+
+	compact := strings.ToUpper(strings.Join(strings.Fields(value), ""))
+	matches := mgrsPattern.FindStringSubmatch(compact)
+
+	if matches == nil {
+		return Position{}, derp.Internal(location, "MGRS must be a zone, band, 100km square, and an even number of digits", value)
+	}
+
+	zone, _ := strconv.Atoi(matches[1])
+
+	if (zone < 1) || (zone > 60) {
+		return Position{}, derp.Internal(location, "MGRS zone must be between 1 and 60", value)
+	}
+
+	bandIndex := strings.Index(mgrsBands, matches[2])
+
+	if bandIndex < 0 {
+		return Position{}, derp.Internal(location, "Invalid MGRS latitude band", value, matches[2])
+	}
+
+	column := strings.Index(mgrsColumns[(zone-1)%3], matches[3])
+
+	if column < 0 {
+		return Position{}, derp.Internal(location, "Invalid 100km column letter for this zone", value, matches[3])
+	}
+
+	row := strings.Index(mgrsRows, matches[4])
+
+	if row < 0 {
+		return Position{}, derp.Internal(location, "Invalid 100km row letter", value, matches[4])
+	}
+
+	digits := matches[5]
+
+	if (len(digits) % 2) != 0 {
+		return Position{}, derp.Internal(location, "MGRS must have the same number of easting and northing digits", value)
+	}
+
+	if len(digits) > 2*MGRSPrecision1m {
+		return Position{}, derp.Internal(location, "MGRS cannot be more precise than 1 meter", value)
+	}
+
+	// Locate the south-west corner of the cell within the 100km square
+	precision := len(digits) / 2
+	cellSize := math.Pow10(5 - precision)
+	eastingDigits, _ := strconv.Atoi("0" + digits[:precision])
+	northingDigits, _ := strconv.Atoi("0" + digits[precision:])
+
+	if zone%2 == 0 {
+		row = (row - 5 + len(mgrsRows)) % len(mgrsRows)
+	}
+
+	hemisphere := UTMHemisphereNorth
+
+	if bandIndex < strings.Index(mgrsBands, "N") {
+		hemisphere = UTMHemisphereSouth
+	}
+
+	utm := UTM{
+		Zone:       zone,
+		Hemisphere: hemisphere,
+		Easting:    float64(column+1)*mgrsSquare + float64(eastingDigits)*cellSize,
+		Northing:   float64(row)*mgrsSquare + float64(northingDigits)*cellSize,
+	}
+
+	// Row letters repeat every 2,000km, so add 2,000km until we reach the latitude band
+	bandLow, bandHigh := mgrsBandRange(bandIndex)
+	bandNorthing := mgrsNorthing(bandLow)
+
+	for utm.Northing+cellSize < bandNorthing-mgrsSquare {
+		utm.Northing += mgrsRowSpan
+	}
+
+	// Confirm that the cell actually overlaps the latitude band
+	southWest, err := utm.Position()
+
+	if err != nil {
+		return Position{}, derp.Wrap(err, location, "Grid square is outside of the UTM zone", value)
+	}
+
+	northEast, err := UTM{Zone: zone, Hemisphere: hemisphere, Easting: utm.Easting + cellSize, Northing: utm.Northing + cellSize}.Position()
+
+	if err != nil {
+		return Position{}, derp.Wrap(err, location, "Grid square is outside of the UTM zone", value)
+	}
+
+	if (northEast.Latitude < bandLow) || (southWest.Latitude > bandHigh) {
+		return Position{}, derp.Internal(location, "100km grid square does not exist in this latitude band", value)
+	}
+
+	// Return the center of the cell, which is always safely inside of it
+	center, err := UTM{Zone: zone, Hemisphere: hemisphere, Easting: utm.Easting + cellSize/2, Northing: utm.Northing + cellSize/2}.Position()
+
+	if err != nil {
+		return Position{}, derp.Wrap(err, location, "Grid square is outside of the UTM zone", value)
+	}
+
+	return center, nil
+}
+
+// mgrsBandIndex returns the index (into mgrsBands) of the latitude band containing a latitude
+func mgrsBandIndex(latitude float64) int {
+	return min(int(math.Floor((latitude-utmMinimumLatitude)/8)), len(mgrsBands)-1)
+}
+
+// mgrsBandRange returns the southern and northern latitudes of a latitude band
+func mgrsBandRange(bandIndex int) (float64, float64) {
+
+	low := utmMinimumLatitude + float64(bandIndex*8)
+
+	// The "X" band is 12° tall, to reach 84°N
+	if bandIndex == len(mgrsBands)-1 {
+		return low, utmMaximumLatitude
+	}
+
+	return low, low + 8
+}
+
+// mgrsNorthing returns the (false) northing of a latitude on the central meridian of any zone
+func mgrsNorthing(latitude float64) float64 {
+
+	// Positions on the central meridian of any zone are always valid
+	projected, _ := utmTransverseMercator(1).Forward(NewPosition(utmCentralMeridian(1), latitude))
+	northing := projected.Latitude
+
+	if latitude < 0 {
+		northing += utmFalseNorthingSouth
+	}
+
+	return northing
+}
+
+// padDigits writes a non-negative integer with leading zeros to a fixed width
+func padDigits(value int, width int) string {
+
+	if width == 0 {
+		return ""
+	}
+
+	result := strconv.Itoa(value)
+
+	if len(result) < width {
+		result = strings.Repeat("0", width-len(result)) + result
+	}
+
+	return result
+}
@@ -0,0 +1,133 @@
+package geo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPosition_MGRS(t *testing.T) {
+
+	// Eiffel Tower, at every precision
+	position := NewPosition(2.2945, 48.8582)
+
+	check := func(precision int, expected string) {
+		result, err := position.MGRS(precision)
+		require.Nil(t, err)
+		require.Equal(t, expected, result)
+	}
+
+	check(MGRSPrecision100km, "31UDQ")
+	check(MGRSPrecision10km, "31UDQ41")
+	check(MGRSPrecision1km, "31UDQ4811")
+	check(MGRSPrecision100m, "31UDQ482119")
+	check(MGRSPrecision10m, "31UDQ48251193")
+	check(MGRSPrecision1m, "31UDQ4825111932")
+}
+
+func TestPosition_MGRS_Errors(t *testing.T) {
+
+	_, err := NewPosition(0, 0).MGRS(6)
+	require.NotNil(t, err)
+
+	_, err = NewPosition(0, 0).MGRS(-1)
+	require.NotNil(t, err)
+
+	_, err = NewPosition(0, 89).MGRS(MGRSPrecision1m)
+	require.NotNil(t, err)
+}
+
+func TestPosition_USNG(t *testing.T) {
+
+	position := NewPosition(2.2945, 48.8582)
+
+	result, err := position.USNG(MGRSPrecision1m)
+	require.Nil(t, err)
+	require.Equal(t, "31U DQ 48251 11932", result)
+
+	result, err = position.USNG(MGRSPrecision100km)
+	require.Nil(t, err)
+	require.Equal(t, "31U DQ", result)
+
+	_, err = position.USNG(9)
+	require.NotNil(t, err)
+}
+
+func TestParseMGRS(t *testing.T) {
+
+	// The center of the 1m cell
+	result, err := ParseMGRS("31UDQ4825111932")
+	require.Nil(t, err)
+	require.InDelta(t, 2.2945, result.Longitude, 0.00001)
+	require.InDelta(t, 48.8582, result.Latitude, 0.00001)
+
+	// The center of the 100km square
+	square, err := ParseMGRS("31UDQ")
+	require.Nil(t, err)
+	utm, err := square.UTM()
+	require.Nil(t, err)
+	require.InDelta(t, 450000, utm.Easting, 0.001)
+	require.InDelta(t, 5450000, utm.Northing, 0.001)
+
+	// USNG spacing and lowercase are accepted
+	spaced, err := ParseMGRS("31u dq 48251 11932")
+	require.Nil(t, err)
+	require.Equal(t, result, spaced)
+}
+
+func TestParseMGRS_RoundTrip(t *testing.T) {
+
+	// Positions across both hemispheres, even and odd zones, and the zone exceptions
+	for _, position := range []Position{
+		NewPosition(2.2945, 48.8582),
+		NewPosition(-79.9822, 40.4461),
+		NewPosition(-77.0352, 38.8895),
+		NewPosition(151.2153, -33.8568),
+		NewPosition(-70.6693, -33.4489),
+		NewPosition(18.4241, -33.9249),
+		NewPosition(5, 60),
+		NewPosition(15.6, 78.2),
+		NewPosition(-43.1729, -22.9068),
+		NewPosition(0.5, 0.0001),
+		NewPosition(0.5, -0.0001),
+		NewPosition(99, 83),
+		NewPosition(-99, -79),
+	} {
+		// 100km squares can hang over the edge of their zone (whose center may then
+		// be in the next zone) so the round-trip starts at 10km precision.
+		for precision := MGRSPrecision10km; precision <= MGRSPrecision1m; precision++ {
+
+			mgrs, err := position.MGRS(precision)
+			require.Nil(t, err)
+
+			result, err := ParseMGRS(mgrs)
+			require.Nil(t, err, mgrs)
+
+			// The center of the parsed cell re-encodes to the same reference
+			encoded, err := result.MGRS(precision)
+			require.Nil(t, err)
+			require.Equal(t, mgrs, encoded, position.String())
+		}
+	}
+}
+
+func TestParseMGRS_Errors(t *testing.T) {
+
+	checkError := func(value string) {
+		_, err := ParseMGRS(value)
+		require.NotNil(t, err, value)
+	}
+
+	checkError("")
+	checkError("hello")
+	checkError("31UDQ482511193")    // uneven digits
+	checkError("31UDQ482511193212") // more precise than 1m
+	checkError("0UDQ")              // zone too small
+	checkError("61UDQ")             // zone too large
+	checkError("31ADQ")             // invalid band
+	checkError("31IDQ")             // "I" is never used
+	checkError("31UJQ")             // column "J" is not in zone 31's set
+	checkError("31UDW")             // invalid row letter
+	checkError("31UDL")             // this square is not in band U
+	checkError("31NDQ")             // nor is this one in band N
+}
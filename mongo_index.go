@@ -0,0 +1,30 @@
+package geo
+
+import (
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// IndexType2dSphere is the MongoDB index type for GeoJSON geometries on a sphere
+const IndexType2dSphere = "2dsphere"
+
+// IndexModel2dSphere returns the IndexModel for a 2dsphere index on a field that
+// contains a Point, LineString, or Polygon. The index is named "<field>_2dsphere",
+// which matches the name that MongoDB would generate for it.
+// https://www.mongodb.com/docs/manual/core/indexes/index-types/geospatial/2dsphere/
+func IndexModel2dSphere(field string) mongo.IndexModel {
+	return mongo.IndexModel{
+		Keys:    bson.D{{Key: field, Value: IndexType2dSphere}},
+		Options: options.Index().SetName(field + "_" + IndexType2dSphere),
+	}
+}
+
+// IndexModel2dSphereCompound returns the IndexModel for a compound index that
+// begins with a 2dsphere key on `field`, followed by the additional `keys`
+// (such as bson.D{{Key: "category", Value: 1}}). MongoDB generates the index name.
+func IndexModel2dSphereCompound(field string, keys bson.D) mongo.IndexModel {
+	return mongo.IndexModel{
+		Keys: append(bson.D{{Key: field, Value: IndexType2dSphere}}, keys...),
+	}
+}
@@ -0,0 +1,27 @@
+package geo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestIndexModel2dSphere(t *testing.T) {
+
+	index := IndexModel2dSphere("location")
+	require.Equal(t, bson.D{{Key: "location", Value: "2dsphere"}}, index.Keys)
+	require.NotNil(t, index.Options)
+	require.Equal(t, "location_2dsphere", *index.Options.Name)
+}
+
+func TestIndexModel2dSphereCompound(t *testing.T) {
+
+	index := IndexModel2dSphereCompound("location", bson.D{{Key: "category", Value: 1}, {Key: "name", Value: -1}})
+	require.Equal(t, bson.D{
+		{Key: "location", Value: "2dsphere"},
+		{Key: "category", Value: 1},
+		{Key: "name", Value: -1},
+	}, index.Keys)
+	require.Nil(t, index.Options)
+}
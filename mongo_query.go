@@ -0,0 +1,187 @@
+package geo
+
+import (
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// MongoEarthRadius is the equatorial radius (in meters) that MongoDB uses to
+// convert distances into the radians expected by legacy spherical queries.
+// https://www.mongodb.com/docs/manual/tutorial/calculate-distances-using-spherical-geometry-with-2d-geospatial-indexes/
+const MongoEarthRadius = 6378100.0
+
+// QueryNear returns a filter that sorts documents by distance from a Point, nearest
+// first, using a $near query on a 2dsphere index. Distances are in meters, and
+// zero values are omitted from the query.
+// https://www.mongodb.com/docs/manual/reference/operator/query/near/
+func QueryNear(field string, point Point, minDistance float64, maxDistance float64) bson.D {
+	return queryNear("$near", field, point, minDistance, maxDistance)
+}
+
+// QueryNearSphere returns a filter that sorts documents by spherical distance from a
+// Point, nearest first, using a $nearSphere query. Distances are in meters, and zero
+// values are omitted from the query.
+// https://www.mongodb.com/docs/manual/reference/operator/query/nearSphere/
+func QueryNearSphere(field string, point Point, minDistance float64, maxDistance float64) bson.D {
+	return queryNear("$nearSphere", field, point, minDistance, maxDistance)
+}
+
+// QueryGeoWithin returns a filter that matches documents whose geometry lies
+// entirely inside of another geometry (which should be a Polygon). A nil geometry
+// is written as null, so MongoDB rejects the query.
+// https://www.mongodb.com/docs/manual/reference/operator/query/geoWithin/
+func QueryGeoWithin(field string, geometry Geometry) bson.D {
+	return bson.D{{Key: field, Value: bson.D{
+		{Key: "$geoWithin", Value: bson.D{
+			{Key: "$geometry", Value: geometryDocument(geometry)},
+		}},
+	}}}
+}
+
+// QueryGeoWithinBox returns a filter that matches documents inside of a BoundingBox,
+// using the legacy (flat, 2d index) $box operator.
+// https://www.mongodb.com/docs/manual/reference/operator/query/box/
+func QueryGeoWithinBox(field string, box BoundingBox) bson.D {
+	return bson.D{{Key: field, Value: bson.D{
+		{Key: "$geoWithin", Value: bson.D{
+			{Key: "$box", Value: bson.A{
+				bson.A{box.West, box.South},
+				bson.A{box.East, box.North},
+			}},
+		}},
+	}}}
+}
+
+// QueryGeoWithinPolygon returns a filter that matches documents inside of a Polygon,
+// using the legacy (flat, 2d index) $polygon operator.
+// https://www.mongodb.com/docs/manual/reference/operator/query/polygon/
+func QueryGeoWithinPolygon(field string, polygon Polygon) bson.D {
+
+	coordinates := make(bson.A, len(polygon.Coordinates))
+
+	for index, position := range polygon.Coordinates {
+		coordinates[index] = bson.A{position.Longitude, position.Latitude}
+	}
+
+	return bson.D{{Key: field, Value: bson.D{
+		{Key: "$geoWithin", Value: bson.D{
+			{Key: "$polygon", Value: coordinates},
+		}},
+	}}}
+}
+
+// QueryGeoWithinCenterSphere returns a filter that matches documents within `radius`
+// meters of a Position, using the $centerSphere operator. The radius is converted
+// into radians using MongoEarthRadius.
+// https://www.mongodb.com/docs/manual/reference/operator/query/centerSphere/
+func QueryGeoWithinCenterSphere(field string, center Position, radius float64) bson.D {
+	return bson.D{{Key: field, Value: bson.D{
+		{Key: "$geoWithin", Value: bson.D{
+			{Key: "$centerSphere", Value: bson.A{
+				bson.A{center.Longitude, center.Latitude},
+				radius / MongoEarthRadius,
+			}},
+		}},
+	}}}
+}
+
+// QueryGeoIntersects returns a filter that matches documents whose geometry
+// intersects another geometry. A nil geometry is written as null, so MongoDB
+// rejects the query.
+// https://www.mongodb.com/docs/manual/reference/operator/query/geoIntersects/
+func QueryGeoIntersects(field string, geometry Geometry) bson.D {
+	return bson.D{{Key: field, Value: bson.D{
+		{Key: "$geoIntersects", Value: bson.D{
+			{Key: "$geometry", Value: geometryDocument(geometry)},
+		}},
+	}}}
+}
+
+// GeoNearStage describes a $geoNear aggregation stage, which must be the first
+// stage in a pipeline. Zero values are omitted from the stage.
+// https://www.mongodb.com/docs/manual/reference/operator/aggregation/geoNear/
+type GeoNearStage struct {
+	Near               Point   // Point to measure distances from
+	DistanceField      string  // Output field that receives the calculated distance
+	MinDistance        float64 // Minimum distance, in meters
+	MaxDistance        float64 // Maximum distance, in meters
+	Query              bson.D  // Additional filter applied to documents
+	Key                string  // Indexed field to use, when the collection has more than one geospatial index
+	IncludeLocs        string  // Output field that receives the location used to calculate the distance
+	DistanceMultiplier float64 // Factor applied to every calculated distance (such as 0.001 for kilometers)
+}
+
+// BSON returns this stage as a bson.D, ready to include in an aggregation pipeline
+func (stage GeoNearStage) BSON() bson.D {
+
+	result := bson.D{
+		{Key: "near", Value: geometryDocument(stage.Near)},
+		{Key: "distanceField", Value: stage.DistanceField},
+		{Key: "spherical", Value: true},
+	}
+
+	if stage.MinDistance != 0 {
+		result = append(result, bson.E{Key: "minDistance", Value: stage.MinDistance})
+	}
+
+	if stage.MaxDistance != 0 {
+		result = append(result, bson.E{Key: "maxDistance", Value: stage.MaxDistance})
+	}
+
+	if len(stage.Query) > 0 {
+		result = append(result, bson.E{Key: "query", Value: stage.Query})
+	}
+
+	if stage.Key != "" {
+		result = append(result, bson.E{Key: "key", Value: stage.Key})
+	}
+
+	if stage.IncludeLocs != "" {
+		result = append(result, bson.E{Key: "includeLocs", Value: stage.IncludeLocs})
+	}
+
+	if stage.DistanceMultiplier != 0 {
+		result = append(result, bson.E{Key: "distanceMultiplier", Value: stage.DistanceMultiplier})
+	}
+
+	return bson.D{{Key: "$geoNear", Value: result}}
+}
+
+// queryNear builds the shared structure of $near and $nearSphere queries
+func queryNear(operator string, field string, point Point, minDistance float64, maxDistance float64) bson.D {
+
+	query := bson.D{
+		{Key: "$geometry", Value: geometryDocument(point)},
+	}
+
+	if minDistance != 0 {
+		query = append(query, bson.E{Key: "$minDistance", Value: minDistance})
+	}
+
+	if maxDistance != 0 {
+		query = append(query, bson.E{Key: "$maxDistance", Value: maxDistance})
+	}
+
+	return bson.D{{Key: field, Value: bson.D{
+		{Key: operator, Value: query},
+	}}}
+}
+
+// geometryDocument returns a geometry as an ordered GeoJSON document, so that
+// the "type" is always written before the "coordinates". A nil geometry is
+// written as null, which MongoDB rejects with a clear error instead of matching
+// nothing (or everything).
+func geometryDocument(geometry Geometry) bson.D {
+
+	geometry = dereferenceGeometry(geometry)
+
+	if geometry == nil {
+		return nil
+	}
+
+	geoJSON := geometry.GeoJSON()
+
+	return bson.D{
+		{Key: PropertyType, Value: geoJSON[PropertyType]},
+		{Key: PropertyCoordinates, Value: geoJSON[PropertyCoordinates]},
+	}
+}
@@ -0,0 +1,211 @@
+package geo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// requireBSON confirms that a bson.D marshals to exactly the same bytes as the expected document
+func requireBSON(t *testing.T, expected bson.D, actual bson.D) {
+	expectedBytes, err := bson.Marshal(expected)
+	require.Nil(t, err)
+
+	actualBytes, err := bson.Marshal(actual)
+	require.Nil(t, err)
+
+	require.Equal(t, bson.Raw(expectedBytes).String(), bson.Raw(actualBytes).String())
+	require.Equal(t, expectedBytes, actualBytes)
+}
+
+func TestQueryNear(t *testing.T) {
+
+	query := QueryNear("location", NewPoint(-73.9667, 40.78), 10, 5000)
+
+	requireBSON(t, bson.D{{Key: "location", Value: bson.D{
+		{Key: "$near", Value: bson.D{
+			{Key: "$geometry", Value: bson.D{
+				{Key: "type", Value: "Point"},
+				{Key: "coordinates", Value: bson.A{-73.9667, 40.78}},
+			}},
+			{Key: "$minDistance", Value: 10.0},
+			{Key: "$maxDistance", Value: 5000.0},
+		}},
+	}}}, query)
+}
+
+func TestQueryNear_NoDistance(t *testing.T) {
+
+	query := QueryNear("location", NewPoint(1, 2), 0, 0)
+
+	requireBSON(t, bson.D{{Key: "location", Value: bson.D{
+		{Key: "$near", Value: bson.D{
+			{Key: "$geometry", Value: bson.D{
+				{Key: "type", Value: "Point"},
+				{Key: "coordinates", Value: bson.A{1.0, 2.0}},
+			}},
+		}},
+	}}}, query)
+}
+
+func TestQueryNearSphere(t *testing.T) {
+
+	query := QueryNearSphere("location", NewPoint(-73.9667, 40.78), 0, 1000)
+
+	requireBSON(t, bson.D{{Key: "location", Value: bson.D{
+		{Key: "$nearSphere", Value: bson.D{
+			{Key: "$geometry", Value: bson.D{
+				{Key: "type", Value: "Point"},
+				{Key: "coordinates", Value: bson.A{-73.9667, 40.78}},
+			}},
+			{Key: "$maxDistance", Value: 1000.0},
+		}},
+	}}}, query)
+}
+
+func TestQueryGeoWithin(t *testing.T) {
+
+	polygon := NewPolygon(NewPosition(0, 0), NewPosition(3, 6), NewPosition(6, 1), NewPosition(0, 0))
+	query := QueryGeoWithin("location", polygon)
+
+	requireBSON(t, bson.D{{Key: "location", Value: bson.D{
+		{Key: "$geoWithin", Value: bson.D{
+			{Key: "$geometry", Value: bson.D{
+				{Key: "type", Value: "Polygon"},
+				{Key: "coordinates", Value: bson.A{bson.A{
+					bson.A{0.0, 0.0}, bson.A{3.0, 6.0}, bson.A{6.0, 1.0}, bson.A{0.0, 0.0},
+				}}},
+			}},
+		}},
+	}}}, query)
+}
+
+func TestQueryGeometry_Nil(t *testing.T) {
+
+	// Nil geometries do not panic, and are written as null for MongoDB to reject
+	for _, geometry := range []Geometry{nil, (*Polygon)(nil), (*Point)(nil), (*LineString)(nil)} {
+
+		requireBSON(t, bson.D{{Key: "location", Value: bson.D{
+			{Key: "$geoWithin", Value: bson.D{
+				{Key: "$geometry", Value: nil},
+			}},
+		}}}, QueryGeoWithin("location", geometry))
+
+		requireBSON(t, bson.D{{Key: "location", Value: bson.D{
+			{Key: "$geoIntersects", Value: bson.D{
+				{Key: "$geometry", Value: nil},
+			}},
+		}}}, QueryGeoIntersects("location", geometry))
+	}
+
+	// Pointers are written like the values they point to
+	polygon := NewPolygon(NewPosition(0, 0), NewPosition(3, 6), NewPosition(6, 1), NewPosition(0, 0))
+	requireBSON(t, QueryGeoWithin("location", polygon), QueryGeoWithin("location", &polygon))
+}
+
+func TestQueryGeoWithinBox(t *testing.T) {
+
+	query := QueryGeoWithinBox("location", NewBoundingBox(-74.1, 40.6, -73.8, 40.9))
+
+	requireBSON(t, bson.D{{Key: "location", Value: bson.D{
+		{Key: "$geoWithin", Value: bson.D{
+			{Key: "$box", Value: bson.A{
+				bson.A{-74.1, 40.6},
+				bson.A{-73.8, 40.9},
+			}},
+		}},
+	}}}, query)
+}
+
+func TestQueryGeoWithinPolygon(t *testing.T) {
+
+	// Altitudes are dropped, because legacy coordinates are always 2D
+	polygon := NewPolygon(NewPosition(0, 0), NewPositionWithAltitude(3, 6, 100), NewPosition(6, 0))
+	query := QueryGeoWithinPolygon("location", polygon)
+
+	requireBSON(t, bson.D{{Key: "location", Value: bson.D{
+		{Key: "$geoWithin", Value: bson.D{
+			{Key: "$polygon", Value: bson.A{
+				bson.A{0.0, 0.0},
+				bson.A{3.0, 6.0},
+				bson.A{6.0, 0.0},
+			}},
+		}},
+	}}}, query)
+}
+
+func TestQueryGeoWithinCenterSphere(t *testing.T) {
+
+	query := QueryGeoWithinCenterSphere("location", NewPosition(-88, 30), 16093.44)
+
+	requireBSON(t, bson.D{{Key: "location", Value: bson.D{
+		{Key: "$geoWithin", Value: bson.D{
+			{Key: "$centerSphere", Value: bson.A{
+				bson.A{-88.0, 30.0},
+				16093.44 / 6378100.0,
+			}},
+		}},
+	}}}, query)
+}
+
+func TestQueryGeoIntersects(t *testing.T) {
+
+	lineString := NewLineString(NewPosition(1, 2), NewPosition(3, 4))
+	query := QueryGeoIntersects("route", lineString)
+
+	requireBSON(t, bson.D{{Key: "route", Value: bson.D{
+		{Key: "$geoIntersects", Value: bson.D{
+			{Key: "$geometry", Value: bson.D{
+				{Key: "type", Value: "LineString"},
+				{Key: "coordinates", Value: bson.A{bson.A{1.0, 2.0}, bson.A{3.0, 4.0}}},
+			}},
+		}},
+	}}}, query)
+}
+
+func TestGeoNearStage(t *testing.T) {
+
+	stage := GeoNearStage{
+		Near:               NewPoint(-73.99279, 40.719296),
+		DistanceField:      "dist.calculated",
+		MinDistance:        2,
+		MaxDistance:        2000,
+		Query:              bson.D{{Key: "category", Value: "Parks"}},
+		Key:                "location",
+		IncludeLocs:        "dist.location",
+		DistanceMultiplier: 0.001,
+	}
+
+	requireBSON(t, bson.D{{Key: "$geoNear", Value: bson.D{
+		{Key: "near", Value: bson.D{
+			{Key: "type", Value: "Point"},
+			{Key: "coordinates", Value: bson.A{-73.99279, 40.719296}},
+		}},
+		{Key: "distanceField", Value: "dist.calculated"},
+		{Key: "spherical", Value: true},
+		{Key: "minDistance", Value: 2.0},
+		{Key: "maxDistance", Value: 2000.0},
+		{Key: "query", Value: bson.D{{Key: "category", Value: "Parks"}}},
+		{Key: "key", Value: "location"},
+		{Key: "includeLocs", Value: "dist.location"},
+		{Key: "distanceMultiplier", Value: 0.001},
+	}}}, stage.BSON())
+}
+
+func TestGeoNearStage_Minimal(t *testing.T) {
+
+	stage := GeoNearStage{
+		Near:          NewPoint(1, 2),
+		DistanceField: "distance",
+	}
+
+	requireBSON(t, bson.D{{Key: "$geoNear", Value: bson.D{
+		{Key: "near", Value: bson.D{
+			{Key: "type", Value: "Point"},
+			{Key: "coordinates", Value: bson.A{1.0, 2.0}},
+		}},
+		{Key: "distanceField", Value: "distance"},
+		{Key: "spherical", Value: true},
+	}}}, stage.BSON())
+}
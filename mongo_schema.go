@@ -0,0 +1,84 @@
+package geo
+
+import (
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// JSONSchemaPosition returns a MongoDB $jsonSchema that matches a GeoJSON position
+// written by Position.MarshalBSONValue: an array of two or three numbers.
+// https://www.mongodb.com/docs/manual/reference/operator/query/jsonSchema/
+func JSONSchemaPosition() bson.D {
+	return bson.D{
+		{Key: "bsonType", Value: "array"},
+		{Key: "minItems", Value: 2},
+		{Key: "maxItems", Value: 3},
+		{Key: "items", Value: bson.D{{Key: "bsonType", Value: "number"}}},
+	}
+}
+
+// JSONSchemaPoint returns a MongoDB $jsonSchema that matches the GeoJSON object
+// written by Point.MarshalBSON
+func JSONSchemaPoint() bson.D {
+	return jsonSchemaGeometry(PropertyTypePoint, JSONSchemaPosition())
+}
+
+// JSONSchemaLineString returns a MongoDB $jsonSchema that matches the GeoJSON object
+// written by LineString.MarshalBSON
+func JSONSchemaLineString() bson.D {
+	return jsonSchemaGeometry(PropertyTypeLineString, bson.D{
+		{Key: "bsonType", Value: "array"},
+		{Key: "items", Value: JSONSchemaPosition()},
+	})
+}
+
+// JSONSchemaPolygon returns a MongoDB $jsonSchema that matches the GeoJSON object
+// written by Polygon.MarshalBSON, which always contains exactly one ring.
+func JSONSchemaPolygon() bson.D {
+	return jsonSchemaGeometry(PropertyTypePolygon, bson.D{
+		{Key: "bsonType", Value: "array"},
+		{Key: "minItems", Value: 1},
+		{Key: "maxItems", Value: 1},
+		{Key: "items", Value: bson.D{
+			{Key: "bsonType", Value: "array"},
+			{Key: "items", Value: JSONSchemaPosition()},
+		}},
+	})
+}
+
+// JSONSchemaValidator returns a collection validator ({"$jsonSchema": ...}) for use
+// with the "validator" option of createCollection or collMod. Each element of
+// `properties` maps a field name to its schema, such as
+// bson.E{Key: "location", Value: geo.JSONSchemaPoint()}. Fields may be missing
+// or null, but when present they must match their schema.
+// https://www.mongodb.com/docs/manual/core/schema-validation/
+func JSONSchemaValidator(properties ...bson.E) bson.D {
+
+	schemas := make(bson.D, len(properties))
+
+	for index, property := range properties {
+		schemas[index] = bson.E{Key: property.Key, Value: bson.D{
+			{Key: "oneOf", Value: bson.A{
+				bson.D{{Key: "bsonType", Value: "null"}},
+				property.Value,
+			}},
+		}}
+	}
+
+	return bson.D{{Key: "$jsonSchema", Value: bson.D{
+		{Key: "bsonType", Value: "object"},
+		{Key: "properties", Value: schemas},
+	}}}
+}
+
+// jsonSchemaGeometry returns the $jsonSchema for a GeoJSON geometry object,
+// with a fixed "type" and the given schema for its "coordinates"
+func jsonSchemaGeometry(geometryType string, coordinates bson.D) bson.D {
+	return bson.D{
+		{Key: "bsonType", Value: "object"},
+		{Key: "required", Value: bson.A{PropertyType, PropertyCoordinates}},
+		{Key: "properties", Value: bson.D{
+			{Key: PropertyType, Value: bson.D{{Key: "enum", Value: bson.A{geometryType}}}},
+			{Key: PropertyCoordinates, Value: coordinates},
+		}},
+	}
+}
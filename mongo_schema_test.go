@@ -0,0 +1,81 @@
+package geo
+
+import (
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestJSONSchemaPoint(t *testing.T) {
+
+	requireBSON(t, bson.D{
+		{Key: "bsonType", Value: "object"},
+		{Key: "required", Value: bson.A{"type", "coordinates"}},
+		{Key: "properties", Value: bson.D{
+			{Key: "type", Value: bson.D{{Key: "enum", Value: bson.A{"Point"}}}},
+			{Key: "coordinates", Value: bson.D{
+				{Key: "bsonType", Value: "array"},
+				{Key: "minItems", Value: 2},
+				{Key: "maxItems", Value: 3},
+				{Key: "items", Value: bson.D{{Key: "bsonType", Value: "number"}}},
+			}},
+		}},
+	}, JSONSchemaPoint())
+}
+
+func TestJSONSchemaLineString(t *testing.T) {
+
+	requireBSON(t, bson.D{
+		{Key: "bsonType", Value: "object"},
+		{Key: "required", Value: bson.A{"type", "coordinates"}},
+		{Key: "properties", Value: bson.D{
+			{Key: "type", Value: bson.D{{Key: "enum", Value: bson.A{"LineString"}}}},
+			{Key: "coordinates", Value: bson.D{
+				{Key: "bsonType", Value: "array"},
+				{Key: "items", Value: JSONSchemaPosition()},
+			}},
+		}},
+	}, JSONSchemaLineString())
+}
+
+func TestJSONSchemaPolygon(t *testing.T) {
+
+	requireBSON(t, bson.D{
+		{Key: "bsonType", Value: "object"},
+		{Key: "required", Value: bson.A{"type", "coordinates"}},
+		{Key: "properties", Value: bson.D{
+			{Key: "type", Value: bson.D{{Key: "enum", Value: bson.A{"Polygon"}}}},
+			{Key: "coordinates", Value: bson.D{
+				{Key: "bsonType", Value: "array"},
+				{Key: "minItems", Value: 1},
+				{Key: "maxItems", Value: 1},
+				{Key: "items", Value: bson.D{
+					{Key: "bsonType", Value: "array"},
+					{Key: "items", Value: JSONSchemaPosition()},
+				}},
+			}},
+		}},
+	}, JSONSchemaPolygon())
+}
+
+func TestJSONSchemaValidator(t *testing.T) {
+
+	validator := JSONSchemaValidator(
+		bson.E{Key: "location", Value: JSONSchemaPoint()},
+		bson.E{Key: "area", Value: JSONSchemaPolygon()},
+	)
+
+	requireBSON(t, bson.D{{Key: "$jsonSchema", Value: bson.D{
+		{Key: "bsonType", Value: "object"},
+		{Key: "properties", Value: bson.D{
+			{Key: "location", Value: bson.D{{Key: "oneOf", Value: bson.A{
+				bson.D{{Key: "bsonType", Value: "null"}},
+				JSONSchemaPoint(),
+			}}}},
+			{Key: "area", Value: bson.D{{Key: "oneOf", Value: bson.A{
+				bson.D{{Key: "bsonType", Value: "null"}},
+				JSONSchemaPolygon(),
+			}}}},
+		}},
+	}}}, validator)
+}
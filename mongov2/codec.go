@@ -0,0 +1,534 @@
+package mongov2
+
+import (
+	"errors"
+	"reflect"
+
+	"github.com/benpate/derp"
+	"github.com/benpate/geo"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// Reflected types that receive custom BSON codecs
+var (
+	typePosition   = reflect.TypeOf(geo.Position{})
+	typePoint      = reflect.TypeOf(geo.Point{})
+	typeLineString = reflect.TypeOf(geo.LineString{})
+	typePolygon    = reflect.TypeOf(geo.Polygon{})
+)
+
+// NewRegistry returns a copy of the default (v2) BSON registry, with codecs for
+// every geometry type in the geo package. Pass it to the MongoDB driver with
+// options.Client().SetRegistry()
+func NewRegistry() *bson.Registry {
+	registry := bson.NewRegistry()
+	RegisterCodecs(registry)
+	return registry
+}
+
+// RegisterCodecs adds ValueEncoders and ValueDecoders for geo.Position, geo.Point,
+// geo.LineString, and geo.Polygon to an existing (v2) registry. These codecs write
+// exactly the same GeoJSON as the v1 codecs in the geo package: zero-valued
+// Points, LineStrings, and Polygons are written as BSON null (or omitted entirely
+// by `omitzero` / `omitempty`), and null values decode into zero values.
+func RegisterCodecs(registry *bson.Registry) {
+
+	registry.RegisterTypeEncoder(typePosition, bson.ValueEncoderFunc(encodePositionValue))
+	registry.RegisterTypeEncoder(typePoint, bson.ValueEncoderFunc(encodePointValue))
+	registry.RegisterTypeEncoder(typeLineString, bson.ValueEncoderFunc(encodeLineStringValue))
+	registry.RegisterTypeEncoder(typePolygon, bson.ValueEncoderFunc(encodePolygonValue))
+
+	registry.RegisterTypeDecoder(typePosition, bson.ValueDecoderFunc(decodePositionValue))
+	registry.RegisterTypeDecoder(typePoint, bson.ValueDecoderFunc(decodePointValue))
+	registry.RegisterTypeDecoder(typeLineString, bson.ValueDecoderFunc(decodeLineStringValue))
+	registry.RegisterTypeDecoder(typePolygon, bson.ValueDecoderFunc(decodePolygonValue))
+}
+
+/******************************************
+ * Encoders
+ ******************************************/
+
+// encodePositionValue writes a Position as a GeoJSON coordinate array
+func encodePositionValue(_ bson.EncodeContext, writer bson.ValueWriter, value reflect.Value) error {
+
+	const location = "mongov2.encodePositionValue"
+
+	if value.Type() != typePosition {
+		return derp.Internal(location, "Value must be a Position", value.Type().String())
+	}
+
+	return writeBSONPosition(writer, value.Interface().(geo.Position))
+}
+
+// encodePointValue writes a Point as a GeoJSON object, or null if it is zero
+func encodePointValue(_ bson.EncodeContext, writer bson.ValueWriter, value reflect.Value) error {
+
+	const location = "mongov2.encodePointValue"
+
+	if value.Type() != typePoint {
+		return derp.Internal(location, "Value must be a Point", value.Type().String())
+	}
+
+	point := value.Interface().(geo.Point)
+
+	if point.IsZero() {
+		return writer.WriteNull()
+	}
+
+	return writeBSONGeometry(writer, geo.PropertyTypePoint, func(coordinates bson.ValueWriter) error {
+		return writeBSONPosition(coordinates, point.Position)
+	})
+}
+
+// encodeLineStringValue writes a LineString as a GeoJSON object, or null if it is zero
+func encodeLineStringValue(_ bson.EncodeContext, writer bson.ValueWriter, value reflect.Value) error {
+
+	const location = "mongov2.encodeLineStringValue"
+
+	if value.Type() != typeLineString {
+		return derp.Internal(location, "Value must be a LineString", value.Type().String())
+	}
+
+	lineString := value.Interface().(geo.LineString)
+
+	if lineString.IsZero() {
+		return writer.WriteNull()
+	}
+
+	return writeBSONGeometry(writer, geo.PropertyTypeLineString, func(coordinates bson.ValueWriter) error {
+		return writeBSONPositions(coordinates, lineString.Coordinates)
+	})
+}
+
+// encodePolygonValue writes a Polygon as a GeoJSON object, or null if it is zero
+func encodePolygonValue(_ bson.EncodeContext, writer bson.ValueWriter, value reflect.Value) error {
+
+	const location = "mongov2.encodePolygonValue"
+
+	if value.Type() != typePolygon {
+		return derp.Internal(location, "Value must be a Polygon", value.Type().String())
+	}
+
+	polygon := value.Interface().(geo.Polygon)
+
+	if polygon.IsZero() {
+		return writer.WriteNull()
+	}
+
+	return writeBSONGeometry(writer, geo.PropertyTypePolygon, func(coordinates bson.ValueWriter) error {
+
+		rings, err := coordinates.WriteArray()
+
+		if err != nil {
+			return err
+		}
+
+		ring, err := rings.WriteArrayElement()
+
+		if err != nil {
+			return err
+		}
+
+		if err := writeBSONPositions(ring, polygon.Coordinates); err != nil {
+			return err
+		}
+
+		return rings.WriteArrayEnd()
+	})
+}
+
+// writeBSONGeometry writes a GeoJSON object with a "type" and "coordinates",
+// using `writeCoordinates` to fill in the coordinates
+func writeBSONGeometry(writer bson.ValueWriter, geometryType string, writeCoordinates func(bson.ValueWriter) error) error {
+
+	const location = "mongov2.writeBSONGeometry"
+
+	document, err := writer.WriteDocument()
+
+	if err != nil {
+		return derp.Wrap(err, location, "Unable to write document")
+	}
+
+	element, err := document.WriteDocumentElement(geo.PropertyType)
+
+	if err != nil {
+		return derp.Wrap(err, location, "Unable to write type")
+	}
+
+	if err := element.WriteString(geometryType); err != nil {
+		return derp.Wrap(err, location, "Unable to write type")
+	}
+
+	element, err = document.WriteDocumentElement(geo.PropertyCoordinates)
+
+	if err != nil {
+		return derp.Wrap(err, location, "Unable to write coordinates")
+	}
+
+	if err := writeCoordinates(element); err != nil {
+		return derp.Wrap(err, location, "Unable to write coordinates")
+	}
+
+	return document.WriteDocumentEnd()
+}
+
+// writeBSONPositions writes a slice of Positions as an array of coordinate arrays
+func writeBSONPositions(writer bson.ValueWriter, positions []geo.Position) error {
+
+	array, err := writer.WriteArray()
+
+	if err != nil {
+		return err
+	}
+
+	for _, position := range positions {
+
+		element, err := array.WriteArrayElement()
+
+		if err != nil {
+			return err
+		}
+
+		if err := writeBSONPosition(element, position); err != nil {
+			return err
+		}
+	}
+
+	return array.WriteArrayEnd()
+}
+
+// writeBSONPosition writes a single Position as a coordinate array, matching MarshalSlice
+func writeBSONPosition(writer bson.ValueWriter, position geo.Position) error {
+
+	array, err := writer.WriteArray()
+
+	if err != nil {
+		return err
+	}
+
+	values := [3]float64{position.Longitude, position.Latitude, position.Altitude}
+	length := 2
+
+	if position.Altitude != 0 {
+		length = 3
+	}
+
+	for _, value := range values[:length] {
+
+		element, err := array.WriteArrayElement()
+
+		if err != nil {
+			return err
+		}
+
+		if err := element.WriteDouble(value); err != nil {
+			return err
+		}
+	}
+
+	return array.WriteArrayEnd()
+}
+
+/******************************************
+ * Decoders
+ ******************************************/
+
+// decodePositionValue reads a GeoJSON coordinate array into a Position
+func decodePositionValue(_ bson.DecodeContext, reader bson.ValueReader, value reflect.Value) error {
+
+	const location = "mongov2.decodePositionValue"
+
+	if !value.CanSet() || (value.Type() != typePosition) {
+		return derp.Internal(location, "Value must be a settable Position", value.Type().String())
+	}
+
+	if reader.Type() == bson.TypeNull {
+		value.Set(reflect.ValueOf(geo.Position{}))
+		return reader.ReadNull()
+	}
+
+	position, err := readBSONPosition(reader)
+
+	if err != nil {
+		return derp.Wrap(err, location, "Unable to read position")
+	}
+
+	value.Set(reflect.ValueOf(position))
+	return nil
+}
+
+// decodePointValue reads a GeoJSON object (or null) into a Point
+func decodePointValue(_ bson.DecodeContext, reader bson.ValueReader, value reflect.Value) error {
+
+	const location = "mongov2.decodePointValue"
+
+	if !value.CanSet() || (value.Type() != typePoint) {
+		return derp.Internal(location, "Value must be a settable Point", value.Type().String())
+	}
+
+	point := geo.Point{}
+
+	err := readBSONGeometry(reader, geo.PropertyTypePoint, func(coordinates bson.ValueReader) error {
+		var err error
+		point.Position, err = readBSONPosition(coordinates)
+		return err
+	})
+
+	if err != nil {
+		return derp.Wrap(err, location, "Unable to read Point")
+	}
+
+	value.Set(reflect.ValueOf(point))
+	return nil
+}
+
+// decodeLineStringValue reads a GeoJSON object (or null) into a LineString
+func decodeLineStringValue(_ bson.DecodeContext, reader bson.ValueReader, value reflect.Value) error {
+
+	const location = "mongov2.decodeLineStringValue"
+
+	if !value.CanSet() || (value.Type() != typeLineString) {
+		return derp.Internal(location, "Value must be a settable LineString", value.Type().String())
+	}
+
+	lineString := geo.LineString{}
+
+	err := readBSONGeometry(reader, geo.PropertyTypeLineString, func(coordinates bson.ValueReader) error {
+		var err error
+		lineString.Coordinates, err = readBSONPositions(coordinates)
+		return err
+	})
+
+	if err != nil {
+		return derp.Wrap(err, location, "Unable to read LineString")
+	}
+
+	value.Set(reflect.ValueOf(lineString))
+	return nil
+}
+
+// decodePolygonValue reads a GeoJSON object (or null) into a Polygon, which must
+// contain exactly one ring of coordinates
+func decodePolygonValue(_ bson.DecodeContext, reader bson.ValueReader, value reflect.Value) error {
+
+	const location = "mongov2.decodePolygonValue"
+
+	if !value.CanSet() || (value.Type() != typePolygon) {
+		return derp.Internal(location, "Value must be a settable Polygon", value.Type().String())
+	}
+
+	polygon := geo.Polygon{}
+
+	err := readBSONGeometry(reader, geo.PropertyTypePolygon, func(coordinates bson.ValueReader) error {
+
+		rings, err := coordinates.ReadArray()
+
+		if err != nil {
+			return err
+		}
+
+		count := 0
+
+		for {
+			ring, err := rings.ReadValue()
+
+			if errors.Is(err, bson.ErrEOA) {
+				break
+			}
+
+			if err != nil {
+				return err
+			}
+
+			if polygon.Coordinates, err = readBSONPositions(ring); err != nil {
+				return err
+			}
+
+			count++
+		}
+
+		if count != 1 {
+			return derp.Internal(location, "Coordinates length must be 1", count)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return derp.Wrap(err, location, "Unable to read Polygon")
+	}
+
+	value.Set(reflect.ValueOf(polygon))
+	return nil
+}
+
+// readBSONGeometry reads a GeoJSON object, confirming its "type" and using
+// `readCoordinates` to read its "coordinates". BSON null is read as an empty
+// geometry, and unknown properties are skipped. Both "type" and "coordinates"
+// are required.
+func readBSONGeometry(reader bson.ValueReader, geometryType string, readCoordinates func(bson.ValueReader) error) error {
+
+	const location = "mongov2.readBSONGeometry"
+
+	if reader.Type() == bson.TypeNull {
+		return reader.ReadNull()
+	}
+
+	document, err := reader.ReadDocument()
+
+	if err != nil {
+		return derp.Wrap(err, location, "Geometry must be a document")
+	}
+
+	hasType := false
+	hasCoordinates := false
+
+	for {
+		name, element, err := document.ReadElement()
+
+		if errors.Is(err, bson.ErrEOD) {
+			break
+		}
+
+		if err != nil {
+			return derp.Wrap(err, location, "Unable to read property")
+		}
+
+		switch name {
+
+		case geo.PropertyType:
+			value, err := element.ReadString()
+
+			if err != nil {
+				return derp.Wrap(err, location, "Unable to read type")
+			}
+
+			if value != geometryType {
+				return derp.Internal(location, "Invalid GeoJSON. Type must be '"+geometryType+"'", value)
+			}
+
+			hasType = true
+
+		case geo.PropertyCoordinates:
+			if err := readCoordinates(element); err != nil {
+				return derp.Wrap(err, location, "Unable to read coordinates")
+			}
+
+			hasCoordinates = true
+
+		default:
+			if err := element.Skip(); err != nil {
+				return derp.Wrap(err, location, "Unable to skip property", name)
+			}
+		}
+	}
+
+	if !hasType {
+		return derp.Internal(location, "Invalid GeoJSON. Type must be '"+geometryType+"'")
+	}
+
+	if !hasCoordinates {
+		return derp.Internal(location, "Invalid GeoJSON. Coordinates are required")
+	}
+
+	return nil
+}
+
+// readBSONPositions reads an array of coordinate arrays
+func readBSONPositions(reader bson.ValueReader) ([]geo.Position, error) {
+
+	const location = "mongov2.readBSONPositions"
+
+	array, err := reader.ReadArray()
+
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]geo.Position, 0, 8)
+
+	for {
+		element, err := array.ReadValue()
+
+		if errors.Is(err, bson.ErrEOA) {
+			return result, nil
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		position, err := readBSONPosition(element)
+
+		if err != nil {
+			return nil, derp.Wrap(err, location, "Invalid coordinate at index", len(result))
+		}
+
+		result = append(result, position)
+	}
+}
+
+// readBSONPosition reads a single coordinate array of length 2 or 3
+func readBSONPosition(reader bson.ValueReader) (geo.Position, error) {
+
+	const location = "mongov2.readBSONPosition"
+
+	array, err := reader.ReadArray()
+
+	if err != nil {
+		return geo.Position{}, derp.Wrap(err, location, "Coordinates must be an array")
+	}
+
+	var values [3]float64
+	length := 0
+
+	for {
+		element, err := array.ReadValue()
+
+		if errors.Is(err, bson.ErrEOA) {
+			break
+		}
+
+		if err != nil {
+			return geo.Position{}, derp.Wrap(err, location, "Unable to read coordinate")
+		}
+
+		if length == len(values) {
+			return geo.Position{}, derp.Internal(location, "Invalid coordinate length. Coordinates must be length 2 or 3")
+		}
+
+		if values[length], err = readBSONNumber(element); err != nil {
+			return geo.Position{}, derp.Wrap(err, location, "Unable to read coordinate")
+		}
+
+		length++
+	}
+
+	if length < 2 {
+		return geo.Position{}, derp.Internal(location, "Invalid coordinate length. Coordinates must be length 2 or 3", length)
+	}
+
+	return geo.NewPositionWithAltitude(values[0], values[1], values[2]), nil
+}
+
+// readBSONNumber reads any BSON numeric value as a float64
+func readBSONNumber(reader bson.ValueReader) (float64, error) {
+
+	const location = "mongov2.readBSONNumber"
+
+	switch reader.Type() {
+
+	case bson.TypeDouble:
+		return reader.ReadDouble()
+
+	case bson.TypeInt32:
+		value, err := reader.ReadInt32()
+		return float64(value), err
+
+	case bson.TypeInt64:
+		value, err := reader.ReadInt64()
+		return float64(value), err
+	}
+
+	return 0, derp.Internal(location, "Coordinate must be a number", reader.Type().String())
+}
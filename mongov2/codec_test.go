@@ -0,0 +1,144 @@
+package mongov2
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/benpate/geo"
+	"github.com/stretchr/testify/require"
+	bsonv1 "go.mongodb.org/mongo-driver/bson"
+	bsonrwv1 "go.mongodb.org/mongo-driver/bson/bsonrw"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// codecDocument exercises every codec, with and without omitempty
+type codecDocument struct {
+	Position   geo.Position   `bson:"position"`
+	Positions  []geo.Position `bson:"positions"`
+	Point      geo.Point      `bson:"point"`
+	LineString geo.LineString `bson:"lineString"`
+	Polygon    geo.Polygon    `bson:"polygon"`
+	Omitted    geo.Point      `bson:"omitted,omitempty"`
+	Pointer    *geo.Polygon   `bson:"pointer,omitempty"`
+}
+
+// marshal encodes a value using NewRegistry
+func marshal(t *testing.T, value any) []byte {
+
+	buffer := bytes.Buffer{}
+	encoder := bson.NewEncoder(bson.NewDocumentWriter(&buffer))
+	encoder.SetRegistry(NewRegistry())
+	require.Nil(t, encoder.Encode(value))
+
+	return buffer.Bytes()
+}
+
+// marshalV1 encodes a value with the v1 driver, using geo.NewBSONRegistry
+func marshalV1(t *testing.T, value any) []byte {
+
+	buffer := bytes.Buffer{}
+	writer, err := bsonrwv1.NewBSONValueWriter(&buffer)
+	require.Nil(t, err)
+
+	encoder, err := bsonv1.NewEncoder(writer)
+	require.Nil(t, err)
+
+	encoder.SetRegistry(geo.NewBSONRegistry())
+	require.Nil(t, encoder.Encode(value))
+
+	return buffer.Bytes()
+}
+
+// unmarshal decodes a value using NewRegistry
+func unmarshal(data []byte, value any) error {
+	decoder := bson.NewDecoder(bson.NewDocumentReader(bytes.NewReader(data)))
+	decoder.SetRegistry(NewRegistry())
+	return decoder.Decode(value)
+}
+
+func TestCodec_MatchesV1(t *testing.T) {
+
+	polygon := geo.NewPolygon(geo.NewPosition(0, 0), geo.NewPosition(1, 0), geo.NewPosition(1, 1), geo.NewPosition(0, 0))
+
+	document := codecDocument{
+		Position:   geo.NewPositionWithAltitude(1, 2, 3),
+		Positions:  []geo.Position{geo.NewPosition(4, 5), geo.NewPosition(6, 7)},
+		Point:      geo.NewPoint(-79.9822, 40.4461),
+		LineString: geo.NewLineString(geo.NewPosition(1, 2), geo.NewPosition(3, 4)),
+		Polygon:    polygon,
+		Pointer:    &polygon,
+	}
+
+	// Documents written by either driver generation are identical
+	expected := marshalV1(t, document)
+	require.Equal(t, bsonv1.Raw(expected).String(), bson.Raw(marshal(t, document)).String())
+	require.Equal(t, expected, marshal(t, document))
+}
+
+func TestCodec_RoundTrip(t *testing.T) {
+
+	polygon := geo.NewPolygon(geo.NewPosition(0, 0), geo.NewPosition(1, 0), geo.NewPosition(1, 1), geo.NewPosition(0, 0))
+
+	original := codecDocument{
+		Position:   geo.NewPositionWithAltitude(1, 2, 3),
+		Positions:  []geo.Position{geo.NewPosition(4, 5)},
+		Point:      geo.NewPoint(-79.9822, 40.4461),
+		LineString: geo.NewLineString(geo.NewPosition(1, 2), geo.NewPosition(3, 4)),
+		Polygon:    polygon,
+		Pointer:    &polygon,
+	}
+
+	result := codecDocument{}
+	require.Nil(t, unmarshal(marshal(t, original), &result))
+	require.Equal(t, original, result)
+
+	// Documents written by the v1 driver can be read by the v2 driver
+	result = codecDocument{}
+	require.Nil(t, unmarshal(marshalV1(t, original), &result))
+	require.Equal(t, original, result)
+}
+
+func TestCodec_Zero(t *testing.T) {
+
+	data := marshal(t, codecDocument{})
+
+	raw := bson.Raw(data)
+	require.Equal(t, bson.TypeArray, raw.Lookup("position").Type)
+	require.Equal(t, bson.TypeNull, raw.Lookup("point").Type)
+	require.Equal(t, bson.TypeNull, raw.Lookup("lineString").Type)
+	require.Equal(t, bson.TypeNull, raw.Lookup("polygon").Type)
+
+	_, err := raw.LookupErr("omitted")
+	require.NotNil(t, err)
+
+	result := codecDocument{Point: geo.NewPoint(1, 2)}
+	require.Nil(t, unmarshal(data, &result))
+	require.True(t, result.Point.IsZero())
+	require.True(t, result.LineString.IsZero())
+	require.True(t, result.Polygon.IsZero())
+}
+
+func TestCodec_Errors(t *testing.T) {
+
+	invalid := []bson.D{
+		{{Key: "point", Value: bson.D{{Key: "type", Value: "Polygon"}, {Key: "coordinates", Value: bson.A{1.0, 2.0}}}}},
+		{{Key: "point", Value: bson.D{{Key: "coordinates", Value: bson.A{1.0, 2.0}}}}},
+		{{Key: "point", Value: bson.D{{Key: "type", Value: "Point"}, {Key: "coordinates", Value: bson.A{1.0}}}}},
+		{{Key: "polygon", Value: bson.D{{Key: "type", Value: "Polygon"}, {Key: "coordinates", Value: bson.A{}}}}},
+		{{Key: "position", Value: bson.A{"1", "2"}}},
+		{{Key: "position", Value: bson.A{true, false}}},
+		{{Key: "lineString", Value: bson.D{{Key: "type", Value: "LineString"}, {Key: "coordinates", Value: bson.A{bson.A{true, false}}}}}},
+		{{Key: "polygon", Value: bson.D{{Key: "type", Value: "Polygon"}, {Key: "coordinates", Value: bson.A{bson.A{bson.A{"1", "2"}}}}}}},
+		{{Key: "point", Value: bson.D{{Key: "type", Value: "Point"}}}},
+		{{Key: "lineString", Value: bson.D{{Key: "type", Value: "LineString"}}}},
+		{{Key: "polygon", Value: bson.D{{Key: "type", Value: "Polygon"}, {Key: "bbox", Value: bson.A{1, 2, 3, 4}}}}},
+	}
+
+	for _, document := range invalid {
+		data, err := bson.Marshal(document)
+		require.Nil(t, err)
+
+		result := codecDocument{}
+		require.NotNil(t, unmarshal(data, &result), document)
+	}
+}
@@ -0,0 +1,12 @@
+// Package mongov2 stores the geo package's types with version 2 of the MongoDB
+// Go driver (go.mongodb.org/mongo-driver/v2).
+//
+// The geo package itself is built on the v1 driver. Its Point, LineString, and
+// Polygon types work with both driver generations through MarshalBSON and
+// UnmarshalBSON, but geo.Position implements the v1 MarshalBSONValue signature,
+// which the v2 driver does not recognize. Registering this package's codecs
+// makes every geo type (including Position) read and write the same GeoJSON
+// documents from either driver:
+//
+//	client, err := mongo.Connect(options.Client().ApplyURI(uri).SetRegistry(mongov2.NewRegistry()))
+package mongov2
@@ -38,6 +38,30 @@ func (point Point) LatLon() string {
 	return formatCoordinatePair(point.Latitude, point.Longitude)
 }
 
+// GeoURI returns this Point as an RFC 5870 "geo" URI, such as "geo:37.78,-122.4"
+func (point Point) GeoURI() string {
+	return NewGeoURI(point).String()
+}
+
+// BoundingBox returns the smallest BoundingBox that contains this Point
+func (point Point) BoundingBox() BoundingBox {
+	return NewBoundingBox(point.Longitude, point.Latitude, point.Longitude, point.Latitude)
+}
+
+// Transform converts this Point from one coordinate reference system into another
+func (point Point) Transform(from Projection, to Projection) (Point, error) {
+
+	const location = "geo.Point.Transform"
+
+	position, err := Transform(point.Position, from, to)
+
+	if err != nil {
+		return Point{}, derp.Wrap(err, location, "Unable to transform point")
+	}
+
+	return Point{Position: position}, nil
+}
+
 /******************************************
  * Marhshalling methods
  ******************************************/
@@ -106,6 +130,19 @@ func (point *Point) UnmarshalMap(data mapof.Any) error {
 // object into this Point object.
 func (point *Point) UnmarshalJSON(data []byte) error {
 
+	// Fast path: parse well-formed GeoJSON directly, without allocating
+	if position, ok := decodePointJSON(data); ok {
+		point.Position = position
+		return nil
+	}
+
+	return point.unmarshalJSONGeneral(data)
+}
+
+// unmarshalJSONGeneral is the original (allocating) JSON decoder, which handles
+// every input that the fast path does not, including all errors.
+func (point *Point) unmarshalJSONGeneral(data []byte) error {
+
 	const location = "geo.Point.UnmarshalJSON"
 
 	// Unmarshall JSON into an intermediate object
@@ -128,6 +165,19 @@ func (point *Point) UnmarshalJSON(data []byte) error {
 // a GeoJSON object into this Point structure.
 func (point *Point) UnmarshalBSON(data []byte) error {
 
+	// Fast path: read well-formed GeoJSON directly, without allocating
+	if position, ok := decodePointBSON(data); ok {
+		point.Position = position
+		return nil
+	}
+
+	return point.unmarshalBSONGeneral(data)
+}
+
+// unmarshalBSONGeneral is the original (allocating) BSON decoder, which handles
+// every input that the fast path does not, including all errors.
+func (point *Point) unmarshalBSONGeneral(data []byte) error {
+
 	const location = "geo.Point.UnmarshalBSON"
 
 	// Unmarshall BSON into an intermediate object
@@ -46,6 +46,21 @@ func NewPolygonFromString(data string) Polygon {
 	return NewPolygon(result...)
 }
 
+// NewPolygonFromPolyline decodes a string created by Google's Encoded Polyline
+// Algorithm (see PolylinePrecision5 and PolylinePrecision6) into a Polygon.
+func NewPolygonFromPolyline(data string, precision int) (Polygon, error) {
+
+	const location = "geo.NewPolygonFromPolyline"
+
+	coordinates, err := DecodePolyline(data, precision)
+
+	if err != nil {
+		return Polygon{}, derp.Wrap(err, location, "Unable to decode polyline")
+	}
+
+	return NewPolygon(coordinates...), nil
+}
+
 // IsZero returns TRUE if this Polygon has no coordinates.
 func (polygon Polygon) IsZero() bool {
 	return polygon.Coordinates.IsZero()
@@ -56,6 +71,25 @@ func (polygon Polygon) NotZero() bool {
 	return !polygon.IsZero()
 }
 
+// BoundingBox returns the smallest BoundingBox that contains this Polygon
+func (polygon Polygon) BoundingBox() BoundingBox {
+	return NewBoundingBoxFromPositions(polygon.Coordinates...)
+}
+
+// Transform converts every coordinate in this Polygon from one coordinate reference system into another
+func (polygon Polygon) Transform(from Projection, to Projection) (Polygon, error) {
+
+	const location = "geo.Polygon.Transform"
+
+	coordinates, err := transformPositions(polygon.Coordinates, from, to)
+
+	if err != nil {
+		return Polygon{}, derp.Wrap(err, location, "Unable to transform polygon")
+	}
+
+	return NewPolygon(coordinates...), nil
+}
+
 /******************************************
  * Marhshalling methods
  ******************************************/
@@ -71,6 +105,21 @@ func (polygon Polygon) String() string {
 	return strings.Join(result, ",")
 }
 
+// Polyline returns the coordinates encoded with Google's Encoded Polyline
+// Algorithm, at the given precision (see PolylinePrecision5 and PolylinePrecision6)
+func (polygon Polygon) Polyline(precision int) (string, error) {
+
+	const location = "geo.Polygon.Polyline"
+
+	result, err := EncodePolyline(polygon.Coordinates, precision)
+
+	if err != nil {
+		return "", derp.Wrap(err, location, "Unable to encode Polygon")
+	}
+
+	return result, nil
+}
+
 // GeoJSON returns a GeoJSON representation of this Polygon
 func (polygon Polygon) GeoJSON() map[string]any {
 	return map[string]any{
@@ -147,6 +196,19 @@ func (polygon *Polygon) UnmarshalStruct(data GeoJSONPolygon) error {
 // object into this Polygon object.
 func (polygon *Polygon) UnmarshalJSON(data []byte) error {
 
+	// Fast path: parse well-formed GeoJSON directly into the coordinate slice
+	if coordinates, ok := decodePolygonJSON(data); ok {
+		polygon.Coordinates = coordinates
+		return nil
+	}
+
+	return polygon.unmarshalJSONGeneral(data)
+}
+
+// unmarshalJSONGeneral is the original JSON decoder, which handles every input
+// that the fast path does not, including all errors.
+func (polygon *Polygon) unmarshalJSONGeneral(data []byte) error {
+
 	const location = "geo.Polygon.UnmarshalJSON"
 
 	// Unmarshall JSON into an intermediate object
@@ -169,6 +231,19 @@ func (polygon *Polygon) UnmarshalJSON(data []byte) error {
 // a GeoJSON object into this Polygon structure.
 func (polygon *Polygon) UnmarshalBSON(data []byte) error {
 
+	// Fast path: read well-formed GeoJSON directly into the coordinate slice
+	if coordinates, ok := decodePolygonBSON(data); ok {
+		polygon.Coordinates = coordinates
+		return nil
+	}
+
+	return polygon.unmarshalBSONGeneral(data)
+}
+
+// unmarshalBSONGeneral is the original BSON decoder, which handles every input
+// that the fast path does not, including all errors.
+func (polygon *Polygon) unmarshalBSONGeneral(data []byte) error {
+
 	const location = "geo.Polygon.UnmarshalBSON"
 
 	// Unmarshall BSON into an intermediate object
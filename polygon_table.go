@@ -0,0 +1,164 @@
+package geo
+
+import (
+	"bufio"
+	"io"
+	"iter"
+	"math"
+	"strings"
+
+	"github.com/benpate/derp"
+)
+
+// boundaryPrecision is the number of decimal places in the encoded boundary polylines (about 11m)
+const boundaryPrecision = 4
+
+// boundaryCellSize is the size (in degrees) of each cell in a polygonTable's grid
+const boundaryCellSize = 1
+
+// polygonTable is a list of named polygons, such as country or time zone boundaries,
+// with a grid of one-degree cells to find the polygons near a position quickly.
+// In text, each line holds a name, then the polygon's outer ring and any holes,
+// each written as an encoded polyline. Names made of several polygons use several lines.
+type polygonTable struct {
+	polygons []boundaryPolygon
+	cells    map[int][]int32 // cell number => polygons whose bounding boxes touch the cell
+}
+
+// boundaryPolygon is a single polygon in a polygonTable
+type boundaryPolygon struct {
+	name  string       // Name of the polygon, such as "US-CA" or "America/Denver"
+	box   BoundingBox  // Bounding box of the outer ring
+	rings [][]Position // Outer ring, followed by any holes
+}
+
+// readPolygonTable reads a polygonTable from text
+func readPolygonTable(reader io.Reader) (polygonTable, error) {
+
+	const location = "geo.readPolygonTable"
+
+	result := polygonTable{
+		cells: map[int][]int32{},
+	}
+
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(nil, 64*1024*1024)
+
+	for scanner.Scan() {
+
+		line := strings.TrimSpace(scanner.Text())
+
+		if (line == "") || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.Split(line, "\t")
+
+		if len(fields) < 2 {
+			return polygonTable{}, derp.Internal(location, "Polygon must include a name and an outer ring", line)
+		}
+
+		polygon := boundaryPolygon{
+			name:  fields[0],
+			rings: make([][]Position, 0, len(fields)-1),
+		}
+
+		for _, field := range fields[1:] {
+
+			ring, err := DecodePolyline(field, boundaryPrecision)
+
+			if err != nil {
+				return polygonTable{}, derp.Wrap(err, location, "Unable to decode polygon", fields[0])
+			}
+
+			if len(ring) < 3 {
+				return polygonTable{}, derp.Internal(location, "Polygon rings must have at least three positions", fields[0])
+			}
+
+			polygon.rings = append(polygon.rings, ring)
+		}
+
+		polygon.box = NewBoundingBoxFromPositions(polygon.rings[0]...)
+		result.add(polygon)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return polygonTable{}, derp.Wrap(err, location, "Unable to read polygons")
+	}
+
+	return result, nil
+}
+
+// add puts a polygon into this table, and into every grid cell that it touches
+func (table *polygonTable) add(polygon boundaryPolygon) {
+
+	polygonIndex := int32(len(table.polygons))
+	table.polygons = append(table.polygons, polygon)
+
+	west, south := boundaryCellPosition(polygon.box.West, polygon.box.South)
+	east, north := boundaryCellPosition(polygon.box.East, polygon.box.North)
+
+	for x := west; x <= east; x++ {
+		for y := south; y <= north; y++ {
+			cell := boundaryCellNumber(x, y)
+			table.cells[cell] = append(table.cells[cell], polygonIndex)
+		}
+	}
+}
+
+// containing returns the name of every polygon that contains a position, in table order
+func (table polygonTable) containing(position Position) iter.Seq[string] {
+
+	return func(yield func(string) bool) {
+
+		if math.IsNaN(position.Longitude) || math.IsNaN(position.Latitude) {
+			return
+		}
+
+		for _, polygonIndex := range table.cells[boundaryCell(position.Longitude, position.Latitude)] {
+
+			polygon := table.polygons[polygonIndex]
+
+			if polygon.contains(position) && !yield(polygon.name) {
+				return
+			}
+		}
+	}
+}
+
+// contains returns TRUE if a position is inside the outer ring of this polygon, but not inside any of its holes
+func (polygon boundaryPolygon) contains(position Position) bool {
+
+	if !polygon.box.Contains(position) {
+		return false
+	}
+
+	if !ringContains(polygon.rings[0], position) {
+		return false
+	}
+
+	for _, hole := range polygon.rings[1:] {
+		if ringContains(hole, position) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// boundaryCell returns the number of the grid cell that contains a longitude and latitude
+func boundaryCell(longitude float64, latitude float64) int {
+	return boundaryCellNumber(boundaryCellPosition(longitude, latitude))
+}
+
+// boundaryCellPosition returns the column and row of the grid cell that contains a longitude and latitude
+func boundaryCellPosition(longitude float64, latitude float64) (int, int) {
+	x := int(math.Floor((max(-180, min(longitude, 180)) + 180) / boundaryCellSize))
+	y := int(math.Floor((max(-90, min(latitude, 90)) + 90) / boundaryCellSize))
+	return x, y
+}
+
+// boundaryCellNumber combines a column and row into a single grid cell number
+func boundaryCellNumber(x int, y int) int {
+	return (y * 1000) + x
+}
@@ -0,0 +1,299 @@
+package geo
+
+import (
+	"math"
+	"strings"
+
+	"github.com/benpate/derp"
+)
+
+// Precisions commonly used with the Encoded Polyline Algorithm.
+// https://developers.google.com/maps/documentation/utilities/polylinealgorithm
+const (
+	// PolylinePrecision5 is the precision used by Google Maps and most static map APIs.
+	PolylinePrecision5 = 5
+
+	// PolylinePrecision6 is the "polyline6" precision used by OSRM, Valhalla, and Mapbox.
+	PolylinePrecision6 = 6
+
+	// PolylineMaxPrecision is the largest precision that polylines can use. Values
+	// at higher precisions would be too large to store exactly.
+	PolylineMaxPrecision = 10
+)
+
+// polylineMaxValue is the largest (scaled) value that a polyline can hold. Values up
+// to this size survive a round trip through float64 without being changed.
+const polylineMaxValue = 1 << 50
+
+// EncodePolyline encodes a list of positions using Google's Encoded Polyline
+// Algorithm, rounding coordinates to the given number of decimal places (from 0
+// to PolylineMaxPrecision). Altitudes are ignored. It returns an error if the
+// precision is out of range, or if a coordinate is not a finite number.
+func EncodePolyline(positions []Position, precision int) (string, error) {
+
+	const location = "geo.EncodePolyline"
+
+	result, err := encodePolyline(positions, precision, 0, false)
+
+	if err != nil {
+		return "", derp.Wrap(err, location, "Unable to encode polyline")
+	}
+
+	return result, nil
+}
+
+// EncodePolylineWithAltitude encodes a list of positions the same way as
+// EncodePolyline, but appends each Altitude as a third value per vertex
+// (in the style of HERE's flexible polyline), rounded to altitudePrecision
+// decimal places. The result can only be read by DecodePolylineWithAltitude.
+func EncodePolylineWithAltitude(positions []Position, precision int, altitudePrecision int) (string, error) {
+
+	const location = "geo.EncodePolylineWithAltitude"
+
+	result, err := encodePolyline(positions, precision, altitudePrecision, true)
+
+	if err != nil {
+		return "", derp.Wrap(err, location, "Unable to encode polyline")
+	}
+
+	return result, nil
+}
+
+// DecodePolyline decodes a string created by Google's Encoded Polyline
+// Algorithm into a list of positions, using the given number of decimal places
+// (from 0 to PolylineMaxPrecision).
+func DecodePolyline(data string, precision int) ([]Position, error) {
+
+	const location = "geo.DecodePolyline"
+
+	result, err := decodePolyline(data, precision, 0, false)
+
+	if err != nil {
+		return nil, derp.Wrap(err, location, "Unable to decode polyline", data)
+	}
+
+	return result, nil
+}
+
+// DecodePolylineWithAltitude decodes a string created by EncodePolylineWithAltitude
+// into a list of positions, including their altitudes.
+func DecodePolylineWithAltitude(data string, precision int, altitudePrecision int) ([]Position, error) {
+
+	const location = "geo.DecodePolylineWithAltitude"
+
+	result, err := decodePolyline(data, precision, altitudePrecision, true)
+
+	if err != nil {
+		return nil, derp.Wrap(err, location, "Unable to decode polyline", data)
+	}
+
+	return result, nil
+}
+
+// encodePolyline implements the Encoded Polyline Algorithm for 2 or 3 dimensions.
+// Each value is written as the difference from the same value in the previous vertex.
+func encodePolyline(positions []Position, precision int, altitudePrecision int, withAltitude bool) (string, error) {
+
+	const location = "geo.encodePolyline"
+
+	if err := validatePolylinePrecision(precision, altitudePrecision, withAltitude); err != nil {
+		return "", derp.Wrap(err, location, "Invalid precision")
+	}
+
+	factor := math.Pow10(precision)
+	altitudeFactor := math.Pow10(altitudePrecision)
+
+	var builder strings.Builder
+	var previousLatitude, previousLongitude, previousAltitude int64
+
+	for index, position := range positions {
+
+		latitude, ok := polylineValue(position.Latitude, factor)
+
+		if !ok {
+			return "", derp.Internal(location, "Latitude is out of range", index, position.Latitude)
+		}
+
+		longitude, ok := polylineValue(position.Longitude, factor)
+
+		if !ok {
+			return "", derp.Internal(location, "Longitude is out of range", index, position.Longitude)
+		}
+
+		writePolylineValue(&builder, latitude-previousLatitude)
+		writePolylineValue(&builder, longitude-previousLongitude)
+
+		previousLatitude = latitude
+		previousLongitude = longitude
+
+		if withAltitude {
+
+			altitude, ok := polylineValue(position.Altitude, altitudeFactor)
+
+			if !ok {
+				return "", derp.Internal(location, "Altitude is out of range", index, position.Altitude)
+			}
+
+			writePolylineValue(&builder, altitude-previousAltitude)
+			previousAltitude = altitude
+		}
+	}
+
+	return builder.String(), nil
+}
+
+// validatePolylinePrecision returns an error if a precision is outside of the range
+// that polylines can store
+func validatePolylinePrecision(precision int, altitudePrecision int, withAltitude bool) error {
+
+	const location = "geo.validatePolylinePrecision"
+
+	if (precision < 0) || (precision > PolylineMaxPrecision) {
+		return derp.Internal(location, "Precision must be between 0 and PolylineMaxPrecision", precision)
+	}
+
+	if withAltitude && ((altitudePrecision < 0) || (altitudePrecision > PolylineMaxPrecision)) {
+		return derp.Internal(location, "Altitude precision must be between 0 and PolylineMaxPrecision", altitudePrecision)
+	}
+
+	return nil
+}
+
+// polylineValue scales and rounds a coordinate, and returns FALSE if the
+// result is not a number or is too large to store
+func polylineValue(value float64, factor float64) (int64, bool) {
+
+	scaled := math.Round(value * factor)
+
+	if !(math.Abs(scaled) <= polylineMaxValue) {
+		return 0, false
+	}
+
+	return int64(scaled), true
+}
+
+// writePolylineValue writes a single signed value as a series of 5-bit chunks
+func writePolylineValue(builder *strings.Builder, value int64) {
+
+	// Left-shift the value, and invert it if it was negative
+	encoded := uint64(value) << 1
+
+	if value < 0 {
+		encoded = ^encoded
+	}
+
+	// Write 5-bit chunks, OR-ing 0x20 onto every chunk that is followed by another
+	for encoded >= 0x20 {
+		builder.WriteByte(byte((0x20 | (encoded & 0x1f)) + 63))
+		encoded >>= 5
+	}
+
+	builder.WriteByte(byte(encoded + 63))
+}
+
+// decodePolyline reverses encodePolyline for 2 or 3 dimensions.
+func decodePolyline(data string, precision int, altitudePrecision int, withAltitude bool) ([]Position, error) {
+
+	const location = "geo.decodePolyline"
+
+	if err := validatePolylinePrecision(precision, altitudePrecision, withAltitude); err != nil {
+		return nil, derp.Wrap(err, location, "Invalid precision")
+	}
+
+	factor := math.Pow10(precision)
+	altitudeFactor := math.Pow10(altitudePrecision)
+
+	result := make([]Position, 0, len(data)/4)
+	var latitude, longitude, altitude int64
+
+	for index := 0; index < len(data); {
+
+		var delta int64
+		var err error
+
+		if delta, index, err = readPolylineValue(data, index); err != nil {
+			return nil, derp.Wrap(err, location, "Invalid latitude", len(result))
+		}
+		latitude += delta
+
+		if !polylineInRange(latitude) {
+			return nil, derp.Internal(location, "Latitude is out of range", len(result))
+		}
+
+		if delta, index, err = readPolylineValue(data, index); err != nil {
+			return nil, derp.Wrap(err, location, "Invalid longitude", len(result))
+		}
+		longitude += delta
+
+		if !polylineInRange(longitude) {
+			return nil, derp.Internal(location, "Longitude is out of range", len(result))
+		}
+
+		position := NewPosition(float64(longitude)/factor, float64(latitude)/factor)
+
+		if withAltitude {
+			if delta, index, err = readPolylineValue(data, index); err != nil {
+				return nil, derp.Wrap(err, location, "Invalid altitude", len(result))
+			}
+			altitude += delta
+
+			if !polylineInRange(altitude) {
+				return nil, derp.Internal(location, "Altitude is out of range", len(result))
+			}
+
+			position.Altitude = float64(altitude) / altitudeFactor
+		}
+
+		result = append(result, position)
+	}
+
+	return result, nil
+}
+
+// polylineInRange returns TRUE if a (scaled) value is small enough to store in a polyline
+func polylineInRange(value int64) bool {
+	return (value >= -polylineMaxValue) && (value <= polylineMaxValue)
+}
+
+// readPolylineValue reads a single signed value starting at `index`, and returns
+// the value along with the index of the first character after it.
+func readPolylineValue(data string, index int) (int64, int, error) {
+
+	const location = "geo.readPolylineValue"
+
+	var encoded uint64
+	var shift uint
+
+	for {
+
+		if index >= len(data) {
+			return 0, index, derp.Internal(location, "Polyline ends in the middle of a value")
+		}
+
+		if shift > 60 {
+			return 0, index, derp.Internal(location, "Polyline value is too large", index)
+		}
+
+		character := data[index]
+
+		if (character < 63) || (character > 126) {
+			return 0, index, derp.Internal(location, "Invalid character in polyline", index, string(character))
+		}
+
+		chunk := uint64(character - 63)
+		encoded |= (chunk & 0x1f) << shift
+		shift += 5
+		index++
+
+		if chunk < 0x20 {
+			break
+		}
+	}
+
+	// Undo the left-shift and inversion applied by writePolylineValue
+	if encoded&1 != 0 {
+		return int64(^(encoded >> 1)), index, nil
+	}
+
+	return int64(encoded >> 1), index, nil
+}
@@ -0,0 +1,185 @@
+package geo
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// googleExample is the reference route from Google's polyline documentation
+var googleExample = []Position{
+	NewPosition(-120.2, 38.5),
+	NewPosition(-120.95, 40.7),
+	NewPosition(-126.453, 43.252),
+}
+
+func TestEncodePolyline(t *testing.T) {
+
+	result, err := EncodePolyline(googleExample, PolylinePrecision5)
+	require.Nil(t, err)
+	require.Equal(t, "_p~iF~ps|U_ulLnnqC_mqNvxq`@", result)
+
+	result, err = EncodePolyline(nil, PolylinePrecision5)
+	require.Nil(t, err)
+	require.Equal(t, "", result)
+}
+
+func TestEncodePolyline_Precision6(t *testing.T) {
+
+	result, err := EncodePolyline(googleExample, PolylinePrecision6)
+	require.Nil(t, err)
+	require.Equal(t, "_izlhA~rlgdF_{geC~ywl@_kwzCn`{nI", result)
+}
+
+func TestEncodePolyline_Errors(t *testing.T) {
+
+	// Precisions outside of the range that polylines can store
+	for _, precision := range []int{-1, PolylineMaxPrecision + 1, 400} {
+		_, err := EncodePolyline(googleExample, precision)
+		require.NotNil(t, err, precision)
+	}
+
+	_, err := EncodePolylineWithAltitude(googleExample, PolylinePrecision5, -1)
+	require.NotNil(t, err)
+
+	// Coordinates that are not finite, or too large to store
+	for _, position := range []Position{
+		NewPosition(math.NaN(), 0),
+		NewPosition(0, math.Inf(1)),
+		NewPosition(1e300, 0),
+	} {
+		_, err := EncodePolyline([]Position{position}, PolylinePrecision5)
+		require.NotNil(t, err, position)
+	}
+
+	_, err = EncodePolylineWithAltitude([]Position{NewPositionWithAltitude(0, 0, math.NaN())}, PolylinePrecision5, 2)
+	require.NotNil(t, err)
+
+	// The largest precision still works for every coordinate on Earth
+	result, err := EncodePolyline([]Position{NewPosition(-180, -90), NewPosition(180, 90)}, PolylineMaxPrecision)
+	require.Nil(t, err)
+
+	decoded, err := DecodePolyline(result, PolylineMaxPrecision)
+	require.Nil(t, err)
+	require.Equal(t, []Position{NewPosition(-180, -90), NewPosition(180, 90)}, decoded)
+}
+
+func TestDecodePolyline(t *testing.T) {
+
+	result, err := DecodePolyline("_p~iF~ps|U_ulLnnqC_mqNvxq`@", PolylinePrecision5)
+	require.Nil(t, err)
+	require.Equal(t, len(googleExample), len(result))
+
+	for index, expected := range googleExample {
+		require.InDelta(t, expected.Longitude, result[index].Longitude, 0.000001)
+		require.InDelta(t, expected.Latitude, result[index].Latitude, 0.000001)
+	}
+}
+
+func TestDecodePolyline_Precision6(t *testing.T) {
+
+	encoded, err := EncodePolyline(googleExample, PolylinePrecision6)
+	require.Nil(t, err)
+
+	result, err := DecodePolyline(encoded, PolylinePrecision6)
+	require.Nil(t, err)
+	require.Equal(t, googleExample, result)
+}
+
+func TestDecodePolyline_Errors(t *testing.T) {
+
+	// Truncated in the middle of a value
+	_, err := DecodePolyline("_p~iF~ps|", PolylinePrecision5)
+	require.NotNil(t, err)
+
+	// A latitude without its longitude
+	_, err = DecodePolyline("_p~iF", PolylinePrecision5)
+	require.NotNil(t, err)
+
+	// Characters outside of the polyline alphabet
+	_, err = DecodePolyline("_p~iF ps|U", PolylinePrecision5)
+	require.NotNil(t, err)
+
+	// A value with too many continuation chunks
+	_, err = DecodePolyline("~~~~~~~~~~~~~~~~", PolylinePrecision5)
+	require.NotNil(t, err)
+
+	// A value that is larger than a polyline can store (2^50 + 1)
+	_, err = DecodePolyline("a_________A??", PolylinePrecision5)
+	require.NotNil(t, err)
+
+	// Precisions outside of the range that polylines can store
+	_, err = DecodePolyline("_p~iF~ps|U", -1)
+	require.NotNil(t, err)
+
+	_, err = DecodePolyline("_p~iF~ps|U", PolylineMaxPrecision+1)
+	require.NotNil(t, err)
+
+	_, err = DecodePolylineWithAltitude("_p~iF~ps|U?", PolylinePrecision5, 400)
+	require.NotNil(t, err)
+}
+
+func TestPolylineWithAltitude(t *testing.T) {
+
+	positions := []Position{
+		NewPositionWithAltitude(-120.2, 38.5, 100),
+		NewPositionWithAltitude(-120.95, 40.7, 1250.5),
+		NewPositionWithAltitude(-126.453, 43.252, -12.25),
+	}
+
+	encoded, err := EncodePolylineWithAltitude(positions, PolylinePrecision5, 2)
+	require.Nil(t, err)
+
+	result, err := DecodePolylineWithAltitude(encoded, PolylinePrecision5, 2)
+	require.Nil(t, err)
+	require.Equal(t, positions, result)
+
+	// A 3D polyline cannot be read as a 2D one (it has an odd number of values)
+	_, err = DecodePolyline(encoded, PolylinePrecision5)
+	require.NotNil(t, err)
+}
+
+func TestPolygon_Polyline(t *testing.T) {
+
+	polygon := NewPolygon(googleExample...)
+	encoded, err := polygon.Polyline(PolylinePrecision5)
+	require.Nil(t, err)
+	require.Equal(t, "_p~iF~ps|U_ulLnnqC_mqNvxq`@", encoded)
+
+	result, err := NewPolygonFromPolyline(encoded, PolylinePrecision5)
+	require.Nil(t, err)
+	require.Equal(t, polygon, result)
+
+	_, err = NewPolygonFromPolyline("_p~iF", PolylinePrecision5)
+	require.NotNil(t, err)
+
+	_, err = polygon.Polyline(PolylineMaxPrecision + 1)
+	require.NotNil(t, err)
+}
+
+func TestLineString_Polyline(t *testing.T) {
+
+	lineString := NewLineString(googleExample...)
+	encoded, err := lineString.Polyline(PolylinePrecision6)
+	require.Nil(t, err)
+
+	result, err := NewLineStringFromPolyline(encoded, PolylinePrecision6)
+	require.Nil(t, err)
+	require.Equal(t, lineString, result)
+
+	_, err = NewLineStringFromPolyline("_p~iF", PolylinePrecision6)
+	require.NotNil(t, err)
+}
+
+// mustEncodePolyline encodes the positions of a test fixture, which must be valid
+func mustEncodePolyline(positions []Position, precision int) string {
+
+	result, err := EncodePolyline(positions, precision)
+
+	if err != nil {
+		panic(err)
+	}
+
+	return result
+}
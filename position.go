@@ -120,6 +120,19 @@ func (position *Position) UnmarshalSlice(coordinates sliceof.Float) error {
 // a GeoJSON coordinate pair into this Position structure.
 func (position *Position) UnmarshalJSON(data []byte) error {
 
+	// Fast path: parse well-formed coordinates directly, without allocating
+	if result, ok := decodePositionJSON(data); ok {
+		*position = result
+		return nil
+	}
+
+	return position.unmarshalJSONGeneral(data)
+}
+
+// unmarshalJSONGeneral is the original (allocating) JSON decoder, which handles
+// every input that the fast path does not, including all errors.
+func (position *Position) unmarshalJSONGeneral(data []byte) error {
+
 	const location = "geo.Position.UnmarshalJSON"
 
 	// Unmarshal into a temporary array
@@ -142,6 +155,19 @@ func (position *Position) UnmarshalJSON(data []byte) error {
 // bson.ValueUnmarshaler to match MarshalBSONValue's array encoding.
 func (position *Position) UnmarshalBSONValue(dataType bsontype.Type, data []byte) error {
 
+	// Fast path: read well-formed coordinates directly, without allocating
+	if result, ok := decodePositionBSON(dataType, data); ok {
+		*position = result
+		return nil
+	}
+
+	return position.unmarshalBSONValueGeneral(dataType, data)
+}
+
+// unmarshalBSONValueGeneral is the original (allocating) BSON decoder, which handles
+// every input that the fast path does not, including all errors.
+func (position *Position) unmarshalBSONValueGeneral(dataType bsontype.Type, data []byte) error {
+
 	const location = "geo.Position.UnmarshalBSONValue"
 
 	// Unmarshal into a temporary array
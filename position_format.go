@@ -0,0 +1,154 @@
+package geo
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Notations supported by Position.Format and ParsePosition
+const (
+	// PositionFormatDecimal is decimal degrees, latitude first: `40.4461, -79.9822`
+	PositionFormatDecimal = "decimal"
+
+	// PositionFormatDMS is degrees, minutes, and seconds: `40°26'46"N 79°58'56"W`
+	PositionFormatDMS = "dms"
+
+	// PositionFormatDDM is degrees and decimal minutes: `40°26.767'N 79°58.933'W`
+	PositionFormatDDM = "ddm"
+
+	// PositionFormatISO6709 is the ISO 6709 decimal degree string: `+40.4461-079.9822/`
+	PositionFormatISO6709 = "iso6709"
+)
+
+// maxFormatPrecision is the largest precision that Format will honor. Anything
+// finer than this is well below the precision of a float64 coordinate anyway.
+const maxFormatPrecision = 9
+
+// Format returns this Position in a human-readable notation (one of the
+// PositionFormat* constants), with `precision` decimal places in the final
+// component (degrees, minutes, or seconds). All notations are written latitude
+// first, and unknown styles fall back to PositionFormatDecimal. ISO 6709 output
+// includes the Altitude when it is non-zero.
+func (position Position) Format(style string, precision int) string {
+
+	precision = max(0, min(precision, maxFormatPrecision))
+
+	switch style {
+
+	case PositionFormatDMS:
+		return formatSexagesimal(position.Latitude, "N", "S", precision, true) + " " +
+			formatSexagesimal(position.Longitude, "E", "W", precision, true)
+
+	case PositionFormatDDM:
+		return formatSexagesimal(position.Latitude, "N", "S", precision, false) + " " +
+			formatSexagesimal(position.Longitude, "E", "W", precision, false)
+
+	case PositionFormatISO6709:
+		return formatISO6709(position, precision)
+	}
+
+	return strconv.FormatFloat(position.Latitude, 'f', precision, 64) + ", " +
+		strconv.FormatFloat(position.Longitude, 'f', precision, 64)
+}
+
+// formatSexagesimal writes a single coordinate as degrees and minutes (and
+// optionally seconds), followed by a hemisphere letter. The value is rounded
+// once, in units of the final component, so that "59.9999" minutes carries
+// into the degrees instead of printing as "60".
+func formatSexagesimal(value float64, positive string, negative string, precision int, withSeconds bool) string {
+
+	// Number of final-component units in a single degree
+	unitsPerMinute := int64(math.Pow10(precision))
+
+	if withSeconds {
+		unitsPerMinute *= 60
+	}
+
+	unitsPerDegree := unitsPerMinute * 60
+	total := int64(math.Round(math.Abs(value) * float64(unitsPerDegree)))
+
+	// Values that round to zero are written in the positive hemisphere
+	hemisphere := positive
+
+	if (value < 0) && (total > 0) {
+		hemisphere = negative
+	}
+
+	// Degrees and minutes are always whole numbers
+	degrees := total / unitsPerDegree
+	total -= degrees * unitsPerDegree
+
+	var builder strings.Builder
+
+	builder.WriteString(strconv.FormatInt(degrees, 10))
+	builder.WriteString("°")
+
+	if withSeconds {
+		minutes := total / unitsPerMinute
+		total -= minutes * unitsPerMinute
+		builder.WriteString(strconv.FormatInt(minutes, 10))
+		builder.WriteString("'")
+		builder.WriteString(formatFixedUnits(total, precision))
+		builder.WriteString(`"`)
+	} else {
+		builder.WriteString(formatFixedUnits(total, precision))
+		builder.WriteString("'")
+	}
+
+	builder.WriteString(hemisphere)
+	return builder.String()
+}
+
+// formatFixedUnits writes an integer number of 10^-precision units as a
+// fixed-point decimal, without any floating point rounding.
+func formatFixedUnits(units int64, precision int) string {
+
+	if precision == 0 {
+		return strconv.FormatInt(units, 10)
+	}
+
+	digits := strconv.FormatInt(units, 10)
+
+	if len(digits) <= precision {
+		digits = strings.Repeat("0", precision-len(digits)+1) + digits
+	}
+
+	return digits[:len(digits)-precision] + "." + digits[len(digits)-precision:]
+}
+
+// formatISO6709 writes a Position as an ISO 6709 decimal degree string,
+// padding latitude to two integer digits and longitude to three.
+func formatISO6709(position Position, precision int) string {
+
+	result := formatISO6709Component(position.Latitude, 2, precision) +
+		formatISO6709Component(position.Longitude, 3, precision)
+
+	if position.Altitude != 0 {
+		result += formatISO6709Component(position.Altitude, 0, -1)
+	}
+
+	return result + "/"
+}
+
+// formatISO6709Component writes a signed, zero-padded decimal number
+func formatISO6709Component(value float64, integerDigits int, precision int) string {
+
+	result := strconv.FormatFloat(math.Abs(value), 'f', precision, 64)
+
+	// Values that round to zero are written with a positive sign
+	sign := "+"
+
+	if (value < 0) && (strings.Trim(result, "0.") != "") {
+		sign = "-"
+	}
+
+	// Zero-pad the integer portion
+	integerPart, _, _ := strings.Cut(result, ".")
+
+	if len(integerPart) < integerDigits {
+		result = strings.Repeat("0", integerDigits-len(integerPart)) + result
+	}
+
+	return sign + result
+}
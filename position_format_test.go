@@ -0,0 +1,50 @@
+package geo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPosition_Format(t *testing.T) {
+
+	position := NewPosition(-79.98222, 40.44611)
+
+	require.Equal(t, "40.4461, -79.9822", position.Format(PositionFormatDecimal, 4))
+	require.Equal(t, `40°26'46"N 79°58'56"W`, position.Format(PositionFormatDMS, 0))
+	require.Equal(t, `40°26'46.00"N 79°58'55.99"W`, position.Format(PositionFormatDMS, 2))
+	require.Equal(t, `40°26.767'N 79°58.933'W`, position.Format(PositionFormatDDM, 3))
+	require.Equal(t, `+40.4461-079.9822/`, position.Format(PositionFormatISO6709, 4))
+
+	// Unknown styles fall back to decimal degrees
+	require.Equal(t, "40.45, -79.98", position.Format("unknown", 2))
+}
+
+func TestPosition_Format_Carry(t *testing.T) {
+
+	// 59.9999 seconds rounds up into the next minute, and then the next degree
+	position := NewPosition(10.99999999, -20.99999999)
+	require.Equal(t, `21°0'0"S 11°0'0"E`, position.Format(PositionFormatDMS, 0))
+	require.Equal(t, `21°0.00'S 11°0.00'E`, position.Format(PositionFormatDDM, 2))
+}
+
+func TestPosition_Format_Zero(t *testing.T) {
+
+	// Tiny negative values that round to zero are written as positive
+	position := NewPosition(-0.0000001, -0.0000001)
+	require.Equal(t, `0°0'0"N 0°0'0"E`, position.Format(PositionFormatDMS, 0))
+	require.Equal(t, `+00.00+000.00/`, position.Format(PositionFormatISO6709, 2))
+}
+
+func TestPosition_Format_ISO6709_Altitude(t *testing.T) {
+	position := NewPositionWithAltitude(2.2945, 48.8584, 35.5)
+	require.Equal(t, "+48.858+002.295+35.5/", position.Format(PositionFormatISO6709, 3))
+}
+
+func TestPosition_Format_Precision(t *testing.T) {
+
+	// Precision is clamped to a sensible range
+	position := NewPosition(1.4, 2.6)
+	require.Equal(t, "3, 1", position.Format(PositionFormatDecimal, -5))
+	require.Equal(t, "2.600000000, 1.400000000", position.Format(PositionFormatDecimal, 50))
+}
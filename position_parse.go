@@ -0,0 +1,471 @@
+package geo
+
+import (
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/benpate/derp"
+)
+
+// iso6709Pattern matches an ISO 6709 string such as "+40.4461-079.9822/". Latitude
+// may be ±DD, ±DDMM, or ±DDMMSS and longitude ±DDD, ±DDDMM, or ±DDDMMSS, each
+// with an optional decimal fraction, followed by an optional altitude and CRS.
+var iso6709Pattern = regexp.MustCompile(`^([+-]\d{2}(?:\d{2}){0,2}(?:\.\d+)?)([+-]\d{3}(?:\d{2}){0,2}(?:\.\d+)?)([+-]\d+(?:\.\d+)?)?(?:CRS[A-Za-z0-9_:]+)?/?$`)
+
+// coordinateToken is a single lexical element of a human-readable coordinate
+type coordinateToken struct {
+	kind  byte   // 'n' (number), 'h' (hemisphere), ',' (separator), or a unit marker: '°', '\'', '"'
+	value string // the text of a number, or the letter of a hemisphere
+}
+
+// coordinateGroup collects the tokens that describe one axis of a Position
+type coordinateGroup struct {
+	numbers    []string // degrees, minutes, seconds (in that order)
+	units      []byte   // the unit marker that followed each number (or 0)
+	hemisphere byte     // N, S, E, W, or 0 if none was given
+}
+
+// ParsePosition parses a human-readable coordinate pair into a Position. It
+// recognizes decimal degrees (`40.4461, -79.9822`), degrees/minutes/seconds
+// (`40°26'46"N 79°58'56"W`), degrees and decimal minutes (`N 40 26.767 W 79 58.933`),
+// and ISO 6709 (`+40.4461-079.9822/`).
+//
+// Hemisphere letters (N/S/E/W) may appear before or after each coordinate, and
+// determine which value is latitude and which is longitude, so either axis order
+// is accepted. Without hemisphere letters, values are read "latitude, longitude"
+// (the order people say aloud, NOT the GeoJSON order) unless the first value is
+// out of range for a latitude.
+func ParsePosition(value string) (Position, error) {
+
+	const location = "geo.ParsePosition"
+
+	value = strings.TrimSpace(value)
+
+	if value == "" {
+		return Position{}, derp.Internal(location, "Coordinate string is empty")
+	}
+
+	// ISO 6709 strings have a fixed, separator-free structure
+	if matches := iso6709Pattern.FindStringSubmatch(value); matches != nil {
+		return parseISO6709(matches)
+	}
+
+	tokens, err := tokenizeCoordinates(value)
+
+	if err != nil {
+		return Position{}, derp.Wrap(err, location, "Unable to read coordinates", value)
+	}
+
+	groups, err := groupCoordinates(tokens)
+
+	if err != nil {
+		return Position{}, derp.Wrap(err, location, "Unable to separate latitude from longitude", value)
+	}
+
+	first, err := groups[0].degrees()
+
+	if err != nil {
+		return Position{}, derp.Wrap(err, location, "Invalid first coordinate", value)
+	}
+
+	second, err := groups[1].degrees()
+
+	if err != nil {
+		return Position{}, derp.Wrap(err, location, "Invalid second coordinate", value)
+	}
+
+	// Use hemisphere letters (or value ranges) to decide the axis order
+	latitudeFirst, err := latitudeIsFirst(groups[0], groups[1], first)
+
+	if err != nil {
+		return Position{}, derp.Wrap(err, location, "Unable to determine axis order", value)
+	}
+
+	result := NewPosition(second, first)
+
+	if !latitudeFirst {
+		result = NewPosition(first, second)
+	}
+
+	if err := validateLongitudeLatitude(result); err != nil {
+		return Position{}, derp.Wrap(err, location, "Coordinates out of range", value)
+	}
+
+	return result, nil
+}
+
+// parseISO6709 converts the sub-matches of iso6709Pattern into a Position
+func parseISO6709(matches []string) (Position, error) {
+
+	const location = "geo.parseISO6709"
+
+	latitude, err := parseISO6709Component(matches[1], 2)
+
+	if err != nil {
+		return Position{}, derp.Wrap(err, location, "Invalid latitude", matches[1])
+	}
+
+	longitude, err := parseISO6709Component(matches[2], 3)
+
+	if err != nil {
+		return Position{}, derp.Wrap(err, location, "Invalid longitude", matches[2])
+	}
+
+	result := NewPosition(longitude, latitude)
+
+	if matches[3] != "" {
+
+		altitude, err := strconv.ParseFloat(matches[3], 64)
+
+		if err != nil {
+			return Position{}, derp.Wrap(err, location, "Invalid altitude", matches[3])
+		}
+
+		result.Altitude = altitude
+	}
+
+	if err := validateLongitudeLatitude(result); err != nil {
+		return Position{}, derp.Wrap(err, location, "Coordinates out of range", matches[0])
+	}
+
+	return result, nil
+}
+
+// parseISO6709Component converts a signed ISO 6709 value (±D, ±DM, or ±DMS, where
+// D has `degreeDigits` digits and M and S have two each) into decimal degrees.
+func parseISO6709Component(value string, degreeDigits int) (float64, error) {
+
+	const location = "geo.parseISO6709Component"
+
+	sign := 1.0
+
+	if value[0] == '-' {
+		sign = -1.0
+	}
+
+	integerPart, fraction, _ := strings.Cut(value[1:], ".")
+
+	if fraction != "" {
+		fraction = "." + fraction
+	}
+
+	// Split the integer part into degrees, minutes, and seconds (the fraction belongs to the last one)
+	components := []string{integerPart[:degreeDigits]}
+
+	for remainder := integerPart[degreeDigits:]; remainder != ""; remainder = remainder[2:] {
+		components = append(components, remainder[:2])
+	}
+
+	components[len(components)-1] += fraction
+
+	result, err := sexagesimalToDecimal(components)
+
+	if err != nil {
+		return 0, derp.Wrap(err, location, "Invalid ISO 6709 value", value)
+	}
+
+	return sign * result, nil
+}
+
+// tokenizeCoordinates splits a human-readable coordinate string into numbers,
+// hemisphere letters, unit markers, and separators. Whitespace is discarded.
+func tokenizeCoordinates(value string) ([]coordinateToken, error) {
+
+	const location = "geo.tokenizeCoordinates"
+
+	result := make([]coordinateToken, 0, 12)
+	runes := []rune(strings.ToUpper(value))
+
+	for index := 0; index < len(runes); {
+
+		character := runes[index]
+
+		switch {
+
+		case (character == ' ') || (character == '\t'):
+			index++
+
+		case (character == '-') || (character == '+') || (character == '.') || ((character >= '0') && (character <= '9')):
+			start := index
+			index++
+			for (index < len(runes)) && (((runes[index] >= '0') && (runes[index] <= '9')) || (runes[index] == '.')) {
+				index++
+			}
+			result = append(result, coordinateToken{kind: 'n', value: string(runes[start:index])})
+
+		case strings.ContainsRune("NSEW", character):
+			result = append(result, coordinateToken{kind: 'h', value: string(character)})
+			index++
+
+		case (character == ',') || (character == ';') || (character == '/'):
+			result = append(result, coordinateToken{kind: ','})
+			index++
+
+		case strings.ContainsRune("°º˚D", character):
+			result = append(result, coordinateToken{kind: '°'})
+			index++
+
+		case (character == '"') || (character == '″') || (character == '”'):
+			result = append(result, coordinateToken{kind: '"'})
+			index++
+
+		case (character == '\'') || (character == '′') || (character == '’') || (character == 'M'):
+
+			// Two single quotes in a row are a common substitute for a double quote
+			if (character == '\'') && (index+1 < len(runes)) && (runes[index+1] == '\'') {
+				result = append(result, coordinateToken{kind: '"'})
+				index += 2
+				continue
+			}
+
+			result = append(result, coordinateToken{kind: '\''})
+			index++
+
+		default:
+			return nil, derp.Internal(location, "Unexpected character in coordinates", string(character))
+		}
+	}
+
+	return result, nil
+}
+
+// groupCoordinates splits a list of tokens into exactly two coordinateGroups
+func groupCoordinates(tokens []coordinateToken) ([]coordinateGroup, error) {
+
+	const location = "geo.groupCoordinates"
+
+	hemispheres := 0
+	separators := 0
+	degreeMarkers := 0
+
+	for _, token := range tokens {
+		switch token.kind {
+		case 'h':
+			hemispheres++
+		case ',':
+			separators++
+		case '°':
+			degreeMarkers++
+		}
+	}
+
+	// startsGroup reports whether the token at `index` begins a new coordinate
+	var startsGroup func(index int) bool
+
+	switch {
+
+	// Hemisphere letters before each coordinate ("N 40 26.767 W 79 58.933")
+	case (hemispheres == 2) && (tokens[0].kind == 'h'):
+		startsGroup = func(index int) bool {
+			return tokens[index].kind == 'h'
+		}
+
+	// Hemisphere letters after each coordinate (`40°26'46"N 79°58'56"W`)
+	case hemispheres == 2:
+		startsGroup = func(index int) bool {
+			return (index > 0) && (tokens[index-1].kind == 'h')
+		}
+
+	case hemispheres != 0:
+		return nil, derp.Internal(location, "Hemisphere letters must be given for both coordinates, or neither")
+
+	// An explicit separator ("40.4461, -79.9822")
+	case separators == 1:
+		startsGroup = func(index int) bool {
+			return (index > 0) && (tokens[index-1].kind == ',')
+		}
+
+	case separators != 0:
+		return nil, derp.Internal(location, "Too many separators between coordinates")
+
+	// Degree markers ("40° 26.767 -79° 58.933")
+	case degreeMarkers == 2:
+		startsGroup = func(index int) bool {
+			return (tokens[index].kind == 'n') && (index+1 < len(tokens)) && (tokens[index+1].kind == '°')
+		}
+
+	// Bare numbers ("40 26 46 -79 58 56"), split down the middle
+	default:
+		numbers := 0
+		for _, token := range tokens {
+			if token.kind == 'n' {
+				numbers++
+			}
+		}
+
+		if (numbers%2 != 0) || (numbers > 6) {
+			return nil, derp.Internal(location, "Unable to split numbers into two coordinates", numbers)
+		}
+
+		seen := 0
+		startsGroup = func(index int) bool {
+			if tokens[index].kind != 'n' {
+				return false
+			}
+			seen++
+			return seen == (numbers/2)+1
+		}
+	}
+
+	// Walk the tokens, building up each group
+	result := []coordinateGroup{{}}
+
+	for index, token := range tokens {
+
+		// Evaluate startsGroup for every token, because the bare-number rule counts them
+		if startsNew := startsGroup(index); startsNew && (index > 0) {
+			result = append(result, coordinateGroup{})
+		}
+
+		group := &result[len(result)-1]
+
+		switch token.kind {
+
+		case 'n':
+			group.numbers = append(group.numbers, token.value)
+			group.units = append(group.units, 0)
+
+		case 'h':
+			if group.hemisphere != 0 {
+				return nil, derp.Internal(location, "Multiple hemisphere letters in one coordinate")
+			}
+			group.hemisphere = token.value[0]
+
+		case ',':
+			// Separators only mark group boundaries
+
+		default:
+			if (len(group.units) == 0) || (group.units[len(group.units)-1] != 0) {
+				return nil, derp.Internal(location, "Unit marker must follow a number", string(token.kind))
+			}
+			group.units[len(group.units)-1] = token.kind
+		}
+	}
+
+	if len(result) != 2 {
+		return nil, derp.Internal(location, "Expected exactly two coordinates", len(result))
+	}
+
+	return result, nil
+}
+
+// degrees converts this group into signed decimal degrees
+func (group coordinateGroup) degrees() (float64, error) {
+
+	const location = "geo.coordinateGroup.degrees"
+
+	if (len(group.numbers) == 0) || (len(group.numbers) > 3) {
+		return 0, derp.Internal(location, "Coordinate must have one to three numbers", group.numbers)
+	}
+
+	// Unit markers, if present, must match the position of each number
+	for index, unit := range group.units {
+		if (unit != 0) && (unit != []byte{'°', '\'', '"'}[index]) {
+			return 0, derp.Internal(location, "Unit marker is out of order", group.numbers)
+		}
+	}
+
+	// Only the degrees may carry a sign
+	components := append([]string{}, group.numbers...)
+	sign := 1.0
+
+	switch components[0][0] {
+	case '-':
+		sign = -1
+		components[0] = components[0][1:]
+	case '+':
+		components[0] = components[0][1:]
+	}
+
+	result, err := sexagesimalToDecimal(components)
+
+	if err != nil {
+		return 0, derp.Wrap(err, location, "Invalid coordinate", group.numbers)
+	}
+
+	switch group.hemisphere {
+
+	case 'S', 'W':
+		if sign < 0 {
+			return 0, derp.Internal(location, "Coordinate cannot have both a minus sign and a S/W hemisphere", group.numbers)
+		}
+		sign = -1
+	}
+
+	return sign * result, nil
+}
+
+// sexagesimalToDecimal converts unsigned degrees[, minutes[, seconds]] into
+// decimal degrees. Only the final component may have a fractional part.
+func sexagesimalToDecimal(components []string) (float64, error) {
+
+	const location = "geo.sexagesimalToDecimal"
+
+	result := 0.0
+	divisor := 1.0
+
+	for index, component := range components {
+
+		if (component == "") || (component[0] == '-') || (component[0] == '+') {
+			return 0, derp.Internal(location, "Only the degrees may have a sign", components)
+		}
+
+		if (index < len(components)-1) && strings.Contains(component, ".") {
+			return 0, derp.Internal(location, "Only the last component may have a fraction", components)
+		}
+
+		value, err := strconv.ParseFloat(component, 64)
+
+		if err != nil {
+			return 0, derp.Wrap(err, location, "Invalid number", component)
+		}
+
+		if (index > 0) && (value >= 60) {
+			return 0, derp.Internal(location, "Minutes and seconds must be less than 60", components)
+		}
+
+		result += value / divisor
+		divisor *= 60
+	}
+
+	return result, nil
+}
+
+// latitudeIsFirst decides whether the first of two coordinate groups is the latitude
+func latitudeIsFirst(first coordinateGroup, second coordinateGroup, firstValue float64) (bool, error) {
+
+	const location = "geo.latitudeIsFirst"
+
+	isLatitude := func(hemisphere byte) bool {
+		return (hemisphere == 'N') || (hemisphere == 'S')
+	}
+
+	// Without hemispheres, assume "latitude, longitude" unless that's impossible
+	if first.hemisphere == 0 {
+		return math.Abs(firstValue) <= 90, nil
+	}
+
+	if isLatitude(first.hemisphere) == isLatitude(second.hemisphere) {
+		return false, derp.Internal(location, "Both coordinates are on the same axis", string(first.hemisphere), string(second.hemisphere))
+	}
+
+	return isLatitude(first.hemisphere), nil
+}
+
+// validateLongitudeLatitude confirms that a Position is on the planet
+func validateLongitudeLatitude(position Position) error {
+
+	const location = "geo.validateLongitudeLatitude"
+
+	if math.IsNaN(position.Latitude) || (position.Latitude < -90) || (position.Latitude > 90) {
+		return derp.Internal(location, "Latitude must be between -90 and 90", position.Latitude)
+	}
+
+	if math.IsNaN(position.Longitude) || (position.Longitude < -180) || (position.Longitude > 180) {
+		return derp.Internal(location, "Longitude must be between -180 and 180", position.Longitude)
+	}
+
+	return nil
+}
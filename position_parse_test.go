@@ -0,0 +1,117 @@
+package geo
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePosition(t *testing.T) {
+
+	// check confirms that a string parses to (approximately) the expected Position
+	check := func(value string, longitude float64, latitude float64) {
+		result, err := ParsePosition(value)
+		require.Nil(t, err, value)
+		require.InDelta(t, longitude, result.Longitude, 0.0001, value)
+		require.InDelta(t, latitude, result.Latitude, 0.0001, value)
+	}
+
+	// Decimal degrees
+	check("40.4461, -79.9822", -79.9822, 40.4461)
+	check("40.4461 -79.9822", -79.9822, 40.4461)
+	check("40.4461;-79.9822", -79.9822, 40.4461)
+	check("40.4461°, -79.9822°", -79.9822, 40.4461)
+	check("40.4461N 79.9822W", -79.9822, 40.4461)
+
+	// Degrees, minutes, and seconds
+	check(`40°26'46"N 79°58'56"W`, -79.9822, 40.4461)
+	check(`40° 26′ 46″ N, 79° 58′ 56″ W`, -79.9822, 40.4461)
+	check(`40°26'46''N 79°58'56''W`, -79.9822, 40.4461)
+	check(`N 40 26 46 W 79 58 56`, -79.9822, 40.4461)
+	check(`40 26 46 -79 58 56`, -79.9822, 40.4461)
+	check(`40d 26m 46" N 79d 58m 56" W`, -79.9822, 40.4461)
+
+	// Degrees and decimal minutes
+	check("N 40 26.767 W 79 58.933", -79.9822, 40.4461)
+	check("N40°26.767' W79°58.933'", -79.9822, 40.4461)
+	check("40° 26.767 -79° 58.933", -79.9822, 40.4461)
+	check("40 26.767, -79 58.933", -79.9822, 40.4461)
+
+	// ISO 6709
+	check("+40.4461-079.9822/", -79.9822, 40.4461)
+	check("+40.4461-079.9822", -79.9822, 40.4461)
+	check("+4026.767-07958.933/", -79.9822, 40.4461)
+	check("+402646-0795856/", -79.9822, 40.4461)
+	check("+402646.0-0795856.0CRSWGS_84/", -79.9822, 40.4461)
+}
+
+func TestParsePosition_AxisOrder(t *testing.T) {
+
+	// check confirms that a string parses to (approximately) the expected Position
+	check := func(value string, longitude float64, latitude float64) {
+		result, err := ParsePosition(value)
+		require.Nil(t, err, value)
+		require.InDelta(t, longitude, result.Longitude, 0.0001, value)
+		require.InDelta(t, latitude, result.Latitude, 0.0001, value)
+	}
+
+	// Hemisphere letters decide the axis, regardless of order
+	check(`79°58'56"W 40°26'46"N`, -79.9822, 40.4461)
+	check(`W 79 58.933 N 40 26.767`, -79.9822, 40.4461)
+	check(`151.2093E 33.8688S`, 151.2093, -33.8688)
+
+	// Without hemispheres, a first value that can't be a latitude must be a longitude
+	check("-122.4194, 37.7749", -122.4194, 37.7749)
+}
+
+func TestParsePosition_Altitude(t *testing.T) {
+
+	result, err := ParsePosition("+27.5916+086.5640+8850CRSWGS_84/")
+	require.Nil(t, err)
+	require.Equal(t, 8850.0, result.Altitude)
+}
+
+func TestParsePosition_Errors(t *testing.T) {
+
+	checkError := func(value string) {
+		_, err := ParsePosition(value)
+		require.NotNil(t, err, value)
+	}
+
+	checkError("")
+	checkError("   ")
+	checkError("40.4461")
+	checkError("hello, world")
+	checkError("40.4461, -79.9822, 100")   // too many separators... or values
+	checkError("40 26 46 12 -79 58 56 12") // too many components
+	checkError("40 26 46 -79 58")          // can't split evenly
+	checkError(`40°26'46"N 79°58'56"N`)    // both latitudes
+	checkError(`40°26'46"N 79°58'56"`)     // only one hemisphere
+	checkError(`-40°26'46"S 79°58'56"W`)   // sign and hemisphere
+	checkError(`40°26'66"N 79°58'56"W`)    // seconds out of range
+	checkError(`40°61'N 79°58'W`)          // minutes out of range
+	checkError(`40.5°26'N 79°58'W`)        // fractional degrees with minutes
+	checkError(`40°-26'N 79°58'W`)         // signed minutes
+	checkError(`40'26°N 79°58'W`)          // units out of order
+	checkError(`°40 N 79 W`)               // unit without a number
+	checkError("95, 200")                  // out of range
+	checkError("+95.0000-079.9822/")       // ISO 6709 out of range
+	checkError("+4075-07958/")             // ISO 6709 minutes out of range
+
+	// ISO 6709 altitude that overflows a float64
+	checkError("+40.4-079.9+" + strings.Repeat("9", 400) + "/")
+}
+
+func TestParsePosition_RoundTrip(t *testing.T) {
+
+	// Every notation that Format writes can be read by ParsePosition
+	position := NewPosition(-79.98222, 40.44611)
+
+	for _, style := range []string{PositionFormatDecimal, PositionFormatDMS, PositionFormatDDM, PositionFormatISO6709} {
+		result, err := ParsePosition(position.Format(style, 4))
+		require.Nil(t, err, style)
+		require.InDelta(t, position.Longitude, result.Longitude, 0.0001, style)
+		require.InDelta(t, position.Latitude, result.Latitude, 0.0001, style)
+	}
+}
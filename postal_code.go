@@ -0,0 +1,108 @@
+package geo
+
+import (
+	_ "embed"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/benpate/derp"
+)
+
+//go:embed data/postal-codes.tsv
+var postalCodeFormatsTSV string
+
+// postalCodeFormat describes the postal codes used in one country
+type postalCodeFormat struct {
+	pattern   *regexp.Regexp // Matches the whole canonical postal code
+	formats   []string       // Formats for people, with "9" for a digit, "A" for a letter, and "X" for either
+	separator string         // Separator between the two parts of the postal code (if any)
+	position  int            // Where the separator goes in a compact code (negative values count from the end)
+	examples  []string       // Example postal codes, in canonical form
+}
+
+// postalCodeFormats parses the embedded postal code formats the first time they are used
+var postalCodeFormats = sync.OnceValue(func() map[string]postalCodeFormat {
+
+	result := map[string]postalCodeFormat{}
+
+	for _, fields := range iso3166Rows(postalCodeFormatsTSV, 6) {
+
+		position, err := strconv.Atoi(fields[4])
+
+		if err != nil {
+			panic("geo: invalid postal code position for " + fields[0])
+		}
+
+		result[fields[0]] = postalCodeFormat{
+			pattern:   regexp.MustCompile(`^(?:` + fields[1] + `)$`),
+			formats:   strings.Split(fields[2], ","),
+			separator: fields[3],
+			position:  position,
+			examples:  strings.Split(fields[5], ","),
+		}
+	}
+
+	return result
+})
+
+// NormalizePostalCode checks a postal code against the format used in a country (which
+// may be any value accepted by LookupCountry), and returns it in canonical form: upper
+// case, with the country's usual separator, such as "SW1A 2AA", "K1A 0B1", or "1234 AB".
+// Countries without a known format are not checked, and their postal codes are only trimmed.
+func NormalizePostalCode(country string, postalCode string) (string, error) {
+
+	postalCode = strings.Join(strings.Fields(postalCode), " ")
+	country = addressCountryCode(country)
+
+	format, ok := postalCodeFormats()[country]
+
+	if !ok || (postalCode == "") {
+		return postalCode, nil
+	}
+
+	postalCode = strings.ToUpper(postalCode)
+
+	if format.pattern.MatchString(postalCode) {
+		return postalCode, nil
+	}
+
+	// Remove every separator, then put the country's separator back in the right place
+	compact := strings.NewReplacer(" ", "", "-", "", "–", "").Replace(postalCode)
+	position := format.position
+
+	if position < 0 {
+		position += len(compact)
+	}
+
+	if (format.separator != "") && (position > 0) && (position < len(compact)) {
+		compact = compact[:position] + format.separator + compact[position:]
+	}
+
+	if format.pattern.MatchString(compact) {
+		return compact, nil
+	}
+
+	return postalCode, derp.Validation(
+		"Postal code must be in the format used in "+country+" ("+strings.Join(format.formats, " or ")+"), such as "+strings.Join(format.examples, " or "),
+		country,
+		postalCode,
+	)
+}
+
+// ValidatePostalCode checks this Address's PostalCode against the format used in its Country,
+// and rewrites it in canonical form when it is valid. It returns a derp Validation error that
+// names the expected format (such as "A9A 9A9") and some examples when it is not. Empty
+// postal codes, and postal codes in countries without a known format, are always valid.
+func (address *Address) ValidatePostalCode() error {
+
+	postalCode, err := NormalizePostalCode(address.Country, address.PostalCode)
+
+	if err != nil {
+		return err
+	}
+
+	address.PostalCode = postalCode
+	return nil
+}
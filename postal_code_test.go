@@ -0,0 +1,162 @@
+package geo
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"unicode"
+
+	"github.com/benpate/derp"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizePostalCode(t *testing.T) {
+
+	tests := []struct {
+		country  string
+		value    string
+		expected string
+	}{
+		{"CA", "K1A 0B1", "K1A 0B1"},
+		{"CA", "k1a0b1", "K1A 0B1"},
+		{"Canada", " k1a  0b1 ", "K1A 0B1"},
+		{"GB", "sw1a2aa", "SW1A 2AA"},
+		{"GB", "M2 5BQ", "M2 5BQ"},
+		{"GB", "m25bq", "M2 5BQ"},
+		{"United Kingdom", "ec1y8sy", "EC1Y 8SY"},
+		{"GB", "gir0aa", "GIR 0AA"},
+		{"NL", "1234ab", "1234 AB"},
+		{"NL", "1234 ab", "1234 AB"},
+		{"US", "95014", "95014"},
+		{"US", "221621010", "22162-1010"},
+		{"US", "22162 1010", "22162-1010"},
+		{"JP", "1000001", "100-0001"},
+		{"JP", "100-0001", "100-0001"},
+		{"BR", "01310100", "01310-100"},
+		{"PL", "00950", "00-950"},
+		{"PT", "2725 079", "2725-079"},
+		{"SE", "11455", "114 55"},
+		{"CZ", "10000", "100 00"},
+		{"DE", "10115", "10115"},
+		{"FR", "75 001", "75001"},
+		{"IE", "d02x285", "D02 X285"},
+		{"IE", "D02", "D02"},
+		{"LV", "lv 1073", "LV-1073"},
+		{"AU", "2000", "2000"},
+		{"US", "", ""},
+		{"", "anything at all", "anything at all"},
+		{"AQ", "  x ", "x"},
+	}
+
+	for _, test := range tests {
+		result, err := NormalizePostalCode(test.country, test.value)
+		require.Nil(t, err, test.country+" "+test.value)
+		require.Equal(t, test.expected, result, test.country+" "+test.value)
+	}
+}
+
+func TestNormalizePostalCode_Invalid(t *testing.T) {
+
+	tests := []struct {
+		country string
+		value   string
+	}{
+		{"CA", "K1A 0B"},
+		{"CA", "D1A 0B1"},
+		{"GB", "SW1A"},
+		{"GB", "QQ1 1AA"},
+		{"NL", "0123 AB"},
+		{"NL", "1234 SA"},
+		{"US", "1234"},
+		{"US", "12345-678"},
+		{"DE", "1011"},
+		{"JP", "100-001"},
+		{"AU", "junk"},
+	}
+
+	for _, test := range tests {
+		_, err := NormalizePostalCode(test.country, test.value)
+		require.NotNil(t, err, test.country+" "+test.value)
+		require.True(t, derp.IsValidationError(err), test.country+" "+test.value)
+	}
+}
+
+func TestNormalizePostalCode_ErrorMessage(t *testing.T) {
+
+	_, err := NormalizePostalCode("CA", "junk")
+	require.NotNil(t, err)
+	require.Equal(t, "Postal code must be in the format used in CA (A9A 9A9), such as K1A 0B1 or H3Z 2Y7 or V8X 3X4", derp.Message(err))
+
+	_, err = NormalizePostalCode("US", "1234")
+	require.NotNil(t, err)
+	require.Equal(t, "Postal code must be in the format used in US (99999 or 99999-9999), such as 95014 or 22162-1010", derp.Message(err))
+}
+
+func TestAddress_ValidatePostalCode(t *testing.T) {
+
+	address := Address{
+		Locality:   "Ottawa",
+		Region:     "ON",
+		PostalCode: "k1a0b1",
+		Country:    "CA",
+	}
+
+	require.Nil(t, address.ValidatePostalCode())
+	require.Equal(t, "K1A 0B1", address.PostalCode)
+
+	// Invalid postal codes are left unchanged
+	address.PostalCode = "k1a"
+	require.NotNil(t, address.ValidatePostalCode())
+	require.Equal(t, "k1a", address.PostalCode)
+
+	// Countries are matched by name
+	address = Address{PostalCode: "1234ab", Country: "Netherlands"}
+	require.Nil(t, address.ValidatePostalCode())
+	require.Equal(t, "1234 AB", address.PostalCode)
+}
+
+func TestPostalCodeFormats_Examples(t *testing.T) {
+
+	// Every example must be valid, and already in canonical form
+	for country, format := range postalCodeFormats() {
+
+		_, ok := LookupCountry(country)
+		require.True(t, ok, country)
+
+		for _, example := range format.examples {
+			result, err := NormalizePostalCode(country, example)
+			require.Nil(t, err, country+" "+example)
+			require.Equal(t, example, result, country+" "+example)
+			require.True(t, slices.ContainsFunc(format.formats, func(value string) bool {
+				return postalCodeMatchesFormat(example, value)
+			}), country+" "+example)
+		}
+	}
+}
+
+// postalCodeMatchesFormat returns TRUE if a postal code matches a format for people,
+// where "9" is a digit, "A" is a letter, "X" is either one, and everything else is literal
+func postalCodeMatchesFormat(postalCode string, format string) bool {
+
+	if len(postalCode) != len(format) {
+		return false
+	}
+
+	for index := range len(format) {
+
+		character, placeholder := postalCode[index], format[index]
+
+		switch {
+		case (placeholder == '9') && !unicode.IsDigit(rune(character)):
+			return false
+		case (placeholder == 'A') && !unicode.IsLetter(rune(character)):
+			return false
+		case (placeholder == 'X') && !unicode.IsLetter(rune(character)) && !unicode.IsDigit(rune(character)):
+			return false
+		case !strings.ContainsRune("9AX", rune(placeholder)) && (placeholder != character):
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,147 @@
+package geo
+
+import (
+	"github.com/benpate/derp"
+)
+
+// Projection converts Positions between WGS84 longitude/latitude and another
+// coordinate reference system. Projected coordinates are also carried in a
+// Position, with the easting (x) in Longitude and the northing (y) in Latitude.
+type Projection interface {
+
+	// Forward converts a WGS84 Position into this coordinate reference system
+	Forward(position Position) (Position, error)
+
+	// Inverse converts a Position in this coordinate reference system back into WGS84
+	Inverse(position Position) (Position, error)
+}
+
+// Transform converts a Position from one coordinate reference system into
+// another, by way of WGS84.
+func Transform(position Position, from Projection, to Projection) (Position, error) {
+
+	const location = "geo.Transform"
+
+	wgs84, err := from.Inverse(position)
+
+	if err != nil {
+		return Position{}, derp.Wrap(err, location, "Unable to convert position to WGS84", position)
+	}
+
+	result, err := to.Forward(wgs84)
+
+	if err != nil {
+		return Position{}, derp.Wrap(err, location, "Unable to convert position from WGS84", wgs84)
+	}
+
+	return result, nil
+}
+
+// transformPositions applies Transform to every Position in a slice
+func transformPositions(positions []Position, from Projection, to Projection) ([]Position, error) {
+
+	const location = "geo.transformPositions"
+
+	result := make([]Position, len(positions))
+
+	for index, position := range positions {
+
+		transformed, err := Transform(position, from, to)
+
+		if err != nil {
+			return nil, derp.Wrap(err, location, "Unable to transform position", index)
+		}
+
+		result[index] = transformed
+	}
+
+	return result, nil
+}
+
+/******************************************
+ * Identity Projection
+ ******************************************/
+
+// identityProjection is the "projection" for WGS84 longitude/latitude, which
+// returns every Position unchanged.
+type identityProjection struct{}
+
+// ProjectionWGS84 leaves Positions in WGS84 longitude/latitude (EPSG:4326).
+// Use it as the `from` or `to` value in Transform.
+var ProjectionWGS84 Projection = identityProjection{}
+
+// Forward returns the Position unchanged
+func (identityProjection) Forward(position Position) (Position, error) {
+	return position, nil
+}
+
+// Inverse returns the Position unchanged
+func (identityProjection) Inverse(position Position) (Position, error) {
+	return position, nil
+}
+
+/******************************************
+ * Web Mercator Projection
+ ******************************************/
+
+// webMercatorProjection adapts Position.WebMercator to the Projection interface
+type webMercatorProjection struct{}
+
+// ProjectionWebMercator converts Positions to and from Web Mercator meters (EPSG:3857)
+var ProjectionWebMercator Projection = webMercatorProjection{}
+
+// Forward converts a WGS84 Position into Web Mercator meters
+func (webMercatorProjection) Forward(position Position) (Position, error) {
+	x, y := position.WebMercator()
+	return NewPositionWithAltitude(x, y, position.Altitude), nil
+}
+
+// Inverse converts Web Mercator meters into a WGS84 Position
+func (webMercatorProjection) Inverse(position Position) (Position, error) {
+	result := NewPositionFromWebMercator(position.Longitude, position.Latitude)
+	result.Altitude = position.Altitude
+	return result, nil
+}
+
+/******************************************
+ * Pipeline
+ ******************************************/
+
+// Pipeline chains several Projections together, such as a datum shift followed by
+// a map projection. Forward applies each step in order, and Inverse applies each
+// step's Inverse in reverse order.
+type Pipeline []Projection
+
+// Forward converts a WGS84 Position through every step of this Pipeline
+func (pipeline Pipeline) Forward(position Position) (Position, error) {
+
+	const location = "geo.Pipeline.Forward"
+
+	for index, step := range pipeline {
+
+		var err error
+
+		if position, err = step.Forward(position); err != nil {
+			return Position{}, derp.Wrap(err, location, "Pipeline step failed", index)
+		}
+	}
+
+	return position, nil
+}
+
+// Inverse converts a Position back through every step of this Pipeline, into WGS84
+func (pipeline Pipeline) Inverse(position Position) (Position, error) {
+
+	const location = "geo.Pipeline.Inverse"
+
+	for index := len(pipeline) - 1; index >= 0; index-- {
+
+		var err error
+
+		if position, err = pipeline[index].Inverse(position); err != nil {
+			return Position{}, derp.Wrap(err, location, "Pipeline step failed", index)
+		}
+	}
+
+	return position, nil
+}
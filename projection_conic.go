@@ -0,0 +1,261 @@
+package geo
+
+import (
+	"math"
+
+	"github.com/benpate/derp"
+)
+
+// LambertConformalConic is the Lambert Conformal Conic projection with two standard
+// parallels (EPSG method 9802), used by Lambert-93 and many state-plane zones.
+// Set both standard parallels to the same latitude for the one-parallel variant.
+// https://epsg.org/guidance-notes.html (Guidance Note 7-2, section 3.1.1)
+type LambertConformalConic struct {
+	Ellipsoid         Ellipsoid // Reference ellipsoid of the projected coordinate system
+	CentralMeridian   float64   // Longitude of the false origin, in degrees
+	LatitudeOfOrigin  float64   // Latitude of the false origin, in degrees
+	StandardParallel1 float64   // First standard parallel, in degrees
+	StandardParallel2 float64   // Second standard parallel, in degrees
+	FalseEasting      float64   // Easting at the false origin, in meters
+	FalseNorthing     float64   // Northing at the false origin, in meters
+}
+
+// Forward converts a Position (on this projection's ellipsoid) into easting and northing
+func (projection LambertConformalConic) Forward(position Position) (Position, error) {
+
+	const location = "geo.LambertConformalConic.Forward"
+
+	if err := validateLongitudeLatitude(position); err != nil {
+		return Position{}, derp.Wrap(err, location, "Invalid position", position)
+	}
+
+	n, f, originRadius, err := projection.constants()
+
+	if err != nil {
+		return Position{}, derp.Wrap(err, location, "Invalid projection parameters", projection)
+	}
+
+	// The cone cannot represent the pole opposite its apex
+	if math.Abs(position.Latitude) == 90 && (math.Signbit(position.Latitude) != math.Signbit(n)) {
+		return Position{}, derp.Internal(location, "Position cannot be projected", position)
+	}
+
+	radius := projection.Ellipsoid.SemiMajorAxis * f * math.Pow(projection.t(degreesToRadians(position.Latitude)), n)
+	theta := n * degreesToRadians(normalizeLongitude(position.Longitude-projection.CentralMeridian))
+
+	return NewPositionWithAltitude(
+		projection.FalseEasting+radius*math.Sin(theta),
+		projection.FalseNorthing+originRadius-radius*math.Cos(theta),
+		position.Altitude,
+	), nil
+}
+
+// Inverse converts an easting and northing back into a Position on this projection's ellipsoid
+func (projection LambertConformalConic) Inverse(position Position) (Position, error) {
+
+	const location = "geo.LambertConformalConic.Inverse"
+
+	n, f, originRadius, err := projection.constants()
+
+	if err != nil {
+		return Position{}, derp.Wrap(err, location, "Invalid projection parameters", projection)
+	}
+
+	x := position.Longitude - projection.FalseEasting
+	y := originRadius - (position.Latitude - projection.FalseNorthing)
+
+	radius := math.Copysign(math.Hypot(x, y), n)
+	theta := math.Atan2(x, y)
+
+	if n < 0 {
+		theta = math.Atan2(-x, -y)
+	}
+
+	t := math.Pow(radius/(projection.Ellipsoid.SemiMajorAxis*f), 1/n)
+	latitude := latitudeFromConformalT(t, projection.Ellipsoid.Eccentricity())
+
+	return NewPositionWithAltitude(
+		normalizeLongitude(radiansToDegrees(theta/n)+projection.CentralMeridian),
+		radiansToDegrees(latitude),
+		position.Altitude,
+	), nil
+}
+
+// constants calculates the cone constant (n), the mapping radius factor (F),
+// and the radius of the latitude of origin (rF)
+func (projection LambertConformalConic) constants() (n float64, f float64, originRadius float64, err error) {
+
+	const location = "geo.LambertConformalConic.constants"
+
+	phi1 := degreesToRadians(projection.StandardParallel1)
+	phi2 := degreesToRadians(projection.StandardParallel2)
+
+	m1 := projection.m(phi1)
+	m2 := projection.m(phi2)
+	t1 := projection.t(phi1)
+	t2 := projection.t(phi2)
+
+	if phi1 == phi2 {
+		n = math.Sin(phi1)
+	} else {
+		n = (math.Log(m1) - math.Log(m2)) / (math.Log(t1) - math.Log(t2))
+	}
+
+	if (n == 0) || math.IsNaN(n) || math.IsInf(n, 0) {
+		return 0, 0, 0, derp.Internal(location, "Standard parallels must not be symmetric around the equator", projection)
+	}
+
+	f = m1 / (n * math.Pow(t1, n))
+	originRadius = projection.Ellipsoid.SemiMajorAxis * f * math.Pow(projection.t(degreesToRadians(projection.LatitudeOfOrigin)), n)
+
+	return n, f, originRadius, nil
+}
+
+// m calculates cos(φ) / √(1 - e²sin²φ)
+func (projection LambertConformalConic) m(latitude float64) float64 {
+	return conicM(latitude, projection.Ellipsoid.EccentricitySquared())
+}
+
+// t calculates tan(π/4 - φ/2) / ((1 - e sinφ) / (1 + e sinφ))^(e/2)
+func (projection LambertConformalConic) t(latitude float64) float64 {
+	e := projection.Ellipsoid.Eccentricity()
+	sinLatitude := math.Sin(latitude)
+	return math.Tan(math.Pi/4-latitude/2) / math.Pow((1-e*sinLatitude)/(1+e*sinLatitude), e/2)
+}
+
+// latitudeFromConformalT reverses LambertConformalConic.t by fixed-point iteration
+func latitudeFromConformalT(t float64, eccentricity float64) float64 {
+
+	latitude := math.Pi/2 - 2*math.Atan(t)
+
+	for range 10 {
+		sinLatitude := math.Sin(latitude)
+		latitude = math.Pi/2 - 2*math.Atan(t*math.Pow((1-eccentricity*sinLatitude)/(1+eccentricity*sinLatitude), eccentricity/2))
+	}
+
+	return latitude
+}
+
+// AlbersEqualArea is the Albers Equal-Area Conic projection (EPSG method 9822),
+// used by the US National Atlas (EPSG:5070) and many statewide grids.
+// https://epsg.org/guidance-notes.html (Guidance Note 7-2, section 3.1.2)
+type AlbersEqualArea struct {
+	Ellipsoid         Ellipsoid // Reference ellipsoid of the projected coordinate system
+	CentralMeridian   float64   // Longitude of the false origin, in degrees
+	LatitudeOfOrigin  float64   // Latitude of the false origin, in degrees
+	StandardParallel1 float64   // First standard parallel, in degrees
+	StandardParallel2 float64   // Second standard parallel, in degrees
+	FalseEasting      float64   // Easting at the false origin, in meters
+	FalseNorthing     float64   // Northing at the false origin, in meters
+}
+
+// Forward converts a Position (on this projection's ellipsoid) into easting and northing
+func (projection AlbersEqualArea) Forward(position Position) (Position, error) {
+
+	const location = "geo.AlbersEqualArea.Forward"
+
+	if err := validateLongitudeLatitude(position); err != nil {
+		return Position{}, derp.Wrap(err, location, "Invalid position", position)
+	}
+
+	n, c, originRadius, err := projection.constants()
+
+	if err != nil {
+		return Position{}, derp.Wrap(err, location, "Invalid projection parameters", projection)
+	}
+
+	radius := projection.Ellipsoid.SemiMajorAxis * math.Sqrt(c-n*projection.alpha(degreesToRadians(position.Latitude))) / n
+	theta := n * degreesToRadians(normalizeLongitude(position.Longitude-projection.CentralMeridian))
+
+	return NewPositionWithAltitude(
+		projection.FalseEasting+radius*math.Sin(theta),
+		projection.FalseNorthing+originRadius-radius*math.Cos(theta),
+		position.Altitude,
+	), nil
+}
+
+// Inverse converts an easting and northing back into a Position on this projection's ellipsoid
+func (projection AlbersEqualArea) Inverse(position Position) (Position, error) {
+
+	const location = "geo.AlbersEqualArea.Inverse"
+
+	n, c, originRadius, err := projection.constants()
+
+	if err != nil {
+		return Position{}, derp.Wrap(err, location, "Invalid projection parameters", projection)
+	}
+
+	x := position.Longitude - projection.FalseEasting
+	y := originRadius - (position.Latitude - projection.FalseNorthing)
+
+	radius := math.Hypot(x, y)
+	theta := math.Atan2(x, y)
+
+	if n < 0 {
+		theta = math.Atan2(-x, -y)
+	}
+
+	a := projection.Ellipsoid.SemiMajorAxis
+	e := projection.Ellipsoid.Eccentricity()
+	e2 := e * e
+	e4 := e2 * e2
+	e6 := e4 * e2
+
+	// Authalic latitude, then the series that converts it back into a geodetic latitude
+	alpha := (c - radius*radius*n*n/(a*a)) / n
+	beta := math.Asin(max(-1, min(1, alpha/(1-(1-e2)/(2*e)*math.Log((1-e)/(1+e))))))
+
+	latitude := beta +
+		(e2/3+31*e4/180+517*e6/5040)*math.Sin(2*beta) +
+		(23*e4/360+251*e6/3780)*math.Sin(4*beta) +
+		(761*e6/45360)*math.Sin(6*beta)
+
+	return NewPositionWithAltitude(
+		normalizeLongitude(projection.CentralMeridian+radiansToDegrees(theta/n)),
+		radiansToDegrees(latitude),
+		position.Altitude,
+	), nil
+}
+
+// constants calculates the cone constant (n), C, and the radius of the latitude of origin (ρ0)
+func (projection AlbersEqualArea) constants() (n float64, c float64, originRadius float64, err error) {
+
+	const location = "geo.AlbersEqualArea.constants"
+
+	phi1 := degreesToRadians(projection.StandardParallel1)
+	phi2 := degreesToRadians(projection.StandardParallel2)
+	e2 := projection.Ellipsoid.EccentricitySquared()
+
+	m1 := conicM(phi1, e2)
+	m2 := conicM(phi2, e2)
+	alpha1 := projection.alpha(phi1)
+	alpha2 := projection.alpha(phi2)
+
+	if phi1 == phi2 {
+		n = math.Sin(phi1)
+	} else {
+		n = (m1*m1 - m2*m2) / (alpha2 - alpha1)
+	}
+
+	if (n == 0) || math.IsNaN(n) || math.IsInf(n, 0) {
+		return 0, 0, 0, derp.Internal(location, "Standard parallels must not be symmetric around the equator", projection)
+	}
+
+	c = m1*m1 + n*alpha1
+	originRadius = projection.Ellipsoid.SemiMajorAxis * math.Sqrt(c-n*projection.alpha(degreesToRadians(projection.LatitudeOfOrigin))) / n
+
+	return n, c, originRadius, nil
+}
+
+// alpha calculates the (scaled) authalic latitude term used by the Albers projection
+func (projection AlbersEqualArea) alpha(latitude float64) float64 {
+	e := projection.Ellipsoid.Eccentricity()
+	sinLatitude := math.Sin(latitude)
+	return (1 - e*e) * (sinLatitude/(1-e*e*sinLatitude*sinLatitude) - (1/(2*e))*math.Log((1-e*sinLatitude)/(1+e*sinLatitude)))
+}
+
+// conicM calculates cos(φ) / √(1 - e²sin²φ), which is shared by both conic projections
+func conicM(latitude float64, eccentricitySquared float64) float64 {
+	sinLatitude := math.Sin(latitude)
+	return math.Cos(latitude) / math.Sqrt(1-eccentricitySquared*sinLatitude*sinLatitude)
+}
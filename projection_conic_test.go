@@ -0,0 +1,126 @@
+package geo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLambertConformalConic_EPSGExample(t *testing.T) {
+
+	// Worked example from EPSG Guidance Note 7-2 (NAD27 / Texas South Central),
+	// which is published in US survey feet.
+	const feet = 3937.0 / 1200.0
+
+	projection := LambertConformalConic{
+		Ellipsoid:         EllipsoidClarke1866,
+		CentralMeridian:   -99,
+		LatitudeOfOrigin:  27 + 50.0/60,
+		StandardParallel1: 28 + 23.0/60,
+		StandardParallel2: 30 + 17.0/60,
+		FalseEasting:      2000000 / feet,
+	}
+
+	result, err := projection.Forward(NewPosition(-96, 28.5))
+	require.Nil(t, err)
+	require.InDelta(t, 2963503.91, result.Longitude*feet, 0.01)
+	require.InDelta(t, 254759.80, result.Latitude*feet, 0.01)
+
+	back, err := projection.Inverse(result)
+	require.Nil(t, err)
+	require.InDelta(t, -96, back.Longitude, 0.00000001)
+	require.InDelta(t, 28.5, back.Latitude, 0.00000001)
+}
+
+func TestLambertConformalConic_Lambert93(t *testing.T) {
+
+	projection, err := ProjectionByEPSG(2154)
+	require.Nil(t, err)
+
+	result, err := projection.Forward(NewPosition(3, 46.5))
+	require.Nil(t, err)
+	require.InDelta(t, 700000, result.Longitude, 0.001)
+	require.InDelta(t, 6600000, result.Latitude, 0.001)
+}
+
+func TestLambertConformalConic_Southern(t *testing.T) {
+
+	// A cone whose apex is at the South Pole
+	projection := LambertConformalConic{
+		Ellipsoid:         EllipsoidGRS80,
+		CentralMeridian:   135,
+		LatitudeOfOrigin:  0,
+		StandardParallel1: -18,
+		StandardParallel2: -36,
+		FalseEasting:      1000000,
+		FalseNorthing:     10000000,
+	}
+
+	for _, position := range []Position{NewPosition(151.2, -33.9), NewPosition(115.9, -31.95), NewPosition(130.8, -12.5)} {
+		projected, err := projection.Forward(position)
+		require.Nil(t, err)
+
+		back, err := projection.Inverse(projected)
+		require.Nil(t, err)
+		require.InDelta(t, position.Longitude, back.Longitude, 0.00000001)
+		require.InDelta(t, position.Latitude, back.Latitude, 0.00000001)
+	}
+}
+
+func TestLambertConformalConic_Invalid(t *testing.T) {
+
+	// Standard parallels that are symmetric around the equator produce a cylinder, not a cone
+	projection := LambertConformalConic{Ellipsoid: EllipsoidWGS84, StandardParallel1: 30, StandardParallel2: -30}
+
+	_, err := projection.Forward(NewPosition(0, 0))
+	require.NotNil(t, err)
+
+	_, err = projection.Inverse(NewPosition(0, 0))
+	require.NotNil(t, err)
+}
+
+func TestAlbersEqualArea_Origin(t *testing.T) {
+
+	projection, err := ProjectionByEPSG(5070)
+	require.Nil(t, err)
+
+	result, err := projection.Forward(NewPosition(-96, 23))
+	require.Nil(t, err)
+	require.InDelta(t, 0, result.Longitude, 0.001)
+	require.InDelta(t, 0, result.Latitude, 0.001)
+}
+
+func TestAlbersEqualArea_RoundTrip(t *testing.T) {
+
+	for _, code := range []int{5070, 3310} {
+
+		projection, err := ProjectionByEPSG(code)
+		require.Nil(t, err)
+
+		for _, position := range []Position{NewPosition(-122.4194, 37.7749), NewPosition(-118.2437, 34.0522), NewPosition(-74.006, 40.7128)} {
+			projected, err := projection.Forward(position)
+			require.Nil(t, err)
+
+			back, err := projection.Inverse(projected)
+			require.Nil(t, err)
+			require.InDelta(t, position.Longitude, back.Longitude, 0.0000001)
+			require.InDelta(t, position.Latitude, back.Latitude, 0.0000001)
+		}
+	}
+}
+
+func TestAlbersEqualArea_California(t *testing.T) {
+
+	projection, err := ProjectionByEPSG(3310)
+	require.Nil(t, err)
+
+	// The central meridian runs straight up the Y axis
+	south, err := projection.Forward(NewPosition(-120, 34))
+	require.Nil(t, err)
+	require.InDelta(t, 0, south.Longitude, 0.001)
+
+	north, err := projection.Forward(NewPosition(-120, 40))
+	require.Nil(t, err)
+	require.InDelta(t, 0, north.Longitude, 0.001)
+	require.Greater(t, north.Latitude, south.Latitude)
+}
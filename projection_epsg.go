@@ -0,0 +1,140 @@
+package geo
+
+import (
+	"github.com/benpate/derp"
+)
+
+// ProjectionByEPSG returns the Projection for a coordinate reference system,
+// identified by its EPSG code. Only a commonly used subset of the EPSG registry
+// is available:
+//
+//   - 4326: WGS84 longitude/latitude
+//   - 3857: Web Mercator
+//   - 32601-32660, 32701-32760: WGS84 / UTM (north and south)
+//   - 25828-25838: ETRS89 / UTM
+//   - 26903-26923: NAD83 / UTM
+//   - 27700: OSGB36 / British National Grid
+//   - 2154: RGF93 / Lambert-93
+//   - 5070: NAD83 / Conus Albers
+//   - 3310: NAD83 / California Albers
+//   - 26943: NAD83 / California zone 3 (meters)
+//   - 32118: NAD83 / New York Long Island (meters)
+//
+// NAD83, ETRS89, and RGF93 differ from WGS84 by less than two meters, so they
+// are treated as identical to it (as most web mapping software does). OSGB36
+// is shifted with its published Helmert parameters, which are accurate to a
+// few meters.
+// https://epsg.io
+func ProjectionByEPSG(code int) (Projection, error) {
+
+	const location = "geo.ProjectionByEPSG"
+
+	switch {
+
+	case code == 4326:
+		return ProjectionWGS84, nil
+
+	case code == 3857:
+		return ProjectionWebMercator, nil
+
+	case (code >= 32601) && (code <= 32660):
+		return utmProjection(EllipsoidWGS84, code-32600, false), nil
+
+	case (code >= 32701) && (code <= 32760):
+		return utmProjection(EllipsoidWGS84, code-32700, true), nil
+
+	case (code >= 25828) && (code <= 25838):
+		return utmProjection(EllipsoidGRS80, code-25800, false), nil
+
+	case (code >= 26903) && (code <= 26923):
+		return utmProjection(EllipsoidGRS80, code-26900, false), nil
+
+	case code == 27700:
+		return Pipeline{
+			Helmert{
+				Ellipsoid:    EllipsoidAiry1830,
+				TranslationX: -446.448,
+				TranslationY: 125.157,
+				TranslationZ: -542.060,
+				RotationX:    -0.1502,
+				RotationY:    -0.2470,
+				RotationZ:    -0.8421,
+				Scale:        20.4894,
+			},
+			TransverseMercator{
+				Ellipsoid:        EllipsoidAiry1830,
+				CentralMeridian:  -2,
+				LatitudeOfOrigin: 49,
+				ScaleFactor:      0.9996012717,
+				FalseEasting:     400000,
+				FalseNorthing:    -100000,
+			},
+		}, nil
+
+	case code == 2154:
+		return LambertConformalConic{
+			Ellipsoid:         EllipsoidGRS80,
+			CentralMeridian:   3,
+			LatitudeOfOrigin:  46.5,
+			StandardParallel1: 49,
+			StandardParallel2: 44,
+			FalseEasting:      700000,
+			FalseNorthing:     6600000,
+		}, nil
+
+	case code == 5070:
+		return AlbersEqualArea{
+			Ellipsoid:         EllipsoidGRS80,
+			CentralMeridian:   -96,
+			LatitudeOfOrigin:  23,
+			StandardParallel1: 29.5,
+			StandardParallel2: 45.5,
+		}, nil
+
+	case code == 3310:
+		return AlbersEqualArea{
+			Ellipsoid:         EllipsoidGRS80,
+			CentralMeridian:   -120,
+			LatitudeOfOrigin:  0,
+			StandardParallel1: 34,
+			StandardParallel2: 40.5,
+			FalseNorthing:     -4000000,
+		}, nil
+
+	case code == 26943:
+		return LambertConformalConic{
+			Ellipsoid:         EllipsoidGRS80,
+			CentralMeridian:   -120.5,
+			LatitudeOfOrigin:  36.5,
+			StandardParallel1: 38 + 26.0/60,
+			StandardParallel2: 37 + 4.0/60,
+			FalseEasting:      2000000,
+			FalseNorthing:     500000,
+		}, nil
+
+	case code == 32118:
+		return LambertConformalConic{
+			Ellipsoid:         EllipsoidGRS80,
+			CentralMeridian:   -74,
+			LatitudeOfOrigin:  40 + 10.0/60,
+			StandardParallel1: 41 + 2.0/60,
+			StandardParallel2: 40 + 40.0/60,
+			FalseEasting:      300000,
+		}, nil
+	}
+
+	return nil, derp.Internal(location, "Unsupported EPSG code", code)
+}
+
+// utmProjection returns the Transverse Mercator projection for a UTM zone on any ellipsoid
+func utmProjection(ellipsoid Ellipsoid, zone int, south bool) TransverseMercator {
+
+	result := utmTransverseMercator(zone)
+	result.Ellipsoid = ellipsoid
+
+	if south {
+		result.FalseNorthing = utmFalseNorthingSouth
+	}
+
+	return result
+}
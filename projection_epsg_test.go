@@ -0,0 +1,76 @@
+package geo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestProjectionByEPSG(t *testing.T) {
+
+	codes := []int{4326, 3857, 32601, 32660, 32701, 32760, 25828, 25838, 26903, 26923, 27700, 2154, 5070, 3310, 26943, 32118}
+
+	for _, code := range codes {
+		projection, err := ProjectionByEPSG(code)
+		require.Nil(t, err, code)
+		require.NotNil(t, projection, code)
+	}
+}
+
+func TestProjectionByEPSG_Unknown(t *testing.T) {
+
+	for _, code := range []int{0, 4269, 32600, 32661, 32700, 99999} {
+		_, err := ProjectionByEPSG(code)
+		require.NotNil(t, err, code)
+	}
+}
+
+func TestProjectionByEPSG_UTMSouth(t *testing.T) {
+
+	projection, err := ProjectionByEPSG(32756)
+	require.Nil(t, err)
+
+	result, err := projection.Forward(NewPosition(151.2153, -33.8568))
+	require.Nil(t, err)
+
+	utm, err := NewPosition(151.2153, -33.8568).UTM()
+	require.Nil(t, err)
+	require.InDelta(t, utm.Easting, result.Longitude, 0.001)
+	require.InDelta(t, utm.Northing, result.Latitude, 0.001)
+}
+
+func TestProjectionByEPSG_BritishNationalGrid(t *testing.T) {
+
+	projection, err := ProjectionByEPSG(27700)
+	require.Nil(t, err)
+
+	// Big Ben, which the Ordnance Survey places at roughly E 530268, N 179640
+	result, err := projection.Forward(NewPosition(-0.1246, 51.5007))
+	require.Nil(t, err)
+	require.InDelta(t, 530268, result.Longitude, 50)
+	require.InDelta(t, 179640, result.Latitude, 50)
+
+	back, err := projection.Inverse(result)
+	require.Nil(t, err)
+	require.InDelta(t, -0.1246, back.Longitude, 0.0000001)
+	require.InDelta(t, 51.5007, back.Latitude, 0.0000001)
+}
+
+func TestProjectionByEPSG_StatePlane(t *testing.T) {
+
+	for code, position := range map[int]Position{
+		26943: NewPosition(-122.4194, 37.7749), // San Francisco
+		32118: NewPosition(-73.9857, 40.7484),  // Empire State Building
+	} {
+		projection, err := ProjectionByEPSG(code)
+		require.Nil(t, err)
+
+		projected, err := projection.Forward(position)
+		require.Nil(t, err)
+
+		back, err := projection.Inverse(projected)
+		require.Nil(t, err)
+		require.InDelta(t, position.Longitude, back.Longitude, 0.00000001)
+		require.InDelta(t, position.Latitude, back.Latitude, 0.00000001)
+	}
+}
@@ -0,0 +1,48 @@
+package geo
+
+// arcSecondsToRadians converts the rotation parameters of a Helmert transformation
+const arcSecondsToRadians = 4.84813681109536e-6
+
+// Helmert is a seven-parameter (position vector) Helmert transformation that
+// shifts geodetic Positions from WGS84 onto another datum (EPSG method 9606).
+// Forward converts WGS84 into the target datum, and Inverse converts back. The
+// inverse uses negated parameters, which is accurate to a few millimeters for
+// the small rotations used between terrestrial datums.
+// https://en.wikipedia.org/wiki/Helmert_transformation
+type Helmert struct {
+	Ellipsoid    Ellipsoid // Reference ellipsoid of the target datum
+	TranslationX float64   // Translation along the X axis, in meters
+	TranslationY float64   // Translation along the Y axis, in meters
+	TranslationZ float64   // Translation along the Z axis, in meters
+	RotationX    float64   // Rotation around the X axis, in arc-seconds
+	RotationY    float64   // Rotation around the Y axis, in arc-seconds
+	RotationZ    float64   // Rotation around the Z axis, in arc-seconds
+	Scale        float64   // Scale correction, in parts per million
+}
+
+// Forward converts a WGS84 Position into a Position on the target datum
+func (helmert Helmert) Forward(position Position) (Position, error) {
+	x, y, z := EllipsoidWGS84.toCartesian(position)
+	x, y, z = helmert.apply(x, y, z, 1)
+	return helmert.Ellipsoid.fromCartesian(x, y, z), nil
+}
+
+// Inverse converts a Position on the target datum back into WGS84
+func (helmert Helmert) Inverse(position Position) (Position, error) {
+	x, y, z := helmert.Ellipsoid.toCartesian(position)
+	x, y, z = helmert.apply(x, y, z, -1)
+	return EllipsoidWGS84.fromCartesian(x, y, z), nil
+}
+
+// apply transforms cartesian coordinates, using each parameter multiplied by `sign`
+func (helmert Helmert) apply(x float64, y float64, z float64, sign float64) (float64, float64, float64) {
+
+	scale := 1 + sign*helmert.Scale*1e-6
+	rx := sign * helmert.RotationX * arcSecondsToRadians
+	ry := sign * helmert.RotationY * arcSecondsToRadians
+	rz := sign * helmert.RotationZ * arcSecondsToRadians
+
+	return sign*helmert.TranslationX + scale*(x-rz*y+ry*z),
+		sign*helmert.TranslationY + scale*(rz*x+y-rx*z),
+		sign*helmert.TranslationZ + scale*(-ry*x+rx*y+z)
+}
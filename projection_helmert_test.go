@@ -0,0 +1,39 @@
+package geo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHelmert_OSGB36(t *testing.T) {
+
+	projection, err := ProjectionByEPSG(27700)
+	require.Nil(t, err)
+
+	helmert := projection.(Pipeline)[0]
+
+	// The datum shift moves positions in Great Britain by roughly 100 meters
+	position := NewPosition(-0.1246, 51.5007)
+	shifted, err := helmert.Forward(position)
+	require.Nil(t, err)
+	require.InDelta(t, -0.1230, shifted.Longitude, 0.0005)
+	require.InDelta(t, 51.5002, shifted.Latitude, 0.0005)
+
+	back, err := helmert.Inverse(shifted)
+	require.Nil(t, err)
+	require.InDelta(t, position.Longitude, back.Longitude, 0.0000001)
+	require.InDelta(t, position.Latitude, back.Latitude, 0.0000001)
+	require.InDelta(t, 0, back.Altitude, 0.05)
+}
+
+func TestHelmert_Identity(t *testing.T) {
+
+	position := NewPositionWithAltitude(12.5, -45.25, 100)
+
+	result, err := Helmert{Ellipsoid: EllipsoidWGS84}.Forward(position)
+	require.Nil(t, err)
+	require.InDelta(t, position.Longitude, result.Longitude, 0.000000001)
+	require.InDelta(t, position.Latitude, result.Latitude, 0.000000001)
+	require.InDelta(t, position.Altitude, result.Altitude, 0.0001)
+}
@@ -0,0 +1,123 @@
+package geo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransform_Identity(t *testing.T) {
+
+	position := NewPositionWithAltitude(-79.9822, 40.4461, 250)
+	result, err := Transform(position, ProjectionWGS84, ProjectionWGS84)
+	require.Nil(t, err)
+	require.Equal(t, position, result)
+}
+
+func TestTransform_WebMercator(t *testing.T) {
+
+	result, err := Transform(NewPosition(180, 0), ProjectionWGS84, ProjectionWebMercator)
+	require.Nil(t, err)
+	require.InDelta(t, 20037508.34, result.Longitude, 0.01)
+	require.InDelta(t, 0, result.Latitude, 0.01)
+
+	back, err := Transform(result, ProjectionWebMercator, ProjectionWGS84)
+	require.Nil(t, err)
+	require.InDelta(t, 180, back.Longitude, 0.0000001)
+}
+
+func TestTransform_BetweenProjections(t *testing.T) {
+
+	utm31, err := ProjectionByEPSG(32631)
+	require.Nil(t, err)
+
+	lambert93, err := ProjectionByEPSG(2154)
+	require.Nil(t, err)
+
+	// Eiffel Tower, from UTM zone 31 into Lambert-93
+	result, err := Transform(NewPosition(448251.8, 5411932.7), utm31, lambert93)
+	require.Nil(t, err)
+
+	expected, err := lambert93.Forward(NewPosition(2.2945, 48.8582))
+	require.Nil(t, err)
+	require.InDelta(t, expected.Longitude, result.Longitude, 0.5)
+	require.InDelta(t, expected.Latitude, result.Latitude, 0.5)
+}
+
+func TestTransform_Error(t *testing.T) {
+
+	_, err := Transform(NewPosition(0, 100), ProjectionWGS84, utmTransverseMercator(31))
+	require.NotNil(t, err)
+}
+
+func TestPipeline(t *testing.T) {
+
+	pipeline := Pipeline{
+		Helmert{Ellipsoid: EllipsoidInternational1924, TranslationX: -87, TranslationY: -98, TranslationZ: -121},
+		TransverseMercator{Ellipsoid: EllipsoidInternational1924, CentralMeridian: 3, ScaleFactor: 0.9996, FalseEasting: 500000},
+	}
+
+	position := NewPosition(2.2945, 48.8582)
+
+	projected, err := pipeline.Forward(position)
+	require.Nil(t, err)
+	require.InDelta(t, 448252, projected.Longitude, 300)
+	require.InDelta(t, 5411933, projected.Latitude, 300)
+
+	back, err := pipeline.Inverse(projected)
+	require.Nil(t, err)
+	require.InDelta(t, position.Longitude, back.Longitude, 0.000001)
+	require.InDelta(t, position.Latitude, back.Latitude, 0.000001)
+}
+
+func TestPipeline_Empty(t *testing.T) {
+
+	position := NewPosition(1, 2)
+
+	result, err := Pipeline{}.Forward(position)
+	require.Nil(t, err)
+	require.Equal(t, position, result)
+
+	result, err = Pipeline{}.Inverse(position)
+	require.Nil(t, err)
+	require.Equal(t, position, result)
+}
+
+func TestPolygon_Transform(t *testing.T) {
+
+	polygon := NewPolygon(NewPosition(2, 48), NewPosition(3, 48), NewPosition(3, 49), NewPosition(2, 48))
+
+	projected, err := polygon.Transform(ProjectionWGS84, ProjectionWebMercator)
+	require.Nil(t, err)
+	require.Equal(t, 4, len(projected.Coordinates))
+
+	back, err := projected.Transform(ProjectionWebMercator, ProjectionWGS84)
+	require.Nil(t, err)
+
+	for index, position := range back.Coordinates {
+		require.InDelta(t, polygon.Coordinates[index].Longitude, position.Longitude, 0.0000001)
+		require.InDelta(t, polygon.Coordinates[index].Latitude, position.Latitude, 0.0000001)
+	}
+}
+
+func TestLineString_Transform(t *testing.T) {
+
+	lineString := NewLineString(NewPosition(0, 0), NewPosition(0, 200))
+
+	_, err := lineString.Transform(ProjectionWGS84, ProjectionWebMercator)
+	require.Nil(t, err)
+
+	_, err = lineString.Transform(ProjectionWGS84, utmTransverseMercator(31))
+	require.NotNil(t, err)
+}
+
+func TestPoint_Transform(t *testing.T) {
+
+	utm31, err := ProjectionByEPSG(32631)
+	require.Nil(t, err)
+
+	result, err := NewPoint(2.2945, 48.8582).Transform(ProjectionWGS84, utm31)
+	require.Nil(t, err)
+	require.InDelta(t, 448251.8, result.Longitude, 0.5)
+	require.InDelta(t, 5411932.7, result.Latitude, 0.5)
+}
@@ -0,0 +1,162 @@
+package geo
+
+import (
+	"math"
+
+	"github.com/benpate/derp"
+)
+
+// TransverseMercator is the Transverse Mercator (Gauss-Krüger) projection, used by
+// UTM, the British National Grid, and many national and state-plane grids.
+// It uses the Krüger series to third order in n, which is accurate to well under
+// a millimeter within a few thousand kilometers of the central meridian.
+// https://en.wikipedia.org/wiki/Transverse_Mercator_projection
+type TransverseMercator struct {
+	Ellipsoid        Ellipsoid // Reference ellipsoid of the projected coordinate system
+	CentralMeridian  float64   // Longitude of the natural origin, in degrees
+	LatitudeOfOrigin float64   // Latitude of the natural origin, in degrees
+	ScaleFactor      float64   // Scale factor on the central meridian
+	FalseEasting     float64   // Easting of the natural origin, in meters
+	FalseNorthing    float64   // Northing of the natural origin, in meters
+}
+
+// Forward converts a Position (on this projection's ellipsoid) into easting and northing
+func (projection TransverseMercator) Forward(position Position) (Position, error) {
+
+	const location = "geo.TransverseMercator.Forward"
+
+	if err := validateLongitudeLatitude(position); err != nil {
+		return Position{}, derp.Wrap(err, location, "Invalid position", position)
+	}
+
+	series := newKruegerSeries(projection.Ellipsoid)
+	deltaLongitude := normalizeLongitude(position.Longitude - projection.CentralMeridian)
+
+	x, y := series.forward(degreesToRadians(position.Latitude), degreesToRadians(deltaLongitude))
+	_, originY := series.forward(degreesToRadians(projection.LatitudeOfOrigin), 0)
+
+	return NewPositionWithAltitude(
+		projection.FalseEasting+projection.ScaleFactor*x,
+		projection.FalseNorthing+projection.ScaleFactor*(y-originY),
+		position.Altitude,
+	), nil
+}
+
+// Inverse converts an easting and northing back into a Position on this projection's ellipsoid
+func (projection TransverseMercator) Inverse(position Position) (Position, error) {
+
+	const location = "geo.TransverseMercator.Inverse"
+
+	if projection.ScaleFactor == 0 {
+		return Position{}, derp.Internal(location, "Scale factor must not be zero", projection)
+	}
+
+	series := newKruegerSeries(projection.Ellipsoid)
+	_, originY := series.forward(degreesToRadians(projection.LatitudeOfOrigin), 0)
+
+	latitude, deltaLongitude := series.inverse(
+		(position.Longitude-projection.FalseEasting)/projection.ScaleFactor,
+		(position.Latitude-projection.FalseNorthing)/projection.ScaleFactor+originY,
+	)
+
+	return NewPositionWithAltitude(
+		normalizeLongitude(projection.CentralMeridian+radiansToDegrees(deltaLongitude)),
+		radiansToDegrees(latitude),
+		position.Altitude,
+	), nil
+}
+
+// kruegerSeries holds the Krüger series coefficients for a Transverse Mercator
+// projection on a single ellipsoid.
+// https://en.wikipedia.org/wiki/Universal_Transverse_Mercator_coordinate_system#Simplified_formulae
+type kruegerSeries struct {
+	rectifyingRadius float64    // A: the radius of the rectifying sphere
+	sqrtN            float64    // 2√n / (1+n), used in the conformal latitude
+	alpha            [3]float64 // forward series
+	beta             [3]float64 // inverse series
+	delta            [3]float64 // conformal-to-geodetic latitude series
+}
+
+// newKruegerSeries computes the series coefficients for an ellipsoid
+func newKruegerSeries(ellipsoid Ellipsoid) kruegerSeries {
+
+	n := ellipsoid.Flattening / (2 - ellipsoid.Flattening)
+	n2 := n * n
+	n3 := n2 * n
+
+	return kruegerSeries{
+		rectifyingRadius: ellipsoid.SemiMajorAxis / (1 + n) * (1 + n2/4 + n2*n2/64),
+		sqrtN:            2 * math.Sqrt(n) / (1 + n),
+		alpha: [3]float64{
+			n/2 - 2*n2/3 + 5*n3/16,
+			13*n2/48 - 3*n3/5,
+			61 * n3 / 240,
+		},
+		beta: [3]float64{
+			n/2 - 2*n2/3 + 37*n3/96,
+			n2/48 + n3/15,
+			17 * n3 / 480,
+		},
+		delta: [3]float64{
+			2*n - 2*n2/3 - 2*n3,
+			7*n2/3 - 8*n3/5,
+			56 * n3 / 15,
+		},
+	}
+}
+
+// forward projects a latitude and a longitude offset from the central meridian
+// (both in radians) onto an unscaled, un-offset plane.
+func (series kruegerSeries) forward(latitude float64, deltaLongitude float64) (x float64, y float64) {
+
+	sinLatitude := math.Sin(latitude)
+	t := math.Sinh(math.Atanh(sinLatitude) - series.sqrtN*math.Atanh(series.sqrtN*sinLatitude))
+
+	xiPrime := math.Atan2(t, math.Cos(deltaLongitude))
+	etaPrime := math.Atanh(math.Sin(deltaLongitude) / math.Sqrt(1+t*t))
+
+	xi, eta := xiPrime, etaPrime
+
+	for index, alpha := range series.alpha {
+		j := float64(2 * (index + 1))
+		xi += alpha * math.Sin(j*xiPrime) * math.Cosh(j*etaPrime)
+		eta += alpha * math.Cos(j*xiPrime) * math.Sinh(j*etaPrime)
+	}
+
+	return series.rectifyingRadius * eta, series.rectifyingRadius * xi
+}
+
+// inverse reverses forward, returning the latitude and longitude offset
+// from the central meridian (both in radians).
+func (series kruegerSeries) inverse(x float64, y float64) (latitude float64, deltaLongitude float64) {
+
+	xi := y / series.rectifyingRadius
+	eta := x / series.rectifyingRadius
+
+	xiPrime, etaPrime := xi, eta
+
+	for index, beta := range series.beta {
+		j := float64(2 * (index + 1))
+		xiPrime -= beta * math.Sin(j*xi) * math.Cosh(j*eta)
+		etaPrime -= beta * math.Cos(j*xi) * math.Sinh(j*eta)
+	}
+
+	chi := math.Asin(math.Sin(xiPrime) / math.Cosh(etaPrime))
+	latitude = chi
+
+	for index, delta := range series.delta {
+		latitude += delta * math.Sin(float64(2*(index+1))*chi)
+	}
+
+	return latitude, math.Atan2(math.Sinh(etaPrime), math.Cos(xiPrime))
+}
+
+// normalizeLongitude wraps a longitude into the range [-180, 180]
+func normalizeLongitude(longitude float64) float64 {
+
+	if (longitude >= -180) && (longitude <= 180) {
+		return longitude
+	}
+
+	return math.Mod(math.Mod(longitude+180, 360)+360, 360) - 180
+}
@@ -0,0 +1,53 @@
+package geo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransverseMercator_BritishNationalGrid(t *testing.T) {
+
+	// Worked example from the Ordnance Survey's "A guide to coordinate systems in Great Britain"
+	projection := TransverseMercator{
+		Ellipsoid:        EllipsoidAiry1830,
+		CentralMeridian:  -2,
+		LatitudeOfOrigin: 49,
+		ScaleFactor:      0.9996012717,
+		FalseEasting:     400000,
+		FalseNorthing:    -100000,
+	}
+
+	result, err := projection.Forward(NewPosition(1.71792158333, 52.65757030556))
+	require.Nil(t, err)
+	require.InDelta(t, 651409.903, result.Longitude, 0.001)
+	require.InDelta(t, 313177.270, result.Latitude, 0.001)
+
+	back, err := projection.Inverse(result)
+	require.Nil(t, err)
+	require.InDelta(t, 1.71792158333, back.Longitude, 0.00000001)
+	require.InDelta(t, 52.65757030556, back.Latitude, 0.00000001)
+}
+
+func TestTransverseMercator_Origin(t *testing.T) {
+
+	projection := TransverseMercator{
+		Ellipsoid:        EllipsoidGRS80,
+		CentralMeridian:  10,
+		LatitudeOfOrigin: 45,
+		ScaleFactor:      1,
+		FalseEasting:     1000,
+		FalseNorthing:    2000,
+	}
+
+	result, err := projection.Forward(NewPosition(10, 45))
+	require.Nil(t, err)
+	require.InDelta(t, 1000, result.Longitude, 0.000001)
+	require.InDelta(t, 2000, result.Latitude, 0.000001)
+}
+
+func TestTransverseMercator_ScaleFactor(t *testing.T) {
+
+	_, err := TransverseMercator{Ellipsoid: EllipsoidWGS84}.Inverse(NewPosition(0, 0))
+	require.NotNil(t, err)
+}
@@ -7,6 +7,18 @@ const (
 
 	// PropertyCoordinates is the GeoJSON "coordinates" property.
 	PropertyCoordinates = "coordinates"
+
+	// PropertyID is the (optional) GeoJSON "id" property of a Feature.
+	PropertyID = "id"
+
+	// PropertyGeometry is the GeoJSON "geometry" property of a Feature.
+	PropertyGeometry = "geometry"
+
+	// PropertyProperties is the GeoJSON "properties" property of a Feature.
+	PropertyProperties = "properties"
+
+	// PropertyFeatures is the GeoJSON "features" property of a FeatureCollection.
+	PropertyFeatures = "features"
 )
 
 // GeoJSON "type" values supported by this package.
@@ -14,6 +26,15 @@ const (
 	// PropertyTypePoint is the GeoJSON type value for a Point.
 	PropertyTypePoint = "Point"
 
+	// PropertyTypeLineString is the GeoJSON type value for a LineString.
+	PropertyTypeLineString = "LineString"
+
 	// PropertyTypePolygon is the GeoJSON type value for a Polygon.
 	PropertyTypePolygon = "Polygon"
+
+	// PropertyTypeFeature is the GeoJSON type value for a Feature.
+	PropertyTypeFeature = "Feature"
+
+	// PropertyTypeFeatureCollection is the GeoJSON type value for a FeatureCollection.
+	PropertyTypeFeatureCollection = "FeatureCollection"
 )
@@ -0,0 +1,254 @@
+package geo
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"iter"
+
+	"github.com/benpate/derp"
+)
+
+// Media types for the GeoJSON formats read and written by this package
+const (
+	// MediaTypeGeoJSON is the media type of a GeoJSON document (RFC 7946)
+	MediaTypeGeoJSON = "application/geo+json"
+
+	// MediaTypeGeoJSONSeq is the media type of a GeoJSON Text Sequence (RFC 8142)
+	MediaTypeGeoJSONSeq = "application/geo+json-seq"
+
+	// MediaTypeNDJSON is the (unregistered) media type of newline-delimited JSON
+	MediaTypeNDJSON = "application/x-ndjson"
+)
+
+// Record separators for sequences of GeoJSON texts
+const (
+	// sequenceRecordSeparator (RS, 0x1E) begins every record in a GeoJSON Text Sequence
+	sequenceRecordSeparator = 0x1E
+
+	// sequenceLineFeed (LF, 0x0A) ends every record in both formats
+	sequenceLineFeed = '\n'
+)
+
+/******************************************
+ * Reading
+ ******************************************/
+
+// SequenceReader reads records, one at a time, from a GeoJSON Text Sequence
+// (RFC 8142) or from newline-delimited GeoJSON (NDJSON). Each record may be a
+// Feature, a FeatureCollection, or a bare geometry.
+type SequenceReader struct {
+	reader    *bufio.Reader
+	separator byte
+}
+
+// NewGeoJSONSeqReader returns a SequenceReader for an "application/geo+json-seq" stream,
+// in which each record begins with an RS (0x1E) character and ends with a line feed.
+func NewGeoJSONSeqReader(reader io.Reader) *SequenceReader {
+	return &SequenceReader{
+		reader:    bufio.NewReader(reader),
+		separator: sequenceRecordSeparator,
+	}
+}
+
+// NewNDJSONReader returns a SequenceReader for newline-delimited GeoJSON,
+// in which each record is on its own line. Blank lines are ignored.
+func NewNDJSONReader(reader io.Reader) *SequenceReader {
+	return &SequenceReader{
+		reader:    bufio.NewReader(reader),
+		separator: sequenceLineFeed,
+	}
+}
+
+// Features returns an iterator over the Features in this sequence. Bare geometries
+// are returned as Features with no ID or properties, and FeatureCollections are
+// returned one Feature at a time. An invalid or truncated record yields an
+// error and iteration continues with the next record, as RFC 8142 requires.
+// The underlying io.Reader is consumed, so the iterator can only be used once.
+func (reader *SequenceReader) Features() iter.Seq2[Feature, error] {
+
+	const location = "geo.SequenceReader.Features"
+
+	return func(yield func(Feature, error) bool) {
+
+		for {
+			record, err := reader.reader.ReadBytes(reader.separator)
+			done := (err == io.EOF)
+
+			if (err != nil) && !done {
+				yield(Feature{}, derp.Wrap(err, location, "Unable to read record"))
+				return
+			}
+
+			if !reader.readRecord(bytes.TrimSuffix(record, []byte{reader.separator}), yield) {
+				return
+			}
+
+			if done {
+				return
+			}
+		}
+	}
+}
+
+// readRecord yields the Feature(s) in a single record. It returns FALSE if the caller is done.
+func (reader *SequenceReader) readRecord(record []byte, yield func(Feature, error) bool) bool {
+
+	const location = "geo.SequenceReader.readRecord"
+
+	// Empty records (such as consecutive RS characters, or blank lines) are ignored
+	record = bytes.TrimSpace(record)
+
+	if len(record) == 0 {
+		return true
+	}
+
+	// Every record must be a complete JSON object. Anything else was truncated or is malformed.
+	if !json.Valid(record) {
+		return yield(Feature{}, derp.Internal(location, "Truncated or invalid JSON record", string(record)))
+	}
+
+	intermediate := struct {
+		Type string `json:"type"`
+	}{}
+
+	if err := json.Unmarshal(record, &intermediate); err != nil {
+		return yield(Feature{}, derp.Wrap(err, location, "Invalid GeoJSON. Record must be an object", string(record)))
+	}
+
+	switch intermediate.Type {
+
+	case PropertyTypeFeature:
+		feature := Feature{}
+
+		if err := feature.UnmarshalJSON(record); err != nil {
+			return yield(Feature{}, derp.Wrap(err, location, "Invalid feature"))
+		}
+
+		return yield(feature, nil)
+
+	case PropertyTypeFeatureCollection:
+		for feature, err := range NewFeatureReader(bytes.NewReader(record)).Features() {
+			if !yield(feature, err) {
+				return false
+			}
+		}
+
+		return true
+	}
+
+	geometry, err := UnmarshalGeometryJSON(record)
+
+	if err != nil {
+		return yield(Feature{}, derp.Wrap(err, location, "Invalid geometry"))
+	}
+
+	return yield(Feature{Geometry: geometry}, nil)
+}
+
+/******************************************
+ * Writing
+ ******************************************/
+
+// SequenceWriter writes records, one at a time, to a GeoJSON Text Sequence (RFC 8142)
+// or to newline-delimited GeoJSON (NDJSON). Wrap the destination in a bufio.Writer
+// when it is a file or network connection.
+type SequenceWriter struct {
+	writer io.Writer
+	prefix []byte
+}
+
+// NewGeoJSONSeqWriter returns a SequenceWriter for an "application/geo+json-seq" stream
+func NewGeoJSONSeqWriter(writer io.Writer) *SequenceWriter {
+	return &SequenceWriter{
+		writer: writer,
+		prefix: []byte{sequenceRecordSeparator},
+	}
+}
+
+// NewNDJSONWriter returns a SequenceWriter for newline-delimited GeoJSON
+func NewNDJSONWriter(writer io.Writer) *SequenceWriter {
+	return &SequenceWriter{
+		writer: writer,
+	}
+}
+
+// WriteFeature writes a single Feature as one record
+func (writer *SequenceWriter) WriteFeature(feature Feature) error {
+
+	const location = "geo.SequenceWriter.WriteFeature"
+
+	data, err := feature.MarshalJSON()
+
+	if err != nil {
+		return derp.Wrap(err, location, "Unable to marshal feature", feature.ID)
+	}
+
+	if err := writer.writeRecord(data); err != nil {
+		return derp.Wrap(err, location, "Unable to write feature", feature.ID)
+	}
+
+	return nil
+}
+
+// WriteGeometry writes a single (non-empty) Geometry as one record
+func (writer *SequenceWriter) WriteGeometry(geometry Geometry) error {
+
+	const location = "geo.SequenceWriter.WriteGeometry"
+
+	// Empty geometries marshal as `null`, which is not a GeoJSON object
+	if (geometry == nil) || geometry.IsZero() {
+		return derp.Internal(location, "Geometry must not be empty")
+	}
+
+	data, err := json.Marshal(geometry)
+
+	if err != nil {
+		return derp.Wrap(err, location, "Unable to marshal geometry", geometry)
+	}
+
+	if err := writer.writeRecord(data); err != nil {
+		return derp.Wrap(err, location, "Unable to write geometry")
+	}
+
+	return nil
+}
+
+// WriteAll writes every Feature from an iterator (such as SequenceReader.Features
+// or FeatureReader.Features) as a separate record. It stops at the first error.
+func (writer *SequenceWriter) WriteAll(features iter.Seq2[Feature, error]) error {
+
+	const location = "geo.SequenceWriter.WriteAll"
+
+	for feature, err := range features {
+
+		if err != nil {
+			return derp.Wrap(err, location, "Unable to read feature")
+		}
+
+		if err := writer.WriteFeature(feature); err != nil {
+			return derp.Wrap(err, location, "Unable to write feature")
+		}
+	}
+
+	return nil
+}
+
+// writeRecord writes a single record, with its prefix (if any) and trailing line feed.
+// The record is written in one call, so that concurrent appends to a log are not interleaved.
+func (writer *SequenceWriter) writeRecord(data []byte) error {
+
+	const location = "geo.SequenceWriter.writeRecord"
+
+	record := make([]byte, 0, len(writer.prefix)+len(data)+1)
+	record = append(record, writer.prefix...)
+	record = append(record, data...)
+	record = append(record, sequenceLineFeed)
+
+	if _, err := writer.writer.Write(record); err != nil {
+		return derp.Wrap(err, location, "Unable to write record")
+	}
+
+	return nil
+}
@@ -0,0 +1,182 @@
+package geo
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/benpate/rosetta/mapof"
+	"github.com/stretchr/testify/require"
+)
+
+// readSequence collects every Feature and error from a SequenceReader
+func readSequence(reader *SequenceReader) ([]Feature, []error) {
+
+	var features []Feature
+	var errors []error
+
+	for feature, err := range reader.Features() {
+		if err != nil {
+			errors = append(errors, err)
+			continue
+		}
+		features = append(features, feature)
+	}
+
+	return features, errors
+}
+
+func TestGeoJSONSeqReader(t *testing.T) {
+
+	data := "\x1e" + `{"type":"Feature","id":"a","geometry":{"type":"Point","coordinates":[1,2]},"properties":null}` + "\n" +
+		"\x1e" + `{"type":"Point","coordinates":[3,4]}` + "\n" +
+		"\x1e\x1e" + // Consecutive separators are ignored
+		"\x1e" + "{\n\"type\": \"LineString\",\n\"coordinates\": [[1,2],[3,4]]\n}\n"
+
+	features, errors := readSequence(NewGeoJSONSeqReader(strings.NewReader(data)))
+
+	require.Empty(t, errors)
+	require.Equal(t, []Feature{
+		{ID: "a", Geometry: NewPoint(1, 2)},
+		{Geometry: NewPoint(3, 4)},
+		{Geometry: NewLineString(NewPosition(1, 2), NewPosition(3, 4))},
+	}, features)
+}
+
+func TestGeoJSONSeqReader_Truncated(t *testing.T) {
+
+	// A truncated record is reported, and reading continues with the next record
+	data := "\x1e" + `{"type":"Point","coordinates":[1,2]}` + "\n" +
+		"\x1e" + `{"type":"Point","coordi` +
+		"\x1e" + `{"type":"Point","coordinates":[3,4]}` + "\n" +
+		"\x1e" + `{"type":"Point","coordinates":[5,`
+
+	features, errors := readSequence(NewGeoJSONSeqReader(strings.NewReader(data)))
+
+	require.Equal(t, 2, len(errors))
+	require.Equal(t, []Feature{{Geometry: NewPoint(1, 2)}, {Geometry: NewPoint(3, 4)}}, features)
+}
+
+func TestGeoJSONSeqReader_FeatureCollection(t *testing.T) {
+
+	data := "\x1e" + `{"type":"FeatureCollection","features":[{"type":"Feature","id":1,"geometry":null},{"type":"Feature","id":2,"geometry":null}]}` + "\n"
+
+	features, errors := readSequence(NewGeoJSONSeqReader(strings.NewReader(data)))
+
+	require.Empty(t, errors)
+	require.Equal(t, []Feature{{ID: float64(1)}, {ID: float64(2)}}, features)
+}
+
+func TestGeoJSONSeqReader_Invalid(t *testing.T) {
+
+	data := "garbage\n" +
+		"\x1e" + `12` + "\n" +
+		"\x1e" + `{"type":"MultiPoint","coordinates":[[1,2]]}` + "\n" +
+		"\x1e" + `{"type":"Feature","geometry":{"type":"Point"}}` + "\n" +
+		"\x1e" + `{"type":"Point","coordinates":[1,2]}` + "\n"
+
+	features, errors := readSequence(NewGeoJSONSeqReader(strings.NewReader(data)))
+
+	require.Equal(t, 4, len(errors))
+	require.Equal(t, []Feature{{Geometry: NewPoint(1, 2)}}, features)
+}
+
+func TestGeoJSONSeqReader_Break(t *testing.T) {
+
+	data := strings.Repeat("\x1e"+`{"type":"Point","coordinates":[1,2]}`+"\n", 10)
+
+	var count int
+
+	for range NewGeoJSONSeqReader(strings.NewReader(data)).Features() {
+		count++
+		break
+	}
+
+	require.Equal(t, 1, count)
+}
+
+func TestNDJSONReader(t *testing.T) {
+
+	data := `{"type":"Feature","id":"a","geometry":{"type":"Point","coordinates":[1,2]},"properties":{"name":"A"}}` + "\r\n" +
+		"\n" +
+		`{"type":"Point","coordinates":[3,4]}` + "\n" +
+		`{"type":"Point",` + "\n" +
+		`{"type":"Point","coordinates":[5,6]}` // No trailing newline
+
+	features, errors := readSequence(NewNDJSONReader(strings.NewReader(data)))
+
+	require.Equal(t, 1, len(errors))
+	require.Equal(t, []Feature{
+		{ID: "a", Geometry: NewPoint(1, 2), Properties: mapof.Any{"name": "A"}},
+		{Geometry: NewPoint(3, 4)},
+		{Geometry: NewPoint(5, 6)},
+	}, features)
+}
+
+func TestGeoJSONSeqWriter(t *testing.T) {
+
+	buffer := bytes.Buffer{}
+	writer := NewGeoJSONSeqWriter(&buffer)
+
+	require.Nil(t, writer.WriteFeature(Feature{ID: "a", Geometry: NewPoint(1, 2)}))
+	require.Nil(t, writer.WriteGeometry(NewPoint(3, 4)))
+
+	expected := "\x1e" + `{"type":"Feature","id":"a","geometry":{"type":"Point","coordinates":[1,2]},"properties":null}` + "\n" +
+		"\x1e" + `{"type":"Point","coordinates":[3,4]}` + "\n"
+
+	require.Equal(t, expected, buffer.String())
+}
+
+func TestNDJSONWriter(t *testing.T) {
+
+	buffer := bytes.Buffer{}
+	writer := NewNDJSONWriter(&buffer)
+
+	require.Nil(t, writer.WriteGeometry(NewPoint(1, 2)))
+	require.Nil(t, writer.WriteFeature(Feature{Properties: mapof.Any{"note": "line one\nline two"}}))
+
+	expected := `{"type":"Point","coordinates":[1,2]}` + "\n" +
+		`{"type":"Feature","geometry":null,"properties":{"note":"line one\nline two"}}` + "\n"
+
+	require.Equal(t, expected, buffer.String())
+}
+
+func TestSequenceWriter_EmptyGeometry(t *testing.T) {
+
+	writer := NewNDJSONWriter(&bytes.Buffer{})
+
+	require.NotNil(t, writer.WriteGeometry(nil))
+	require.NotNil(t, writer.WriteGeometry(Point{}))
+}
+
+func TestSequenceWriter_RoundTrip(t *testing.T) {
+
+	original := []Feature{
+		{ID: "a", Geometry: NewPoint(1, 2), Properties: mapof.Any{"name": "A"}},
+		{ID: "b", Geometry: NewPolygon(NewPosition(1, 2), NewPosition(3, 4), NewPosition(5, 6), NewPosition(1, 2))},
+	}
+
+	features := func(yield func(Feature, error) bool) {
+		for _, feature := range original {
+			if !yield(feature, nil) {
+				return
+			}
+		}
+	}
+
+	// GeoJSON Text Sequence
+	seq := bytes.Buffer{}
+	require.Nil(t, NewGeoJSONSeqWriter(&seq).WriteAll(features))
+
+	result, errors := readSequence(NewGeoJSONSeqReader(&seq))
+	require.Empty(t, errors)
+	require.Equal(t, original, result)
+
+	// NDJSON
+	ndjson := bytes.Buffer{}
+	require.Nil(t, NewNDJSONWriter(&ndjson).WriteAll(features))
+
+	result, errors = readSequence(NewNDJSONReader(&ndjson))
+	require.Empty(t, errors)
+	require.Equal(t, original, result)
+}
@@ -0,0 +1,317 @@
+package geo
+
+import (
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/benpate/derp"
+)
+
+// MaxTileZoom is the deepest zoom level supported by Tile calculations
+const MaxTileZoom = 30
+
+// MaxTilesCovering is the largest number of candidate tiles (the tiles in a
+// geometry's bounding box) that TilesCovering will check
+const MaxTilesCovering = 1 << 20
+
+// Tile identifies a single slippy-map tile, using the z/x/y scheme
+// shared by OpenStreetMap, Google, and most web map libraries.
+// https://wiki.openstreetmap.org/wiki/Slippy_map_tilenames
+type Tile struct {
+	Z int // Zoom level, from 0 to MaxTileZoom
+	X int // Column, counting east from the antimeridian
+	Y int // Row, counting south from the north edge of the map
+}
+
+// NewTile returns the Tile at the given zoom, column, and row
+func NewTile(z int, x int, y int) Tile {
+	return Tile{Z: z, X: x, Y: y}
+}
+
+// Tile returns the Tile that contains this Position at the given zoom level.
+// Zoom levels are clamped to between 0 and MaxTileZoom.
+func (position Position) Tile(zoom int) Tile {
+
+	zoom = clampZoom(zoom)
+	fractionX, fractionY := position.mercatorFraction()
+	size := 1 << zoom
+
+	return Tile{
+		Z: zoom,
+		X: clampTileIndex(int(math.Floor(fractionX*float64(size))), size),
+		Y: clampTileIndex(int(math.Floor(fractionY*float64(size))), size),
+	}
+}
+
+// ParseQuadKey parses a Bing Maps quadkey into a Tile
+// https://learn.microsoft.com/en-us/bingmaps/articles/bing-maps-tile-system
+func ParseQuadKey(quadKey string) (Tile, error) {
+
+	const location = "geo.ParseQuadKey"
+
+	if len(quadKey) > MaxTileZoom {
+		return Tile{}, derp.Internal(location, "QuadKey is too long", quadKey)
+	}
+
+	result := Tile{Z: len(quadKey)}
+
+	for _, digit := range quadKey {
+
+		result.X <<= 1
+		result.Y <<= 1
+
+		switch digit {
+		case '0':
+		case '1':
+			result.X |= 1
+		case '2':
+			result.Y |= 1
+		case '3':
+			result.X |= 1
+			result.Y |= 1
+		default:
+			return Tile{}, derp.Internal(location, "QuadKey digits must be 0, 1, 2, or 3", quadKey)
+		}
+	}
+
+	return result, nil
+}
+
+// IsValid returns TRUE if this Tile exists at its zoom level
+func (tile Tile) IsValid() bool {
+
+	if (tile.Z < 0) || (tile.Z > MaxTileZoom) {
+		return false
+	}
+
+	size := 1 << tile.Z
+	return (tile.X >= 0) && (tile.X < size) && (tile.Y >= 0) && (tile.Y < size)
+}
+
+// String returns this Tile in "z/x/y" format, as used in tile URLs
+func (tile Tile) String() string {
+	return strconv.Itoa(tile.Z) + "/" + strconv.Itoa(tile.X) + "/" + strconv.Itoa(tile.Y)
+}
+
+// QuadKey returns the Bing Maps quadkey for this Tile
+// https://learn.microsoft.com/en-us/bingmaps/articles/bing-maps-tile-system
+func (tile Tile) QuadKey() string {
+
+	var builder strings.Builder
+
+	for bit := tile.Z; bit > 0; bit-- {
+
+		mask := 1 << (bit - 1)
+		digit := byte('0')
+
+		if tile.X&mask != 0 {
+			digit++
+		}
+
+		if tile.Y&mask != 0 {
+			digit += 2
+		}
+
+		builder.WriteByte(digit)
+	}
+
+	return builder.String()
+}
+
+// BoundingBox returns the area covered by this Tile
+func (tile Tile) BoundingBox() BoundingBox {
+
+	size := float64(int(1) << tile.Z)
+	northWest := positionFromMercatorFraction(float64(tile.X)/size, float64(tile.Y)/size)
+	southEast := positionFromMercatorFraction(float64(tile.X+1)/size, float64(tile.Y+1)/size)
+
+	return NewBoundingBox(northWest.Longitude, southEast.Latitude, southEast.Longitude, northWest.Latitude)
+}
+
+// Parent returns the Tile one zoom level up that contains this Tile.
+// Tiles at zoom level 0 are their own parent.
+func (tile Tile) Parent() Tile {
+
+	if tile.Z == 0 {
+		return tile
+	}
+
+	return Tile{Z: tile.Z - 1, X: tile.X >> 1, Y: tile.Y >> 1}
+}
+
+// Children returns the four Tiles one zoom level down that make up this Tile
+func (tile Tile) Children() []Tile {
+
+	z, x, y := tile.Z+1, tile.X<<1, tile.Y<<1
+
+	return []Tile{
+		{Z: z, X: x, Y: y},
+		{Z: z, X: x + 1, Y: y},
+		{Z: z, X: x, Y: y + 1},
+		{Z: z, X: x + 1, Y: y + 1},
+	}
+}
+
+// TilesCovering returns every Tile at the given zoom level that the geometry
+// touches, in row-major order (north to south, then west to east). Points return
+// a single Tile, LineStrings return the Tiles crossed by each segment, and
+// Polygons return the Tiles that share any area with the polygon's ring.
+// Geometries that cross the antimeridian are not supported. It returns an error
+// if the geometry's bounding box spans more than MaxTilesCovering tiles, so use a
+// lower zoom level for large geometries.
+func TilesCovering(geometry Geometry, zoom int) ([]Tile, error) {
+
+	const location = "geo.TilesCovering"
+
+	// Use the values inside pointers, so that a *Polygon is covered like a Polygon
+	geometry = dereferenceGeometry(geometry)
+
+	if (geometry == nil) || geometry.IsZero() {
+		return []Tile{}, nil
+	}
+
+	zoom = clampZoom(zoom)
+	bounds := geometry.BoundingBox()
+
+	// Find the range of tiles that contain the bounding box
+	northWest := NewPosition(bounds.West, bounds.North).Tile(zoom)
+	southEast := NewPosition(bounds.East, bounds.South).Tile(zoom)
+
+	if count := (southEast.X - northWest.X + 1) * (southEast.Y - northWest.Y + 1); count > MaxTilesCovering {
+		return nil, derp.Internal(location, "Geometry covers too many tiles at this zoom level", zoom, count)
+	}
+
+	// Choose the test for each candidate tile
+	var touches func(BoundingBox) bool
+
+	switch typed := geometry.(type) {
+
+	case LineString:
+		touches = func(box BoundingBox) bool {
+			return lineStringIntersectsBox(typed.Coordinates, box)
+		}
+
+	case Polygon:
+		touches = func(box BoundingBox) bool {
+			return polygonIntersectsBox(typed.Coordinates, box)
+		}
+
+	default:
+		touches = func(BoundingBox) bool {
+			return true
+		}
+	}
+
+	result := make([]Tile, 0)
+
+	for y := northWest.Y; y <= southEast.Y; y++ {
+		for x := northWest.X; x <= southEast.X; x++ {
+			tile := NewTile(zoom, x, y)
+			if touches(tile.BoundingBox()) {
+				result = append(result, tile)
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// lineStringIntersectsBox returns TRUE if any segment of the line touches the box
+func lineStringIntersectsBox(coordinates []Position, box BoundingBox) bool {
+
+	if len(coordinates) == 1 {
+		return box.Contains(coordinates[0])
+	}
+
+	for index := 1; index < len(coordinates); index++ {
+		if segmentIntersectsBox(coordinates[index-1], coordinates[index], box) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// polygonIntersectsBox returns TRUE if the ring (implicitly closed) shares any area with the box
+func polygonIntersectsBox(ring []Position, box BoundingBox) bool {
+
+	// Any edge touching the box means that they intersect
+	for index := range ring {
+		if segmentIntersectsBox(ring[index], ring[(index+1)%len(ring)], box) {
+			return true
+		}
+	}
+
+	// Otherwise, the box is either entirely inside or entirely outside of the ring
+	return ringContains(ring, box.Center())
+}
+
+// segmentIntersectsBox returns TRUE if the line segment from a to b touches the box,
+// using Liang-Barsky clipping
+func segmentIntersectsBox(a Position, b Position, box BoundingBox) bool {
+
+	deltaX := b.Longitude - a.Longitude
+	deltaY := b.Latitude - a.Latitude
+
+	low, high := 0.0, 1.0
+
+	// clip narrows [low, high] for one edge of the box, and returns FALSE if nothing remains
+	clip := func(p float64, q float64) bool {
+
+		if p == 0 {
+			return q >= 0
+		}
+
+		r := q / p
+
+		if p < 0 {
+			if r > high {
+				return false
+			}
+			low = max(low, r)
+		} else {
+			if r < low {
+				return false
+			}
+			high = min(high, r)
+		}
+
+		return true
+	}
+
+	return clip(-deltaX, a.Longitude-box.West) &&
+		clip(deltaX, box.East-a.Longitude) &&
+		clip(-deltaY, a.Latitude-box.South) &&
+		clip(deltaY, box.North-a.Latitude)
+}
+
+// ringContains returns TRUE if the position is inside the ring, using the even-odd rule
+func ringContains(ring []Position, position Position) bool {
+
+	result := false
+
+	for index, previous := 0, len(ring)-1; index < len(ring); previous, index = index, index+1 {
+
+		a, b := ring[index], ring[previous]
+
+		if (a.Latitude > position.Latitude) != (b.Latitude > position.Latitude) {
+			crossing := a.Longitude + (position.Latitude-a.Latitude)*(b.Longitude-a.Longitude)/(b.Latitude-a.Latitude)
+			if position.Longitude < crossing {
+				result = !result
+			}
+		}
+	}
+
+	return result
+}
+
+// clampZoom limits a zoom level to between 0 and MaxTileZoom
+func clampZoom(zoom int) int {
+	return max(0, min(zoom, MaxTileZoom))
+}
+
+// clampTileIndex limits a tile row or column to the tiles that exist at a zoom level
+func clampTileIndex(index int, size int) int {
+	return max(0, min(index, size-1))
+}
@@ -0,0 +1,171 @@
+package geo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPosition_Tile(t *testing.T) {
+
+	position := NewPosition(-122.4194, 37.7749)
+
+	require.Equal(t, NewTile(0, 0, 0), position.Tile(0))
+	require.Equal(t, NewTile(10, 163, 395), position.Tile(10))
+
+	// Zoom levels and map edges are clamped
+	require.Equal(t, 0, position.Tile(-1).Z)
+	require.Equal(t, MaxTileZoom, position.Tile(99).Z)
+	require.Equal(t, NewTile(2, 3, 0), NewPosition(180, 90).Tile(2))
+	require.Equal(t, NewTile(2, 0, 3), NewPosition(-180, -90).Tile(2))
+}
+
+func TestTile_String(t *testing.T) {
+	require.Equal(t, "10/163/395", NewTile(10, 163, 395).String())
+}
+
+func TestTile_IsValid(t *testing.T) {
+	require.True(t, NewTile(0, 0, 0).IsValid())
+	require.True(t, NewTile(2, 3, 3).IsValid())
+	require.False(t, NewTile(2, 4, 0).IsValid())
+	require.False(t, NewTile(2, 0, -1).IsValid())
+	require.False(t, NewTile(-1, 0, 0).IsValid())
+	require.False(t, NewTile(MaxTileZoom+1, 0, 0).IsValid())
+}
+
+func TestTile_QuadKey(t *testing.T) {
+
+	// The example from the Bing Maps tile system documentation
+	require.Equal(t, "213", NewTile(3, 3, 5).QuadKey())
+	require.Equal(t, "", NewTile(0, 0, 0).QuadKey())
+
+	result, err := ParseQuadKey("213")
+	require.Nil(t, err)
+	require.Equal(t, NewTile(3, 3, 5), result)
+
+	_, err = ParseQuadKey("214")
+	require.NotNil(t, err)
+
+	_, err = ParseQuadKey("0123012301230123012301230123012")
+	require.NotNil(t, err)
+}
+
+func TestTile_BoundingBox(t *testing.T) {
+
+	box := NewTile(0, 0, 0).BoundingBox()
+	require.InDelta(t, -180, box.West, 0.0000001)
+	require.InDelta(t, 180, box.East, 0.0000001)
+	require.InDelta(t, WebMercatorMaxLatitude, box.North, 0.0000001)
+	require.InDelta(t, -WebMercatorMaxLatitude, box.South, 0.0000001)
+
+	// A Tile contains the positions that map into it
+	position := NewPosition(-122.4194, 37.7749)
+	require.True(t, position.Tile(14).BoundingBox().Contains(position))
+}
+
+func TestTile_Family(t *testing.T) {
+
+	tile := NewTile(3, 3, 5)
+	require.Equal(t, NewTile(2, 1, 2), tile.Parent())
+	require.Equal(t, NewTile(0, 0, 0), NewTile(0, 0, 0).Parent())
+
+	for _, child := range tile.Children() {
+		require.Equal(t, tile, child.Parent())
+	}
+}
+
+func TestTilesCovering_Point(t *testing.T) {
+	point := NewPoint(-122.4194, 37.7749)
+	require.Equal(t, []Tile{NewTile(10, 163, 395)}, mustTilesCovering(t, point, 10))
+	require.Empty(t, mustTilesCovering(t, Point{}, 10))
+	require.Empty(t, mustTilesCovering(t, nil, 10))
+}
+
+func TestTilesCovering_Polygon(t *testing.T) {
+
+	// A triangle covering the western half of the world at zoom 2 touches
+	// every tile in the western half, except the south-eastern one
+	triangle := NewPolygon(
+		NewPosition(-179, 80),
+		NewPosition(-1, 80),
+		NewPosition(-179, -80),
+	)
+
+	require.Equal(t, []Tile{
+		NewTile(2, 0, 0), NewTile(2, 1, 0),
+		NewTile(2, 0, 1), NewTile(2, 1, 1),
+		NewTile(2, 0, 2), NewTile(2, 1, 2),
+		NewTile(2, 0, 3),
+	}, mustTilesCovering(t, triangle, 2))
+
+	// A polygon that surrounds a tile also covers it
+	square := NewBoundingBox(-100, -60, 100, 60).Polygon()
+	require.Contains(t, mustTilesCovering(t, square, 2), NewTile(2, 1, 1))
+	require.Equal(t, 8, len(mustTilesCovering(t, square, 2)))
+}
+
+func TestTilesCovering_LineString(t *testing.T) {
+
+	// A diagonal line crosses the prime meridian north of the equator,
+	// so it never touches the south-western tile
+	line := NewLineString(
+		NewPosition(-170, 80),
+		NewPosition(170, -70),
+	)
+
+	require.Equal(t, []Tile{
+		NewTile(1, 0, 0), NewTile(1, 1, 0),
+		NewTile(1, 1, 1),
+	}, mustTilesCovering(t, line, 1))
+}
+
+func TestTilesCovering_Pointers(t *testing.T) {
+
+	// Pointers are covered exactly like the values they point to
+	triangle := NewPolygon(
+		NewPosition(-179, 80),
+		NewPosition(-1, 80),
+		NewPosition(-179, -80),
+	)
+
+	line := NewLineString(
+		NewPosition(-170, 80),
+		NewPosition(170, -70),
+	)
+
+	point := NewPoint(-122.4194, 37.7749)
+
+	require.Equal(t, mustTilesCovering(t, triangle, 2), mustTilesCovering(t, &triangle, 2))
+	require.Equal(t, mustTilesCovering(t, line, 1), mustTilesCovering(t, &line, 1))
+	require.Equal(t, mustTilesCovering(t, point, 10), mustTilesCovering(t, &point, 10))
+
+	// Nil pointers are empty geometries
+	require.Empty(t, mustTilesCovering(t, (*Polygon)(nil), 2))
+	require.Empty(t, mustTilesCovering(t, (*LineString)(nil), 2))
+	require.Empty(t, mustTilesCovering(t, (*Point)(nil), 2))
+}
+
+func TestTilesCovering_TooMany(t *testing.T) {
+
+	square := NewBoundingBox(-100, -60, 100, 60).Polygon()
+
+	_, err := TilesCovering(square, MaxTileZoom)
+	require.NotNil(t, err)
+
+	_, err = TilesCovering(square, 12)
+	require.NotNil(t, err)
+
+	// Small geometries still work at the deepest zoom level
+	small := NewBoundingBox(-122.4194, 37.7749, -122.4193, 37.7750).Polygon()
+	tiles, err := TilesCovering(small, MaxTileZoom)
+	require.Nil(t, err)
+	require.NotEmpty(t, tiles)
+	require.LessOrEqual(t, len(tiles), MaxTilesCovering)
+}
+
+// mustTilesCovering returns the tiles that a geometry covers, failing the test on errors
+func mustTilesCovering(t *testing.T, geometry Geometry, zoom int) []Tile {
+	result, err := TilesCovering(geometry, zoom)
+	require.Nil(t, err)
+	return result
+}
@@ -0,0 +1,181 @@
+package geo
+
+import (
+	"bytes"
+	"compress/gzip"
+	_ "embed"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/benpate/derp"
+)
+
+//go:generate go run boundary_generate.go -timezones timezones-with-oceans.geojson -timezones-out data/timezones.tsv.gz
+
+// The embedded time zone table is generated from timezone-boundary-builder's time zone
+// boundaries (including its nautical time zones at sea) by boundary_generate.go, and is gzipped. Each line holds a tz database name
+// ("America/Denver") and one polygon, in the same format as the boundary table.
+
+//go:embed data/timezones.tsv.gz
+var timezonesTSVGZ []byte
+
+// TimezoneIndex finds the time zone that contains a position, using a table of
+// simplified time zone boundaries. It is safe for concurrent use once it has been created.
+type TimezoneIndex struct {
+	table polygonTable
+}
+
+// defaultTimezoneIndex parses the embedded time zone table the first time it is used
+var defaultTimezoneIndex = sync.OnceValue(func() *TimezoneIndex {
+
+	reader, err := gzip.NewReader(bytes.NewReader(timezonesTSVGZ))
+
+	if err != nil {
+		panic("geo: invalid embedded time zone table: " + err.Error())
+	}
+
+	result, err := NewTimezoneIndex(reader)
+
+	if err != nil {
+		panic("geo: invalid embedded time zone table: " + err.Error())
+	}
+
+	return result
+})
+
+// NewTimezoneIndex reads a (decompressed) time zone table in the same format as the
+// embedded one (see boundary_generate.go), such as a more detailed table that you generate yourself.
+func NewTimezoneIndex(reader io.Reader) (*TimezoneIndex, error) {
+
+	const location = "geo.NewTimezoneIndex"
+
+	table, err := readPolygonTable(reader)
+
+	if err != nil {
+		return nil, derp.Wrap(err, location, "Unable to read time zone table")
+	}
+
+	return &TimezoneIndex{table: table}, nil
+}
+
+// Len returns the number of polygons in this TimezoneIndex
+func (index *TimezoneIndex) Len() int {
+	return len(index.table.polygons)
+}
+
+// TimezoneAt returns the name of the tz database time zone that contains a position,
+// such as "America/Denver". Positions outside of every time zone boundary (such as
+// positions in international waters) use the nautical time zone for their longitude,
+// such as "Etc/GMT+5" for 75°W (which is UTC-5, because the Etc names use POSIX signs).
+func (index *TimezoneIndex) TimezoneAt(position Position) (string, error) {
+
+	const location = "geo.TimezoneIndex.TimezoneAt"
+
+	if math.IsNaN(position.Latitude) || (position.Latitude < -90) || (position.Latitude > 90) {
+		return "", derp.Internal(location, "Latitude must be between -90 and 90", position)
+	}
+
+	if math.IsNaN(position.Longitude) || (position.Longitude < -180) || (position.Longitude > 180) {
+		return "", derp.Internal(location, "Longitude must be between -180 and 180", position)
+	}
+
+	for name := range index.table.containing(position) {
+		return name, nil
+	}
+
+	return nauticalTimezone(position.Longitude), nil
+}
+
+// nauticalTimezone returns the "Etc/GMT" time zone for a longitude in international waters.
+// Each nautical zone is 15° wide and centered on a multiple of 15°, so 7.5°W to 7.5°E is
+// "Etc/GMT", and 172.5°E to 180° is "Etc/GMT-12". Positions exactly on the edge between two
+// zones use the one farther from Greenwich.
+func nauticalTimezone(longitude float64) string {
+
+	offset := int(math.Round(longitude / 15))
+
+	switch {
+
+	case offset == 0:
+		return "Etc/GMT"
+
+	// Etc/GMT names use POSIX signs, which are the opposite of ISO 8601
+	case offset > 0:
+		return "Etc/GMT-" + strconv.Itoa(offset)
+
+	default:
+		return "Etc/GMT+" + strconv.Itoa(-offset)
+	}
+}
+
+// TimezoneAt returns the name of the tz database time zone that contains a position, using
+// the embedded time zone table. Positions in international waters use the nautical
+// time zone for their longitude (see TimezoneIndex.TimezoneAt). No network access is needed.
+func TimezoneAt(position Position) (string, error) {
+
+	const location = "geo.TimezoneAt"
+
+	index := defaultTimezoneIndex()
+
+	// Without boundaries, every position would look like it is in international waters
+	if index.Len() == 0 {
+		return "", derp.NotImplemented(location, "The embedded time zone table is empty. Run `go generate` to build it.")
+	}
+
+	return index.TimezoneAt(position)
+}
+
+// UpdateTimezone sets the Timezone of this Address from its coordinates, using the
+// embedded time zone table. It returns an error, and leaves the Address unchanged,
+// if the Address has no coordinates or the time zone cannot be found.
+func (address *Address) UpdateTimezone() error {
+
+	const location = "geo.Address.UpdateTimezone"
+
+	if !address.HasGeocode() {
+		return derp.Validation("Address must have coordinates to find its time zone")
+	}
+
+	timezone, err := TimezoneAt(address.GeoPoint().Position)
+
+	if err != nil {
+		return derp.Wrap(err, location, "Unable to find time zone", address.LatLon())
+	}
+
+	address.Timezone = timezone
+	return nil
+}
+
+// Location returns the time.Location for this Address's Timezone, which can be used
+// to show times at the Address (as in time.Now().In(address.Location())). It returns
+// time.UTC if the Timezone is empty or unknown, or is "Local" (which would be the
+// time zone of the computer running the program, not of the Address).
+func (address Address) Location() *time.Location {
+	result, _ := address.LocationOK()
+	return result
+}
+
+// LocationOK returns the time.Location for this Address's Timezone, and TRUE if the
+// Timezone was found. Otherwise, it returns time.UTC and FALSE. Time zones are loaded
+// from the operating system; programs that run where there is no time zone database
+// should import "time/tzdata".
+func (address Address) LocationOK() (*time.Location, bool) {
+
+	timezone := strings.TrimSpace(address.Timezone)
+
+	if (timezone == "") || (timezone == "Local") {
+		return time.UTC, false
+	}
+
+	result, err := time.LoadLocation(timezone)
+
+	if err != nil {
+		return time.UTC, false
+	}
+
+	return result, true
+}
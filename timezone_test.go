@@ -0,0 +1,254 @@
+package geo
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/benpate/derp"
+	"github.com/stretchr/testify/require"
+)
+
+// testTimezoneIndex returns a time zone table of rough rectangles, which are only test fixtures
+// (not real boundaries). Denver and Chicago share an edge at 102.04°W, and Rome has a hole for the Vatican.
+func testTimezoneIndex(t *testing.T) *TimezoneIndex {
+
+	box := func(west float64, south float64, east float64, north float64) string {
+		return mustEncodePolyline(NewBoundingBox(west, south, east, north).Polygon().Coordinates, boundaryPrecision)
+	}
+
+	table := strings.Join([]string{
+		"America/Denver\t" + box(-109.05, 37, -102.04, 41),
+		"America/Chicago\t" + box(-102.04, 37, -94.6, 40),
+		"Europe/Rome\t" + box(12.3, 41.8, 12.6, 42) + "\t" + box(12.445, 41.9, 12.458, 41.907),
+		"Europe/Vatican\t" + box(12.445, 41.9, 12.458, 41.907),
+	}, "\n")
+
+	index, err := NewTimezoneIndex(strings.NewReader(table))
+	require.Nil(t, err)
+	return index
+}
+
+func TestTimezoneIndex_TimezoneAt(t *testing.T) {
+
+	index := testTimezoneIndex(t)
+	require.Equal(t, 4, index.Len())
+
+	tests := []struct {
+		longitude float64
+		latitude  float64
+		expected  string
+	}{
+		{-104.99, 39.74, "America/Denver"},   // Denver
+		{-97.34, 37.69, "America/Chicago"},   // Wichita
+		{-102.05, 38, "America/Denver"},      // Just west of the border
+		{-102.03, 38, "America/Chicago"},     // Just east of the border
+		{-102.03, 40.5, "Etc/GMT+7"},         // East of the border, but north of the Chicago rectangle
+		{12.4964, 41.9028, "Europe/Rome"},    // Rome
+		{12.4534, 41.9029, "Europe/Vatican"}, // St. Peter's Basilica, in the hole
+	}
+
+	for _, test := range tests {
+		result, err := index.TimezoneAt(NewPosition(test.longitude, test.latitude))
+		require.Nil(t, err)
+		require.Equal(t, test.expected, result, test)
+	}
+}
+
+func TestTimezoneIndex_TimezoneAt_Oceans(t *testing.T) {
+
+	index := testTimezoneIndex(t)
+
+	tests := []struct {
+		longitude float64
+		expected  string
+		offset    int // Hours east of UTC
+	}{
+		{0, "Etc/GMT", 0},
+		{7.49, "Etc/GMT", 0},
+		{-7.49, "Etc/GMT", 0},
+		{7.5, "Etc/GMT-1", 1},
+		{-7.5, "Etc/GMT+1", -1},
+		{-30, "Etc/GMT+2", -2},
+		{-75, "Etc/GMT+5", -5},
+		{-140, "Etc/GMT+9", -9},
+		{90, "Etc/GMT-6", 6},
+		{172.4, "Etc/GMT-11", 11},
+		{172.6, "Etc/GMT-12", 12},
+		{180, "Etc/GMT-12", 12},
+		{-172.6, "Etc/GMT+12", -12},
+		{-180, "Etc/GMT+12", -12},
+	}
+
+	for _, test := range tests {
+
+		result, err := index.TimezoneAt(NewPosition(test.longitude, -20))
+		require.Nil(t, err)
+		require.Equal(t, test.expected, result, test.longitude)
+
+		// Every nautical time zone must exist, with the right offset
+		location, err := time.LoadLocation(result)
+		require.Nil(t, err, result)
+
+		_, offset := time.Date(2024, 6, 1, 0, 0, 0, 0, location).Zone()
+		require.Equal(t, test.offset*3600, offset, result)
+	}
+}
+
+func TestTimezoneIndex_TimezoneAt_Invalid(t *testing.T) {
+
+	index := testTimezoneIndex(t)
+
+	for _, position := range []Position{
+		NewPosition(0, 91),
+		NewPosition(0, -91),
+		NewPosition(181, 0),
+		NewPosition(-181, 0),
+		NewPosition(math.NaN(), 0),
+		NewPosition(0, math.NaN()),
+	} {
+		_, err := index.TimezoneAt(position)
+		require.NotNil(t, err, position)
+	}
+}
+
+func TestTimezoneAt_EmptyTable(t *testing.T) {
+
+	original := defaultTimezoneIndex
+	defaultTimezoneIndex = func() *TimezoneIndex { return &TimezoneIndex{} }
+	defer func() { defaultTimezoneIndex = original }()
+
+	// Without boundaries, land positions must not be reported as international waters
+	_, err := TimezoneAt(NewPosition(-104.99, 39.74))
+	require.True(t, derp.IsNotImplemented(err))
+}
+
+func TestDefaultTimezoneIndex(t *testing.T) {
+
+	// The embedded table must always decompress and parse, and must not be empty
+	index := defaultTimezoneIndex()
+	require.NotNil(t, index)
+	require.Greater(t, index.Len(), 0)
+}
+
+func TestTimezoneAt(t *testing.T) {
+
+	// These use the embedded timezone-boundary-builder boundaries. Each pair of towns is on
+	// either side of the line between Mountain and Central time, from Texas to North Dakota.
+	tests := []struct {
+		longitude float64
+		latitude  float64
+		expected  string
+	}{
+		{-104.99, 39.74, "America/Denver"},  // Denver
+		{-97.34, 37.69, "America/Chicago"},  // Wichita
+		{-106.49, 31.76, "America/Denver"},  // El Paso, Texas
+		{-104.83, 31.04, "America/Chicago"}, // Van Horn, Texas
+		{-101.75, 37.98, "America/Denver"},  // Syracuse, Kansas
+		{-101.26, 37.94, "America/Chicago"}, // Lakin, Kansas
+		{-101.71, 39.35, "America/Denver"},  // Goodland, Kansas
+		{-101.05, 39.4, "America/Chicago"},  // Colby, Kansas
+		{-101.72, 41.13, "America/Denver"},  // Ogallala, Nebraska
+		{-100.77, 41.12, "America/Chicago"}, // North Platte, Nebraska
+		{-102.2, 42.8, "America/Denver"},    // Gordon, Nebraska
+		{-100.55, 42.87, "America/Chicago"}, // Valentine, Nebraska
+		{-101.51, 43.83, "America/Denver"},  // Kadoka, South Dakota
+		{-100.71, 43.89, "America/Chicago"}, // Murdo, South Dakota
+		{-102.79, 46.88, "America/Denver"},  // Dickinson, North Dakota
+		{-100.78, 46.81, "America/Chicago"}, // Bismarck, North Dakota
+		{-112.07, 33.45, "America/Phoenix"}, // Phoenix
+		{12.4964, 41.9028, "Europe/Rome"},   // Rome
+		{2.35, 48.86, "Europe/Paris"},       // Paris
+		{139.69, 35.69, "Asia/Tokyo"},       // Tokyo
+	}
+
+	for _, test := range tests {
+		result, err := TimezoneAt(NewPosition(test.longitude, test.latitude))
+		require.Nil(t, err, test)
+		require.Equal(t, test.expected, result, test)
+	}
+}
+
+func TestTimezoneAt_Oceans(t *testing.T) {
+
+	// timezone-boundary-builder includes its own nautical time zones, so positions
+	// at sea must be found in the embedded table, and agree with nauticalTimezone
+	tests := []struct {
+		longitude float64
+		latitude  float64
+		expected  string
+	}{
+		{-30, 0, "Etc/GMT+2"},       // The middle of the Atlantic
+		{-45, 40, "Etc/GMT+3"},      // The North Atlantic
+		{-140, -20, "Etc/GMT+9"},    // The South Pacific
+		{80, -30, "Etc/GMT-5"},      // The Indian Ocean
+		{0, -60, "Etc/GMT"},         // The Southern Ocean
+		{179.9, -40, "Etc/GMT-12"},  // East of New Zealand
+		{-179.9, -40, "Etc/GMT+12"}, // Across the antimeridian
+	}
+
+	index := defaultTimezoneIndex()
+
+	for _, test := range tests {
+
+		position := NewPosition(test.longitude, test.latitude)
+
+		var found []string
+		for name := range index.table.containing(position) {
+			found = append(found, name)
+		}
+
+		require.Equal(t, []string{test.expected}, found, test)
+		require.Equal(t, test.expected, nauticalTimezone(test.longitude), test)
+
+		result, err := TimezoneAt(position)
+		require.Nil(t, err, test)
+		require.Equal(t, test.expected, result, test)
+	}
+}
+
+func TestAddress_UpdateTimezone(t *testing.T) {
+
+	original := defaultTimezoneIndex
+	index := testTimezoneIndex(t)
+	defaultTimezoneIndex = func() *TimezoneIndex { return index }
+	defer func() { defaultTimezoneIndex = original }()
+
+	address := Address{Latitude: 39.74, Longitude: -104.99}
+	require.Nil(t, address.UpdateTimezone())
+	require.Equal(t, "America/Denver", address.Timezone)
+
+	address = Address{Latitude: 30, Longitude: -45}
+	require.Nil(t, address.UpdateTimezone())
+	require.Equal(t, "Etc/GMT+3", address.Timezone)
+
+	// Addresses without coordinates are not changed
+	address = Address{Timezone: "Europe/London"}
+	require.True(t, derp.IsValidationError(address.UpdateTimezone()))
+	require.Equal(t, "Europe/London", address.Timezone)
+
+	// Neither are addresses with invalid coordinates
+	address = Address{Timezone: "Europe/London", Latitude: 100, Longitude: 0}
+	require.NotNil(t, address.UpdateTimezone())
+	require.Equal(t, "Europe/London", address.Timezone)
+}
+
+func TestAddress_Location(t *testing.T) {
+
+	location, ok := Address{Timezone: "America/Denver"}.LocationOK()
+	require.True(t, ok)
+	require.Equal(t, "America/Denver", location.String())
+	require.Equal(t, "America/Denver", Address{Timezone: " America/Denver "}.Location().String())
+
+	// Times at the address use its time zone
+	moment := time.Date(2024, 7, 4, 18, 0, 0, 0, time.UTC).In(Address{Timezone: "America/Denver"}.Location())
+	require.Equal(t, 12, moment.Hour())
+
+	for _, timezone := range []string{"", "Local", "Not/A_Zone"} {
+		location, ok := Address{Timezone: timezone}.LocationOK()
+		require.False(t, ok, timezone)
+		require.Equal(t, time.UTC, location, timezone)
+		require.Equal(t, time.UTC, Address{Timezone: timezone}.Location(), timezone)
+	}
+}
@@ -0,0 +1,167 @@
+package geo
+
+import (
+	"math"
+	"strconv"
+
+	"github.com/benpate/derp"
+)
+
+// Hemisphere values used by UTM coordinates
+const (
+	// UTMHemisphereNorth marks a UTM coordinate north of the equator (false northing 0m)
+	UTMHemisphereNorth = "N"
+
+	// UTMHemisphereSouth marks a UTM coordinate south of the equator (false northing 10,000,000m)
+	UTMHemisphereSouth = "S"
+)
+
+// Constants that define the UTM grid
+const (
+	utmScaleFactor        = 0.9996
+	utmFalseEasting       = 500000.0
+	utmFalseNorthingSouth = 10000000.0
+	utmMinimumLatitude    = -80.0
+	utmMaximumLatitude    = 84.0
+)
+
+// UTM represents a Universal Transverse Mercator grid coordinate on the WGS84 ellipsoid
+// https://en.wikipedia.org/wiki/Universal_Transverse_Mercator_coordinate_system
+type UTM struct {
+	Zone       int     // Longitude zone, from 1 to 60
+	Hemisphere string  // UTMHemisphereNorth or UTMHemisphereSouth
+	Easting    float64 // Meters east, including the 500,000m false easting
+	Northing   float64 // Meters north, including the 10,000,000m false northing in the southern hemisphere
+}
+
+// UTM converts this Position into UTM coordinates, in the zone that contains it
+// (including the Norway and Svalbard exceptions). UTM is only defined between
+// 80°S and 84°N; positions outside of that range return an error.
+func (position Position) UTM() (UTM, error) {
+
+	const location = "geo.Position.UTM"
+
+	if err := validateLongitudeLatitude(position); err != nil {
+		return UTM{}, derp.Wrap(err, location, "Invalid position", position)
+	}
+
+	if (position.Latitude < utmMinimumLatitude) || (position.Latitude > utmMaximumLatitude) {
+		return UTM{}, derp.Internal(location, "UTM is only defined between 80°S and 84°N", position)
+	}
+
+	zone := utmZone(position.Longitude, position.Latitude)
+	projected, err := utmTransverseMercator(zone).Forward(position)
+
+	if err != nil {
+		return UTM{}, derp.Wrap(err, location, "Unable to project position", position)
+	}
+
+	result := UTM{
+		Zone:       zone,
+		Hemisphere: UTMHemisphereNorth,
+		Easting:    projected.Longitude,
+		Northing:   projected.Latitude,
+	}
+
+	if position.Latitude < 0 {
+		result.Hemisphere = UTMHemisphereSouth
+		result.Northing += utmFalseNorthingSouth
+	}
+
+	return result, nil
+}
+
+// Position converts these UTM coordinates back into a (longitude, latitude) Position.
+func (utm UTM) Position() (Position, error) {
+
+	const location = "geo.UTM.Position"
+
+	if (utm.Zone < 1) || (utm.Zone > 60) {
+		return Position{}, derp.Internal(location, "UTM zone must be between 1 and 60", utm)
+	}
+
+	if (utm.Easting <= 0) || (utm.Easting >= 2*utmFalseEasting) {
+		return Position{}, derp.Internal(location, "UTM easting must be between 0 and 1,000,000 meters", utm)
+	}
+
+	if (utm.Northing < 0) || (utm.Northing > utmFalseNorthingSouth) {
+		return Position{}, derp.Internal(location, "UTM northing must be between 0 and 10,000,000 meters", utm)
+	}
+
+	northing := utm.Northing
+
+	switch utm.Hemisphere {
+
+	case UTMHemisphereNorth:
+
+	case UTMHemisphereSouth:
+		northing -= utmFalseNorthingSouth
+
+	default:
+		return Position{}, derp.Internal(location, "UTM hemisphere must be 'N' or 'S'", utm)
+	}
+
+	result, err := utmTransverseMercator(utm.Zone).Inverse(NewPosition(utm.Easting, northing))
+
+	if err != nil {
+		return Position{}, derp.Wrap(err, location, "Unable to un-project UTM coordinates", utm)
+	}
+
+	return result, nil
+}
+
+// String returns these UTM coordinates as "zone hemisphere easting northing",
+// rounded to the nearest meter, such as "17N 589634 4477360"
+func (utm UTM) String() string {
+	return strconv.Itoa(utm.Zone) + utm.Hemisphere + " " +
+		strconv.FormatFloat(math.Round(utm.Easting), 'f', 0, 64) + " " +
+		strconv.FormatFloat(math.Round(utm.Northing), 'f', 0, 64)
+}
+
+// utmZone returns the UTM zone that contains a longitude and latitude,
+// including the irregular zones around Norway and Svalbard.
+func utmZone(longitude float64, latitude float64) int {
+
+	zone := int(math.Floor((longitude+180)/6)) + 1
+
+	// Longitude 180 belongs to zone 60, not a (non-existent) zone 61
+	if zone > 60 {
+		zone = 60
+	}
+
+	// Zone 32V is widened to cover all of south-western Norway
+	if (latitude >= 56) && (latitude < 64) && (longitude >= 3) && (longitude < 12) {
+		return 32
+	}
+
+	// Zones 32X, 34X, and 36X are not used. Svalbard uses wider 31X, 33X, 35X, and 37X instead.
+	if (latitude >= 72) && (longitude >= 0) && (longitude < 42) {
+		switch {
+		case longitude < 9:
+			return 31
+		case longitude < 21:
+			return 33
+		case longitude < 33:
+			return 35
+		default:
+			return 37
+		}
+	}
+
+	return zone
+}
+
+// utmTransverseMercator returns the (northern hemisphere) projection for a UTM zone
+func utmTransverseMercator(zone int) TransverseMercator {
+	return TransverseMercator{
+		Ellipsoid:       EllipsoidWGS84,
+		CentralMeridian: utmCentralMeridian(zone),
+		ScaleFactor:     utmScaleFactor,
+		FalseEasting:    utmFalseEasting,
+	}
+}
+
+// utmCentralMeridian returns the longitude (in degrees) of the center of a UTM zone
+func utmCentralMeridian(zone int) float64 {
+	return float64((zone-1)*6-180) + 3
+}
@@ -0,0 +1,123 @@
+package geo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPosition_UTM(t *testing.T) {
+
+	// Eiffel Tower
+	result, err := NewPosition(2.2945, 48.8582).UTM()
+	require.Nil(t, err)
+	require.Equal(t, 31, result.Zone)
+	require.Equal(t, UTMHemisphereNorth, result.Hemisphere)
+	require.InDelta(t, 448251.8, result.Easting, 0.5)
+	require.InDelta(t, 5411932.7, result.Northing, 0.5)
+	require.Equal(t, "31N 448252 5411933", result.String())
+}
+
+func TestPosition_UTM_Equator(t *testing.T) {
+
+	// On the central meridian
+	result, err := NewPosition(3, 0).UTM()
+	require.Nil(t, err)
+	require.Equal(t, UTM{Zone: 31, Hemisphere: UTMHemisphereNorth, Easting: 500000, Northing: 0}, result)
+
+	// On the western edge of zone 31
+	result, err = NewPosition(0, 0).UTM()
+	require.Nil(t, err)
+	require.InDelta(t, 166021.443, result.Easting, 0.01)
+}
+
+func TestPosition_UTM_Southern(t *testing.T) {
+
+	result, err := NewPosition(151.2153, -33.8568).UTM()
+	require.Nil(t, err)
+	require.Equal(t, 56, result.Zone)
+	require.Equal(t, UTMHemisphereSouth, result.Hemisphere)
+	require.Greater(t, result.Northing, 6000000.0)
+
+	position, err := result.Position()
+	require.Nil(t, err)
+	require.InDelta(t, 151.2153, position.Longitude, 0.0000001)
+	require.InDelta(t, -33.8568, position.Latitude, 0.0000001)
+}
+
+func TestPosition_UTM_ZoneExceptions(t *testing.T) {
+
+	// check confirms the zone chosen for a longitude/latitude
+	check := func(longitude float64, latitude float64, expected int) {
+		result, err := NewPosition(longitude, latitude).UTM()
+		require.Nil(t, err)
+		require.Equal(t, expected, result.Zone, "%f,%f", longitude, latitude)
+	}
+
+	// Regular zones
+	check(-180, 0, 1)
+	check(180, 0, 60)
+	check(5, 50, 31)
+
+	// Norway: zone 32V is widened westward
+	check(5, 60, 32)
+	check(2.9, 60, 31)
+	check(5, 64, 31)
+
+	// Svalbard: zones 31X, 33X, 35X, and 37X are widened
+	check(8, 78, 31)
+	check(10, 78, 33)
+	check(20, 78, 33)
+	check(22, 78, 35)
+	check(34, 78, 37)
+	check(43, 78, 38)
+}
+
+func TestPosition_UTM_Errors(t *testing.T) {
+
+	_, err := NewPosition(0, 85).UTM()
+	require.NotNil(t, err)
+
+	_, err = NewPosition(0, -81).UTM()
+	require.NotNil(t, err)
+
+	_, err = NewPosition(200, 0).UTM()
+	require.NotNil(t, err)
+}
+
+func TestUTM_Position(t *testing.T) {
+
+	// Round-trip a variety of positions through UTM
+	for _, position := range []Position{
+		NewPosition(2.2945, 48.8582),
+		NewPosition(-79.9822, 40.4461),
+		NewPosition(-0.1276, 51.5072),
+		NewPosition(5, 60),
+		NewPosition(15.6, 78.2),
+		NewPosition(-70.6693, -33.4489),
+		NewPosition(179.9, -79.9),
+	} {
+		utm, err := position.UTM()
+		require.Nil(t, err)
+
+		result, err := utm.Position()
+		require.Nil(t, err)
+		require.InDelta(t, position.Longitude, result.Longitude, 0.00000001, position.String())
+		require.InDelta(t, position.Latitude, result.Latitude, 0.00000001, position.String())
+	}
+}
+
+func TestUTM_Position_Errors(t *testing.T) {
+
+	checkError := func(utm UTM) {
+		_, err := utm.Position()
+		require.NotNil(t, err, utm)
+	}
+
+	checkError(UTM{Zone: 0, Hemisphere: UTMHemisphereNorth, Easting: 500000, Northing: 0})
+	checkError(UTM{Zone: 61, Hemisphere: UTMHemisphereNorth, Easting: 500000, Northing: 0})
+	checkError(UTM{Zone: 31, Hemisphere: "X", Easting: 500000, Northing: 0})
+	checkError(UTM{Zone: 31, Hemisphere: UTMHemisphereNorth, Easting: 0, Northing: 0})
+	checkError(UTM{Zone: 31, Hemisphere: UTMHemisphereNorth, Easting: 500000, Northing: -1})
+	checkError(UTM{Zone: 31, Hemisphere: UTMHemisphereSouth, Easting: 500000, Northing: 10000001})
+}
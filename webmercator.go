@@ -0,0 +1,76 @@
+package geo
+
+import "math"
+
+// Constants that define the Web Mercator (EPSG:3857) projection
+const (
+	// WebMercatorRadius is the radius (in meters) of the sphere used by Web Mercator
+	WebMercatorRadius = 6378137.0
+
+	// WebMercatorMaxLatitude is the northern (and, negated, the southern) edge
+	// of the Web Mercator world, where the projected map becomes square.
+	WebMercatorMaxLatitude = 85.05112877980659
+
+	// TileSize is the width and height (in pixels) of a standard slippy-map tile
+	TileSize = 256
+)
+
+// WebMercator projects this Position into Web Mercator (EPSG:3857) meters.
+// Latitudes beyond ±WebMercatorMaxLatitude are clamped to the edge of the map.
+func (position Position) WebMercator() (x float64, y float64) {
+
+	latitude := clampLatitude(position.Latitude)
+
+	x = WebMercatorRadius * degreesToRadians(position.Longitude)
+	y = WebMercatorRadius * math.Log(math.Tan(math.Pi/4+degreesToRadians(latitude)/2))
+
+	return x, y
+}
+
+// NewPositionFromWebMercator returns the Position at the given Web Mercator (EPSG:3857) coordinates
+func NewPositionFromWebMercator(x float64, y float64) Position {
+	return NewPosition(
+		radiansToDegrees(x/WebMercatorRadius),
+		radiansToDegrees(2*math.Atan(math.Exp(y/WebMercatorRadius))-math.Pi/2),
+	)
+}
+
+// Pixel returns the global pixel coordinates of this Position at the given zoom
+// level, where the whole world is TileSize * 2^zoom pixels square, and (0,0)
+// is the north-west corner of the map.
+func (position Position) Pixel(zoom int) (x float64, y float64) {
+	worldSize := TileSize * math.Exp2(float64(clampZoom(zoom)))
+	fractionX, fractionY := position.mercatorFraction()
+	return fractionX * worldSize, fractionY * worldSize
+}
+
+// NewPositionFromPixel returns the Position at the given global pixel coordinates and zoom level
+func NewPositionFromPixel(x float64, y float64, zoom int) Position {
+	worldSize := TileSize * math.Exp2(float64(clampZoom(zoom)))
+	return positionFromMercatorFraction(x/worldSize, y/worldSize)
+}
+
+// mercatorFraction returns the position of this Position on a unit-square Web Mercator
+// map, where (0,0) is the north-west corner and (1,1) is the south-east corner
+func (position Position) mercatorFraction() (float64, float64) {
+
+	latitude := degreesToRadians(clampLatitude(position.Latitude))
+
+	x := (position.Longitude + 180) / 360
+	y := (1 - math.Log(math.Tan(latitude)+1/math.Cos(latitude))/math.Pi) / 2
+
+	return x, y
+}
+
+// positionFromMercatorFraction reverses mercatorFraction
+func positionFromMercatorFraction(x float64, y float64) Position {
+	return NewPosition(
+		x*360-180,
+		radiansToDegrees(math.Atan(math.Sinh(math.Pi*(1-2*y)))),
+	)
+}
+
+// clampLatitude limits a latitude to the edges of the Web Mercator map
+func clampLatitude(latitude float64) float64 {
+	return max(-WebMercatorMaxLatitude, min(latitude, WebMercatorMaxLatitude))
+}
@@ -0,0 +1,41 @@
+package geo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPosition_WebMercator(t *testing.T) {
+
+	x, y := NewPosition(-122.4194, 37.7749).WebMercator()
+	require.InDelta(t, -13627665.271, x, 0.001)
+	require.InDelta(t, 4547675.354, y, 0.001)
+
+	// The corners of the map
+	x, y = NewPosition(180, 90).WebMercator()
+	require.InDelta(t, 20037508.343, x, 0.001)
+	require.InDelta(t, 20037508.343, y, 0.001)
+}
+
+func TestNewPositionFromWebMercator(t *testing.T) {
+
+	result := NewPositionFromWebMercator(-13627665.271, 4547675.354)
+	require.InDelta(t, -122.4194, result.Longitude, 0.0000001)
+	require.InDelta(t, 37.7749, result.Latitude, 0.0000001)
+}
+
+func TestPosition_Pixel(t *testing.T) {
+
+	// The center of the world at zoom 0
+	x, y := NewPosition(0, 0).Pixel(0)
+	require.InDelta(t, 128, x, 0.0000001)
+	require.InDelta(t, 128, y, 0.0000001)
+
+	// Round-trip at a deep zoom
+	position := NewPosition(-79.9822, 40.4461)
+	x, y = position.Pixel(18)
+	result := NewPositionFromPixel(x, y, 18)
+	require.InDelta(t, position.Longitude, result.Longitude, 0.0000001)
+	require.InDelta(t, position.Latitude, result.Latitude, 0.0000001)
+}